@@ -0,0 +1,182 @@
+package markdownchunker
+
+import "fmt"
+
+// flattenAdaptive 按大小窗口自适应地扁平化层级结构
+// 合并后超过 MaxChunkSize 的章节下钻：标题及其直属内容单独作为一个单元，子章节递归处理；
+// 同一父章节下小于 MinChunkSize 的相邻单元合并，合并结果不超过 MaxChunkSize
+func (s *HierarchicalStrategy) flattenAdaptive(hierarchicalChunks []*HierarchicalChunk, chunker *MarkdownChunker) []Chunk {
+	units := s.adaptiveUnits(hierarchicalChunks, nil, chunker)
+
+	var result []Chunk
+	for _, unit := range units {
+		chunk := s.createMergedChunk(unit, len(result))
+		if chunk == nil {
+			continue
+		}
+		// 虚拟节点承载的合并单元没有自己的标题，使用所在章节的章节信息
+		if count, ok := unit.Chunk.Metadata["merged_sections"]; ok && s.isVirtualChunk(unit) {
+			chunk.Metadata["merged_sections"] = count
+			if unit.Parent != nil && !s.isVirtualChunk(unit.Parent) {
+				copySectionInfo(chunk, unit.Parent.Chunk)
+			}
+		}
+		result = append(result, *chunk)
+	}
+	return result
+}
+
+// adaptiveUnits 将 parent 下的同级节点转换为合并单元
+// 大小在上限内的相邻节点可以互相合并；超出上限的节点下钻，其结果不再与同级节点合并，
+// 因此合并不会跨越章节边界
+func (s *HierarchicalStrategy) adaptiveUnits(nodes []*HierarchicalChunk, parent *HierarchicalChunk, chunker *MarkdownChunker) []*HierarchicalChunk {
+	var units []*HierarchicalChunk
+	var run []*HierarchicalChunk
+	for _, node := range nodes {
+		if s.isVirtualChunk(node) && !s.hasNonVirtualContent(node) {
+			continue
+		}
+
+		if s.config.MaxChunkSize <= 0 || s.unitSize(node, chunker) <= s.config.MaxChunkSize {
+			run = append(run, node)
+			continue
+		}
+
+		section, subsections := s.splitSectionChildren(node)
+		if len(subsections) == 0 {
+			// 没有子章节可下钻，超大内容交给分块器的超大块处理
+			run = append(run, node)
+			continue
+		}
+
+		units = append(units, s.mergeSmallUnits(run, parent, chunker)...)
+		run = nil
+
+		// 标题与直属内容作为本章节下的第一个单元，可以与较小的子章节合并
+		children := subsections
+		if !s.isVirtualChunk(node) {
+			children = append([]*HierarchicalChunk{section}, subsections...)
+		}
+		units = append(units, s.adaptiveUnits(children, node, chunker)...)
+	}
+
+	return append(units, s.mergeSmallUnits(run, parent, chunker)...)
+}
+
+// splitSectionChildren 将层级块拆分为仅包含直属内容的章节块和子章节列表
+func (s *HierarchicalStrategy) splitSectionChildren(hChunk *HierarchicalChunk) (*HierarchicalChunk, []*HierarchicalChunk) {
+	section := &HierarchicalChunk{
+		Chunk:    hChunk.Chunk,
+		Children: []*HierarchicalChunk{},
+		Parent:   hChunk.Parent,
+		Level:    hChunk.Level,
+		Virtual:  hChunk.Virtual,
+	}
+
+	var subsections []*HierarchicalChunk
+	for _, child := range hChunk.Children {
+		if child.Chunk.Type == "heading" || s.isVirtualChunk(child) {
+			subsections = append(subsections, child)
+		} else {
+			section.Children = append(section.Children, child)
+		}
+	}
+
+	return section, subsections
+}
+
+// mergeSmallUnits 贪心合并相邻的小单元，任一方小于 MinChunkSize 且合并后不超过 MaxChunkSize 时合并
+// parent 为这些单元所在的章节，合并后没有标题的单元沿用其章节信息
+func (s *HierarchicalStrategy) mergeSmallUnits(units []*HierarchicalChunk, parent *HierarchicalChunk, chunker *MarkdownChunker) []*HierarchicalChunk {
+	if s.config.MinChunkSize <= 0 || len(units) < 2 {
+		return units
+	}
+
+	var result []*HierarchicalChunk
+	var group []*HierarchicalChunk
+	groupSize := 0
+
+	// 合并内容以空行连接，合并后的大小由各单元大小累加，无需重新构建合并内容
+	separatorSize := measureChunkSize(chunker, "\n\n")
+
+	flush := func() {
+		if len(group) > 0 {
+			result = append(result, s.groupUnits(group, parent))
+		}
+		group = nil
+		groupSize = 0
+	}
+
+	for _, unit := range units {
+		size := s.unitSize(unit, chunker)
+		if len(group) > 0 {
+			combined := groupSize + separatorSize + size
+			small := groupSize < s.config.MinChunkSize || size < s.config.MinChunkSize
+			fits := s.config.MaxChunkSize <= 0 || combined <= s.config.MaxChunkSize
+			if small && fits {
+				group = append(group, unit)
+				groupSize = combined
+				continue
+			}
+			flush()
+		}
+		group = append(group, unit)
+		groupSize = size
+	}
+	flush()
+
+	return result
+}
+
+// groupUnits 将多个单元组合为一个层级块
+// 第一个单元是父章节的标题和直属内容时，其余单元挂在该标题下；
+// 否则使用虚拟节点承载，并沿用父章节的章节信息
+func (s *HierarchicalStrategy) groupUnits(group []*HierarchicalChunk, parent *HierarchicalChunk) *HierarchicalChunk {
+	if len(group) == 1 {
+		return group[0]
+	}
+
+	first := group[0]
+	if parent != nil && !s.isVirtualChunk(parent) && !s.isVirtualChunk(first) && first.Chunk.ID == parent.Chunk.ID {
+		grouped := &HierarchicalChunk{
+			Chunk:  first.Chunk,
+			Parent: first.Parent,
+			Level:  first.Level,
+		}
+		grouped.Children = append(grouped.Children, first.Children...)
+		grouped.Children = append(grouped.Children, group[1:]...)
+		grouped.Chunk.Metadata = s.groupMetadata(first.Chunk.Metadata, len(group))
+		return grouped
+	}
+
+	return &HierarchicalChunk{
+		Chunk: Chunk{
+			ID:       -1,
+			Type:     "heading",
+			Metadata: map[string]string{"virtual": "true", "merged_sections": fmt.Sprintf("%d", len(group))},
+		},
+		Children: group,
+		Parent:   parent,
+		Level:    first.Level,
+		Virtual:  true,
+	}
+}
+
+// groupMetadata 复制元数据并记录合并的单元数
+func (s *HierarchicalStrategy) groupMetadata(metadata map[string]string, count int) map[string]string {
+	grouped := make(map[string]string, len(metadata)+1)
+	for key, value := range metadata {
+		grouped[key] = value
+	}
+	grouped["merged_sections"] = fmt.Sprintf("%d", count)
+	return grouped
+}
+
+// unitSize 返回单元合并后的内容大小
+func (s *HierarchicalStrategy) unitSize(unit *HierarchicalChunk, chunker *MarkdownChunker) int {
+	chunk := s.createMergedChunk(unit, 0)
+	if chunk == nil {
+		return 0
+	}
+	return measureChunkSize(chunker, chunk.Content)
+}
@@ -0,0 +1,73 @@
+package markdownchunker
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestHierarchicalAdaptive_DescendsAndMerges(t *testing.T) {
+	markdown := "# Install\n\n" + strings.Repeat("Intro text here. ", 8) +
+		"\n\n## Linux\n\nRun it.\n\n## macOS\n\nBrew it.\n\n## Windows\n\n" + strings.Repeat("Long windows instructions. ", 10) +
+		"\n\n# Usage\n\nCall the API.\n\n# FAQ\n\nNone."
+
+	chunks := chunkWithStrategyConfig(t, HierarchicalConfigAdaptive(60, 300), markdown)
+	if len(chunks) != 3 {
+		t.Fatalf("Expected 3 chunks, got %d: %+v", len(chunks), chunks)
+	}
+
+	// 超大的 Install 章节下钻，较小的 Linux 和 macOS 与章节导语合并
+	install := chunks[0]
+	if !strings.HasPrefix(install.Content, "# Install") || !strings.Contains(install.Content, "## macOS") ||
+		strings.Contains(install.Content, "## Windows") {
+		t.Errorf("Unexpected install chunk content %q", install.Content)
+	}
+	if install.Metadata["merged_sections"] != "3" {
+		t.Errorf("Expected 3 merged sections, got %q", install.Metadata["merged_sections"])
+	}
+
+	if chunks[1].Text[:7] != "Windows" || strings.Join(chunks[1].SectionPath, " > ") != "Install > Windows" {
+		t.Errorf("Expected Windows subsection on its own, got %+v", chunks[1])
+	}
+
+	// 较小的同级顶层章节合并，但不会并入已下钻的 Install 章节
+	if chunks[2].Text != "Usage Call the API. FAQ None." || chunks[2].Metadata["merged_sections"] != "2" {
+		t.Errorf("Expected Usage and FAQ to be merged, got %+v", chunks[2])
+	}
+
+	for _, chunk := range chunks {
+		if len(chunk.Content) > 300 {
+			t.Errorf("Expected chunk within 300 bytes, got %d", len(chunk.Content))
+		}
+	}
+}
+
+func TestHierarchicalAdaptive_MergedSiblingsKeepParentSection(t *testing.T) {
+	markdown := "# Install\n\n" + strings.Repeat("Intro text here. ", 12) +
+		"\n\n## Linux\n\nRun it.\n\n## macOS\n\nBrew it."
+
+	chunks := chunkWithStrategyConfig(t, HierarchicalConfigAdaptive(60, 210), markdown)
+	if len(chunks) != 2 {
+		t.Fatalf("Expected 2 chunks, got %d: %+v", len(chunks), chunks)
+	}
+	if chunks[0].Type != "heading" || strings.Contains(chunks[0].Content, "##") {
+		t.Errorf("Expected the intro to stay alone, got %q", chunks[0].Content)
+	}
+
+	siblings := chunks[1]
+	if siblings.Text != "Linux Run it. macOS Brew it." {
+		t.Errorf("Expected Linux and macOS to be merged, got %q", siblings.Text)
+	}
+	if strings.Join(siblings.SectionPath, " > ") != "Install" || siblings.Metadata["anchor"] != "install" {
+		t.Errorf("Expected merged siblings to carry the parent section, got %v %q", siblings.SectionPath, siblings.Metadata["anchor"])
+	}
+}
+
+func TestHierarchicalAdaptive_ConfigFromParameters(t *testing.T) {
+	config, err := CreateConfigFromParameters("hierarchical", map[string]any{"adaptive": true})
+	if err != nil {
+		t.Fatalf("CreateConfigFromParameters() error = %v", err)
+	}
+	if !config.Adaptive || !config.Clone().Adaptive {
+		t.Error("Expected adaptive flag to be parsed and cloned")
+	}
+}
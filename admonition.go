@@ -0,0 +1,348 @@
+package markdownchunker
+
+import (
+	"bytes"
+	"html"
+	"regexp"
+	"strings"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/renderer"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
+)
+
+// 提示块语法
+const (
+	// AdmonitionSyntaxGitHub GitHub 提示：> [!NOTE]
+	AdmonitionSyntaxGitHub = "github"
+	// AdmonitionSyntaxMkDocs MkDocs 提示：!!! note "Title"，内容缩进4个空格
+	AdmonitionSyntaxMkDocs = "mkdocs"
+	// AdmonitionSyntaxDocusaurus Docusaurus 提示：:::note[Title] ... :::
+	AdmonitionSyntaxDocusaurus = "docusaurus"
+)
+
+// KindAdmonition 提示块的节点类型
+var KindAdmonition = ast.NewNodeKind("Admonition")
+
+var (
+	// githubAlertPattern 匹配 GitHub 提示的首行标记
+	githubAlertPattern = regexp.MustCompile(`(?i)^\[!(note|tip|important|warning|caution)\]$`)
+	// githubAlertMarkerPattern 匹配纯文本开头的 GitHub 提示标记
+	githubAlertMarkerPattern = regexp.MustCompile(`(?i)^\s*\[!(note|tip|important|warning|caution)\]\s*`)
+	// mkdocsAdmonitionPattern 匹配 MkDocs 提示的开始行，类型之后只能是可选的引号标题，??? 和 ???+ 为可折叠提示
+	mkdocsAdmonitionPattern = regexp.MustCompile(`^(?:!!!|\?\?\?\+?)[ \t]+([a-z][\w-]*)(?:[ \t]+"([^"]*)")?[ \t]*$`)
+	// docusaurusAdmonitionPattern 匹配 Docusaurus 提示的开始行，标题可写在 [] 中或类型之后
+	docusaurusAdmonitionPattern = regexp.MustCompile(`^(:{3,})[ \t]*([A-Za-z][\w-]*)(?:\[(.*)\]|[ \t]+(.*))?$`)
+)
+
+// admonitionKinds 提示类型到归一化类别（note/tip/warning/danger）的映射，未列出的类型归为 note
+var admonitionKinds = map[string]string{
+	"tip": "tip", "hint": "tip", "success": "tip", "check": "tip", "done": "tip",
+	"warning": "warning", "caution": "warning", "attention": "warning", "important": "warning",
+	"danger": "danger", "error": "danger", "failure": "danger", "fail": "danger",
+	"missing": "danger", "bug": "danger",
+}
+
+// mkdocsAdmonitionTypes MkDocs Material 支持的提示类型，其他写法（如 !!! Important announcement）按普通文本处理
+var mkdocsAdmonitionTypes = map[string]bool{
+	"note": true, "abstract": true, "summary": true, "tldr": true, "info": true, "todo": true,
+	"tip": true, "hint": true, "important": true, "success": true, "check": true, "done": true,
+	"question": true, "help": true, "faq": true, "warning": true, "caution": true, "attention": true,
+	"failure": true, "fail": true, "missing": true, "danger": true, "error": true, "bug": true,
+	"example": true, "quote": true, "cite": true,
+}
+
+// Admonition 提示块，子节点为提示内容
+// GitHub 提示的唯一子节点是原始引用块，其余语法的 Lines 为包括开始和结束行在内的原始行
+type Admonition struct {
+	ast.BaseBlock
+	AdmonitionType string // 书写的提示类型（小写），如 note、caution、hint
+	Title          string // 可选标题
+	Syntax         string // 提示语法
+	fence          int    // Docusaurus 开始分隔符的冒号数量
+	closed         bool
+}
+
+// NewAdmonition 创建提示块节点
+func NewAdmonition(admonitionType, title, syntax string) *Admonition {
+	return &Admonition{
+		AdmonitionType: strings.ToLower(admonitionType),
+		Title:          strings.TrimSpace(title),
+		Syntax:         syntax,
+	}
+}
+
+// Kind 返回节点类型
+func (n *Admonition) Kind() ast.NodeKind {
+	return KindAdmonition
+}
+
+// IsRaw 提示块自身的行是原始行，不进行行内解析，内容由子节点承载
+func (n *Admonition) IsRaw() bool {
+	return true
+}
+
+// Dump 输出节点调试信息
+func (n *Admonition) Dump(source []byte, level int) {
+	ast.DumpHelper(n, source, level, map[string]string{
+		"AdmonitionType": n.AdmonitionType,
+		"Title":          n.Title,
+		"Syntax":         n.Syntax,
+	}, nil)
+}
+
+// admonitionKind 返回提示类型的归一化类别
+func admonitionKind(admonitionType string) string {
+	if kind, ok := admonitionKinds[strings.ToLower(admonitionType)]; ok {
+		return kind
+	}
+	return "note"
+}
+
+// admonitionParser 解析 MkDocs 和 Docusaurus 提示块
+type admonitionParser struct{}
+
+// Trigger 触发字符
+func (b *admonitionParser) Trigger() []byte {
+	return []byte{'!', '?', ':'}
+}
+
+// Open 识别提示块的开始行，开始行之后的内容作为子节点解析
+func (b *admonitionParser) Open(parent ast.Node, reader text.Reader, pc parser.Context) (ast.Node, parser.State) {
+	line, segment := reader.PeekLine()
+	pos := pc.BlockOffset()
+	if pos < 0 {
+		return nil, parser.NoChildren
+	}
+	opener := string(util.TrimRightSpace(line[pos:]))
+
+	var node *Admonition
+	if match := mkdocsAdmonitionPattern.FindStringSubmatch(opener); match != nil && mkdocsAdmonitionTypes[match[1]] {
+		node = NewAdmonition(match[1], match[2], AdmonitionSyntaxMkDocs)
+	} else if match := docusaurusAdmonitionPattern.FindStringSubmatch(opener); match != nil &&
+		hasDocusaurusCloser(reader.Source(), segment.Stop, len(match[1])) {
+		node = NewAdmonition(match[2], match[3]+match[4], AdmonitionSyntaxDocusaurus)
+		node.fence = len(match[1])
+	} else {
+		return nil, parser.NoChildren
+	}
+
+	node.Lines().Append(segment)
+	reader.AdvanceToEOL()
+	return node, parser.NoChildren
+}
+
+// hasDocusaurusCloser 判断从 offset 开始是否有至少 fence 个冒号的结束分隔符行
+// 没有结束分隔符的 ::: 行按普通文本处理，避免吞掉文档的其余部分
+func hasDocusaurusCloser(source []byte, offset, fence int) bool {
+	for offset < len(source) {
+		line := source[offset:]
+		if end := bytes.IndexByte(line, '\n'); end >= 0 {
+			line = line[:end+1]
+		}
+		trimmed := bytes.TrimSpace(line)
+		if len(trimmed) >= fence && len(bytes.Trim(trimmed, ":")) == 0 {
+			return true
+		}
+		offset += len(line)
+	}
+	return false
+}
+
+// Continue MkDocs 提示收集缩进至少4个空格的行，Docusaurus 提示收集到结束分隔符为止
+func (b *admonitionParser) Continue(node ast.Node, reader text.Reader, pc parser.Context) parser.State {
+	admonition := node.(*Admonition)
+	if admonition.closed {
+		return parser.Close
+	}
+
+	line, segment := reader.PeekLine()
+
+	if admonition.Syntax == AdmonitionSyntaxDocusaurus {
+		trimmed := bytes.TrimSpace(line)
+		if len(trimmed) >= admonition.fence && len(bytes.Trim(trimmed, ":")) == 0 {
+			node.Lines().Append(segment)
+			admonition.closed = true
+			reader.AdvanceToEOL()
+			return parser.Close
+		}
+		node.Lines().Append(segment)
+		return parser.Continue | parser.HasChildren
+	}
+
+	if util.IsBlank(line) {
+		node.Lines().Append(segment)
+		reader.AdvanceToEOL()
+		return parser.Continue | parser.HasChildren
+	}
+
+	indent, _ := util.IndentWidth(line, reader.LineOffset())
+	if indent < 4 {
+		return parser.Close
+	}
+	node.Lines().Append(segment)
+	pos, padding := util.IndentPosition(line, reader.LineOffset(), 4)
+	reader.AdvanceAndSetPadding(pos, padding)
+	return parser.Continue | parser.HasChildren
+}
+
+// Close 去除末尾的空行，使位置信息只覆盖提示内容
+func (b *admonitionParser) Close(node ast.Node, reader text.Reader, pc parser.Context) {
+	lines := node.Lines()
+	for lines.Len() > 1 {
+		last := lines.At(lines.Len() - 1)
+		if !util.IsBlank(last.Value(reader.Source())) {
+			break
+		}
+		lines.SetSliced(0, lines.Len()-1)
+	}
+}
+
+// CanInterruptParagraph 提示块可以打断段落
+func (b *admonitionParser) CanInterruptParagraph() bool {
+	return true
+}
+
+// CanAcceptIndentedLine 缩进行不作为提示块开始
+func (b *admonitionParser) CanAcceptIndentedLine() bool {
+	return false
+}
+
+// githubAlertTransformer 将首行为 [!NOTE] 等标记的引用块转换为提示块
+type githubAlertTransformer struct{}
+
+// Transform 替换文档中的 GitHub 提示引用块，原引用块作为提示块的子节点保留
+func (t *githubAlertTransformer) Transform(doc *ast.Document, reader text.Reader, pc parser.Context) {
+	source := reader.Source()
+
+	var alerts []*ast.Blockquote
+	var types []string
+	ast.Walk(doc, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		quote, ok := n.(*ast.Blockquote)
+		if !entering || !ok {
+			return ast.WalkContinue, nil
+		}
+		if admonitionType, ok := githubAlertType(quote, source); ok {
+			alerts = append(alerts, quote)
+			types = append(types, admonitionType)
+		}
+		return ast.WalkContinue, nil
+	})
+
+	for i, quote := range alerts {
+		admonition := NewAdmonition(types[i], "", AdmonitionSyntaxGitHub)
+
+		// 用第一个和最后一个有行信息的后代节点确定提示块的位置
+		var first, last ast.Node
+		ast.Walk(quote, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+			if entering && n.Type() == ast.TypeBlock && n.Lines().Len() > 0 {
+				if first == nil {
+					first = n
+				}
+				last = n
+			}
+			return ast.WalkContinue, nil
+		})
+		if first != nil {
+			admonition.Lines().Append(first.Lines().At(0))
+			admonition.Lines().Append(last.Lines().At(last.Lines().Len() - 1))
+		}
+
+		quote.Parent().ReplaceChild(quote.Parent(), quote, admonition)
+		admonition.AppendChild(admonition, quote)
+	}
+}
+
+// githubAlertType 判断引用块是否为 GitHub 提示，返回提示类型
+// 标记必须单独占据第一行，并且之后还有内容
+func githubAlertType(quote *ast.Blockquote, source []byte) (string, bool) {
+	para, ok := quote.FirstChild().(*ast.Paragraph)
+	if !ok || para.Lines().Len() == 0 {
+		return "", false
+	}
+	if para.Lines().Len() == 1 && para.NextSibling() == nil {
+		return "", false
+	}
+
+	firstLine := para.Lines().At(0)
+	match := githubAlertPattern.FindSubmatch(bytes.TrimSpace(firstLine.Value(source)))
+	if match == nil {
+		return "", false
+	}
+	return strings.ToLower(string(match[1])), true
+}
+
+// admonitionHTMLRenderer 将提示块渲染为带类型样式的 div
+type admonitionHTMLRenderer struct{}
+
+// RegisterFuncs 注册渲染函数
+func (r *admonitionHTMLRenderer) RegisterFuncs(reg renderer.NodeRendererFuncRegisterer) {
+	reg.Register(KindAdmonition, r.renderAdmonition)
+}
+
+func (r *admonitionHTMLRenderer) renderAdmonition(w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	admonition := node.(*Admonition)
+	if entering {
+		_, _ = w.WriteString(`<div class="admonition ` + html.EscapeString(admonition.AdmonitionType) + `">` + "\n")
+		if admonition.Title != "" {
+			_, _ = w.WriteString(`<p class="admonition-title">` + html.EscapeString(admonition.Title) + "</p>\n")
+		}
+	} else {
+		_, _ = w.WriteString("</div>\n")
+	}
+	return ast.WalkContinue, nil
+}
+
+// admonitionExtension 为 goldmark 添加 GitHub、MkDocs 和 Docusaurus 提示块支持
+type admonitionExtension struct{}
+
+// Extend 注册提示块解析器、转换器和渲染器
+func (e *admonitionExtension) Extend(m goldmark.Markdown) {
+	m.Parser().AddOptions(
+		parser.WithBlockParsers(util.Prioritized(&admonitionParser{}, 780)),
+		parser.WithASTTransformers(util.Prioritized(&githubAlertTransformer{}, 500)),
+	)
+	m.Renderer().AddOptions(
+		renderer.WithNodeRenderers(util.Prioritized(&admonitionHTMLRenderer{}, 500)),
+	)
+}
+
+// getAdmonitionContent 返回提示块的原始 markdown 内容
+func (c *MarkdownChunker) getAdmonitionContent(block *Admonition) string {
+	if block.Syntax == AdmonitionSyntaxGitHub {
+		if quote, ok := block.FirstChild().(*ast.Blockquote); ok {
+			return c.reconstructBlockquote(quote)
+		}
+	}
+
+	var buf strings.Builder
+	for i := 0; i < block.Lines().Len(); i++ {
+		line := block.Lines().At(i)
+		buf.Write(line.Value(c.source))
+	}
+	return strings.TrimRight(buf.String(), "\r\n")
+}
+
+// getAdmonitionText 返回提示块内容的纯文本，不包括提示标记，块之间用空格分隔
+func (c *MarkdownChunker) getAdmonitionText(block *Admonition) string {
+	var container ast.Node = block
+	if quote, ok := block.FirstChild().(*ast.Blockquote); ok && block.Syntax == AdmonitionSyntaxGitHub {
+		container = quote
+	}
+
+	var parts []string
+	for child := container.FirstChild(); child != nil; child = child.NextSibling() {
+		if text := c.getNodeText(child); text != "" {
+			parts = append(parts, text)
+		}
+	}
+
+	text := strings.Join(parts, " ")
+	if block.Syntax == AdmonitionSyntaxGitHub {
+		text = githubAlertMarkerPattern.ReplaceAllString(text, "")
+	}
+	return strings.TrimSpace(text)
+}
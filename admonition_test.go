@@ -0,0 +1,195 @@
+package markdownchunker
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestAdmonition_GitHubAlert(t *testing.T) {
+	markdown := `> [!WARNING]
+> Rotating the key **invalidates** all sessions.
+> See [the guide](https://example.com/keys).
+
+> Just a regular quote.`
+
+	chunker := NewMarkdownChunker()
+	chunks, err := chunker.ChunkDocument([]byte(markdown))
+	if err != nil {
+		t.Fatalf("ChunkDocument() error = %v", err)
+	}
+	if len(chunks) != 2 {
+		t.Fatalf("Expected 2 chunks, got %d: %+v", len(chunks), chunks)
+	}
+
+	alert := chunks[0]
+	if alert.Type != "admonition" {
+		t.Fatalf("Expected admonition chunk, got %s", alert.Type)
+	}
+	if !strings.HasPrefix(alert.Content, "> [!WARNING]\n> Rotating") {
+		t.Errorf("Expected content to keep the alert markdown, got %q", alert.Content)
+	}
+	if alert.Text != "Rotating the key invalidates all sessions. See the guide." {
+		t.Errorf("Expected marker to be stripped from text, got %q", alert.Text)
+	}
+	if alert.Metadata["admonition_kind"] != "warning" || alert.Metadata["admonition_type"] != "warning" {
+		t.Errorf("Unexpected kind metadata %+v", alert.Metadata)
+	}
+	if alert.Metadata["admonition_syntax"] != AdmonitionSyntaxGitHub {
+		t.Errorf("Expected github syntax, got %q", alert.Metadata["admonition_syntax"])
+	}
+	if _, ok := alert.Metadata["admonition_title"]; ok {
+		t.Error("Expected GitHub alert to have no title")
+	}
+	if len(alert.Links) != 1 || alert.Links[0].URL != "https://example.com/keys" {
+		t.Errorf("Expected link inside alert to be extracted, got %+v", alert.Links)
+	}
+	if alert.Position.StartLine != 1 || alert.Position.EndLine != 3 {
+		t.Errorf("Expected alert to span lines 1-3, got %+v", alert.Position)
+	}
+
+	if chunks[1].Type != "blockquote" {
+		t.Errorf("Expected regular quote to stay a blockquote, got %s", chunks[1].Type)
+	}
+}
+
+func TestAdmonition_MkDocs(t *testing.T) {
+	markdown := `!!! danger "Data loss"
+    Dropping the table deletes every row.
+
+    There is no undo.
+
+??? hint
+    Collapsible hint body.
+
+Back to normal text.`
+
+	chunker := NewMarkdownChunker()
+	chunks, err := chunker.ChunkDocument([]byte(markdown))
+	if err != nil {
+		t.Fatalf("ChunkDocument() error = %v", err)
+	}
+	if len(chunks) != 3 {
+		t.Fatalf("Expected 3 chunks, got %d: %+v", len(chunks), chunks)
+	}
+
+	danger := chunks[0]
+	if danger.Type != "admonition" || danger.Metadata["admonition_kind"] != "danger" {
+		t.Fatalf("Expected danger admonition, got %+v", danger)
+	}
+	if danger.Metadata["admonition_title"] != "Data loss" {
+		t.Errorf("Expected title 'Data loss', got %q", danger.Metadata["admonition_title"])
+	}
+	if danger.Text != "Data loss Dropping the table deletes every row. There is no undo." {
+		t.Errorf("Unexpected text %q", danger.Text)
+	}
+	if danger.Position.StartLine != 1 || danger.Position.EndLine != 4 {
+		t.Errorf("Expected admonition to span lines 1-4, got %+v", danger.Position)
+	}
+
+	hint := chunks[1]
+	if hint.Metadata["admonition_type"] != "hint" || hint.Metadata["admonition_kind"] != "tip" {
+		t.Errorf("Expected hint to be normalised to tip, got %+v", hint.Metadata)
+	}
+	if hint.Text != "Collapsible hint body." {
+		t.Errorf("Unexpected text %q", hint.Text)
+	}
+
+	if chunks[2].Type != "paragraph" || chunks[2].Text != "Back to normal text." {
+		t.Errorf("Expected unindented text to end the admonition, got %+v", chunks[2])
+	}
+}
+
+func TestAdmonition_Docusaurus(t *testing.T) {
+	markdown := `:::caution[Before upgrading]
+Back up your database.
+
+- Stop the service
+- Run the migration
+:::
+
+:::note
+Plain note.
+:::`
+
+	chunker := NewMarkdownChunker()
+	chunks, err := chunker.ChunkDocument([]byte(markdown))
+	if err != nil {
+		t.Fatalf("ChunkDocument() error = %v", err)
+	}
+	if len(chunks) != 2 {
+		t.Fatalf("Expected 2 chunks, got %d: %+v", len(chunks), chunks)
+	}
+
+	caution := chunks[0]
+	if caution.Metadata["admonition_type"] != "caution" || caution.Metadata["admonition_kind"] != "warning" {
+		t.Errorf("Unexpected kind metadata %+v", caution.Metadata)
+	}
+	if caution.Metadata["admonition_title"] != "Before upgrading" {
+		t.Errorf("Unexpected title %q", caution.Metadata["admonition_title"])
+	}
+	if !strings.HasPrefix(caution.Text, "Before upgrading Back up your database.") {
+		t.Errorf("Unexpected text %q", caution.Text)
+	}
+	if !strings.HasSuffix(caution.Content, "- Run the migration\n:::") {
+		t.Errorf("Expected content to include the closing fence, got %q", caution.Content)
+	}
+	if caution.Position.StartLine != 1 || caution.Position.EndLine != 6 {
+		t.Errorf("Expected admonition to span lines 1-6, got %+v", caution.Position)
+	}
+
+	note := chunks[1]
+	if note.Metadata["admonition_kind"] != "note" || note.Text != "Plain note." {
+		t.Errorf("Unexpected note chunk %+v", note)
+	}
+}
+
+func TestAdmonition_NotAdmonitions(t *testing.T) {
+	tests := []struct {
+		name     string
+		markdown string
+		types    string
+	}{
+		{
+			name:     "unclosed docusaurus fence",
+			markdown: ":::note\nThis note is never closed.\n\n## Next\n\nSome paragraph.\n\n```go\nfunc main() {}\n```",
+			types:    "paragraph,heading,paragraph,code",
+		},
+		{
+			name:     "mkdocs marker followed by prose",
+			markdown: "!!! Important announcement\n\n!!! note this is not a title\n\nRegular text.",
+			types:    "paragraph,paragraph,paragraph",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			chunker := NewMarkdownChunker()
+			chunks, err := chunker.ChunkDocument([]byte(tt.markdown))
+			if err != nil {
+				t.Fatalf("ChunkDocument() error = %v", err)
+			}
+
+			var types []string
+			for _, chunk := range chunks {
+				types = append(types, chunk.Type)
+			}
+			if strings.Join(types, ",") != tt.types {
+				t.Errorf("Expected chunk types %s, got %v", tt.types, types)
+			}
+		})
+	}
+}
+
+func TestAdmonition_DisabledThroughEnabledTypes(t *testing.T) {
+	config := DefaultConfig()
+	config.EnabledTypes = map[string]bool{"paragraph": true, "admonition": false}
+
+	chunker := NewMarkdownChunkerWithConfig(config)
+	chunks, err := chunker.ChunkDocument([]byte(":::tip\nHidden.\n:::\n\nVisible."))
+	if err != nil {
+		t.Fatalf("ChunkDocument() error = %v", err)
+	}
+	if len(chunks) != 1 || chunks[0].Text != "Visible." {
+		t.Errorf("Expected only the paragraph, got %+v", chunks)
+	}
+}
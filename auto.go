@@ -0,0 +1,299 @@
+package markdownchunker
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/yuin/goldmark/ast"
+)
+
+// 内置选择规则的阈值
+const (
+	// AutoShortDocumentWords 短文档的最大词数，不超过该词数且最多一个标题的文档作为整体处理
+	AutoShortDocumentWords = 200
+	// AutoStructuredMinHeadings 结构化文档的最少标题数，同时要求至少有两级标题
+	AutoStructuredMinHeadings = 3
+	// AutoPackTargetSize 扁平文档使用元素级策略时的打包目标大小
+	AutoPackTargetSize = 500
+)
+
+// DocumentProfile 自动策略用于选择分块策略的文档特征
+// 统计数据来自文档级策略的 extractDocumentMetadata
+type DocumentProfile struct {
+	Size            int    // 文档大小（按分块器的 SizeUnit 计量，不含前置元数据）
+	WordCount       int    // 词数（不含代码）
+	HeadingCount    int    // 标题数
+	MaxHeadingLevel int    // 最深的标题层级
+	ParagraphCount  int    // 段落数
+	CodeBlockCount  int    // 代码块数
+	TableCount      int    // 表格数
+	ListCount       int    // 列表数
+	Complexity      string // 文档复杂度：simple、moderate、complex、very_complex
+
+	Metadata map[string]string // extractDocumentMetadata 的完整结果，包括前置元数据
+}
+
+// StrategySelectionRule 自动策略的选择规则
+// 规则按顺序匹配，第一个匹配的规则决定使用的策略
+type StrategySelectionRule struct {
+	Name     string                              // 规则名称，记录在 selection_rule 元数据中
+	Match    func(profile *DocumentProfile) bool // 匹配条件
+	Strategy string                              // 选用的策略名称，必须已注册
+	Config   *StrategyConfig                     // 选用策略的配置，nil 时使用已注册策略的配置
+	Reason   string                              // 选择原因，记录在 selection_reason 元数据中
+}
+
+// Validate 验证选择规则
+func (r StrategySelectionRule) Validate() error {
+	if r.Name == "" {
+		return NewChunkerError(ErrorTypeStrategyConfigInvalid, "选择规则名称不能为空", nil).
+			WithContext("function", "Validate")
+	}
+
+	if r.Match == nil {
+		return NewChunkerError(ErrorTypeStrategyConfigInvalid, "选择规则条件不能为空", nil).
+			WithContext("function", "Validate").
+			WithContext("rule_name", r.Name)
+	}
+
+	if r.Strategy == "" || r.Strategy == "auto" {
+		return NewChunkerError(ErrorTypeStrategyConfigInvalid, "选择规则必须指向自动策略以外的策略", nil).
+			WithContext("function", "Validate").
+			WithContext("rule_name", r.Name).
+			WithContext("strategy", r.Strategy)
+	}
+
+	if r.Config != nil && r.Config.Name != r.Strategy {
+		return NewChunkerError(ErrorTypeStrategyConfigInvalid, "选择规则的配置与策略名称不匹配", nil).
+			WithContext("function", "Validate").
+			WithContext("rule_name", r.Name).
+			WithContext("strategy", r.Strategy).
+			WithContext("config_name", r.Config.Name)
+	}
+
+	return nil
+}
+
+// DefaultSelectionRules 返回自动策略的内置选择规则
+// 短文档作为整体处理，多级标题组织的文档按章节处理，其余扁平文档按元素处理并打包小块
+func DefaultSelectionRules() []StrategySelectionRule {
+	return []StrategySelectionRule{
+		{
+			Name: "short-document",
+			Match: func(profile *DocumentProfile) bool {
+				return profile.WordCount <= AutoShortDocumentWords && profile.HeadingCount <= 1
+			},
+			Strategy: "document-level",
+			Reason:   fmt.Sprintf("short document: at most %d words and one heading", AutoShortDocumentWords),
+		},
+		{
+			Name: "structured-document",
+			Match: func(profile *DocumentProfile) bool {
+				return profile.HeadingCount >= AutoStructuredMinHeadings && profile.MaxHeadingLevel >= 2
+			},
+			Strategy: "hierarchical",
+			Reason:   fmt.Sprintf("structured document: at least %d headings across multiple levels", AutoStructuredMinHeadings),
+		},
+		{
+			Name:     "flat-document",
+			Match:    func(profile *DocumentProfile) bool { return true },
+			Strategy: "element-level",
+			Config:   ElementLevelConfigWithPacking(AutoPackTargetSize, 0),
+			Reason:   "flat document: element-level chunks packed up to the target size",
+		},
+	}
+}
+
+// AutoStrategy 按文档特征自动选择分块策略
+// 先按顺序匹配配置的 SelectionRules，再匹配内置规则，由第一个匹配的规则选择的策略完成分块。
+// 块的元数据 selected_strategy、selection_rule 和 selection_reason 记录选择结果
+type AutoStrategy struct {
+	config *StrategyConfig
+}
+
+// NewAutoStrategy 创建新的自动策略
+func NewAutoStrategy() *AutoStrategy {
+	return &AutoStrategy{
+		config: AutoConfig(),
+	}
+}
+
+// NewAutoStrategyWithConfig 使用指定配置创建自动策略
+func NewAutoStrategyWithConfig(config *StrategyConfig) *AutoStrategy {
+	if config == nil {
+		config = AutoConfig()
+	}
+	return &AutoStrategy{
+		config: config,
+	}
+}
+
+// GetName 返回策略名称
+func (s *AutoStrategy) GetName() string {
+	return "auto"
+}
+
+// GetDescription 返回策略描述
+func (s *AutoStrategy) GetDescription() string {
+	return "按文档特征或自定义选择规则为每个文档选择分块策略"
+}
+
+// ChunkDocument 选择分块策略并对文档进行分块
+func (s *AutoStrategy) ChunkDocument(doc ast.Node, source []byte, chunker *MarkdownChunker) ([]Chunk, error) {
+	if doc == nil {
+		return nil, NewChunkerError(ErrorTypeStrategyExecutionFailed, "文档节点不能为空", nil).
+			WithContext("strategy", s.GetName()).
+			WithContext("function", "ChunkDocument")
+	}
+
+	if chunker == nil || chunker.strategyRegistry == nil {
+		return nil, NewChunkerError(ErrorTypeStrategyExecutionFailed, "分块器实例不能为空", nil).
+			WithContext("strategy", s.GetName()).
+			WithContext("function", "ChunkDocument")
+	}
+
+	profile := NewDocumentProfile(doc, source, chunker)
+	rule := s.SelectRule(profile)
+
+	strategy, err := s.resolveStrategy(rule, chunker)
+	if err != nil {
+		return nil, err
+	}
+
+	chunker.logWithContext("info", "自动选择分块策略", NewLogContext("AutoStrategy.ChunkDocument").
+		WithMetadata("selected_strategy", rule.Strategy).
+		WithMetadata("selection_rule", rule.Name).
+		WithMetadata("word_count", profile.WordCount).
+		WithMetadata("heading_count", profile.HeadingCount).
+		WithMetadata("document_complexity", profile.Complexity))
+
+	chunks, err := strategy.ChunkDocument(doc, source, chunker)
+	if err != nil {
+		return nil, NewChunkerError(ErrorTypeStrategyExecutionFailed, "自动选择的策略执行失败", err).
+			WithContext("strategy", s.GetName()).
+			WithContext("function", "ChunkDocument").
+			WithContext("selected_strategy", rule.Strategy).
+			WithContext("selection_rule", rule.Name)
+	}
+
+	for i := range chunks {
+		if chunks[i].Metadata == nil {
+			chunks[i].Metadata = make(map[string]string)
+		}
+		chunks[i].Metadata["strategy"] = s.GetName()
+		chunks[i].Metadata["selected_strategy"] = rule.Strategy
+		chunks[i].Metadata["selection_rule"] = rule.Name
+		chunks[i].Metadata["selection_reason"] = rule.Reason
+	}
+
+	return chunks, nil
+}
+
+// SelectRule 返回文档匹配的第一个选择规则，配置的规则优先于内置规则
+func (s *AutoStrategy) SelectRule(profile *DocumentProfile) StrategySelectionRule {
+	var rules []StrategySelectionRule
+	if s.config != nil {
+		rules = append(rules, s.config.SelectionRules...)
+	}
+	rules = append(rules, DefaultSelectionRules()...)
+
+	for _, rule := range rules {
+		if rule.Match(profile) {
+			return rule
+		}
+	}
+	// 内置规则的最后一条总是匹配
+	return rules[len(rules)-1]
+}
+
+// resolveStrategy 从分块器的注册器中获取规则选择的策略副本，并应用规则的配置
+func (s *AutoStrategy) resolveStrategy(rule StrategySelectionRule, chunker *MarkdownChunker) (ChunkingStrategy, error) {
+	registered, err := chunker.strategyRegistry.Get(rule.Strategy)
+	if err != nil {
+		return nil, NewChunkerError(ErrorTypeStrategyNotFound, "自动选择的策略未注册", err).
+			WithContext("strategy", s.GetName()).
+			WithContext("function", "resolveStrategy").
+			WithContext("selected_strategy", rule.Strategy).
+			WithContext("selection_rule", rule.Name)
+	}
+
+	strategy := registered.Clone()
+	if rule.Config != nil {
+		if err := applyStrategyConfig(strategy, rule.Config, chunker.logger); err != nil {
+			return nil, NewChunkerError(ErrorTypeStrategyConfigInvalid, "自动选择的策略配置无效", err).
+				WithContext("strategy", s.GetName()).
+				WithContext("function", "resolveStrategy").
+				WithContext("selected_strategy", rule.Strategy).
+				WithContext("selection_rule", rule.Name)
+		}
+	}
+	return strategy, nil
+}
+
+// NewDocumentProfile 计算文档的特征，用于选择分块策略
+func NewDocumentProfile(doc ast.Node, source []byte, chunker *MarkdownChunker) *DocumentProfile {
+	metadata := (&DocumentLevelStrategy{}).extractDocumentMetadata(doc, source, chunker)
+	count := func(key string) int {
+		value, _ := strconv.Atoi(metadata[key])
+		return value
+	}
+
+	return &DocumentProfile{
+		Size:            measureChunkSize(chunker, string(source)),
+		WordCount:       count("word_count"),
+		HeadingCount:    count("heading_count"),
+		MaxHeadingLevel: count("max_heading_level"),
+		ParagraphCount:  count("paragraph_count"),
+		CodeBlockCount:  count("code_block_count"),
+		TableCount:      count("table_count"),
+		ListCount:       count("list_count"),
+		Complexity:      metadata["document_complexity"],
+		Metadata:        metadata,
+	}
+}
+
+// ValidateConfig 验证策略特定的配置
+func (s *AutoStrategy) ValidateConfig(config *StrategyConfig) error {
+	if config == nil {
+		return NewChunkerError(ErrorTypeStrategyConfigInvalid, "策略配置不能为空", nil).
+			WithContext("strategy", s.GetName()).
+			WithContext("function", "ValidateConfig")
+	}
+
+	return config.ValidateConfig()
+}
+
+// Clone 创建策略的副本（用于并发安全）
+func (s *AutoStrategy) Clone() ChunkingStrategy {
+	var configClone *StrategyConfig
+	if s.config != nil {
+		configClone = s.config.Clone()
+	}
+
+	return &AutoStrategy{
+		config: configClone,
+	}
+}
+
+// GetConfig 获取策略配置
+func (s *AutoStrategy) GetConfig() *StrategyConfig {
+	if s.config == nil {
+		return nil
+	}
+	return s.config.Clone()
+}
+
+// SetConfig 设置策略配置
+func (s *AutoStrategy) SetConfig(config *StrategyConfig) error {
+	if config == nil {
+		s.config = AutoConfig()
+		return nil
+	}
+
+	if err := s.ValidateConfig(config); err != nil {
+		return err
+	}
+
+	s.config = config.Clone()
+	return nil
+}
@@ -0,0 +1,112 @@
+package markdownchunker
+
+import (
+	"strings"
+	"testing"
+)
+
+func assertSelection(t *testing.T, chunks []Chunk, strategy, rule string) {
+	t.Helper()
+
+	if len(chunks) == 0 {
+		t.Fatal("Expected chunks")
+	}
+	for _, chunk := range chunks {
+		if chunk.Metadata["strategy"] != "auto" || chunk.Metadata["selected_strategy"] != strategy ||
+			chunk.Metadata["selection_rule"] != rule || chunk.Metadata["selection_reason"] == "" {
+			t.Fatalf("Expected %s via %s, got metadata %v", strategy, rule, chunk.Metadata)
+		}
+	}
+}
+
+func TestAutoStrategy_ShortNote(t *testing.T) {
+	chunks := chunkWithStrategyConfig(t, AutoConfig(), "# Note\n\nBuy milk.\n\n- eggs\n- bread")
+	assertSelection(t, chunks, "document-level", "short-document")
+	if len(chunks) != 1 || chunks[0].Type != "document" {
+		t.Errorf("Expected a single document chunk, got %+v", chunks)
+	}
+}
+
+func TestAutoStrategy_StructuredManual(t *testing.T) {
+	body := strings.Repeat("This manual section explains the configuration in detail. ", 6)
+	markdown := "# Manual\n\n" + body + "\n\n## Install\n\n" + body + "\n\n## Configure\n\n" + body + "\n\n# Reference\n\n" + body
+
+	chunks := chunkWithStrategyConfig(t, AutoConfig(), markdown)
+	assertSelection(t, chunks, "hierarchical", "structured-document")
+	if len(chunks) != 2 || !strings.Contains(chunks[0].Content, "## Configure") {
+		t.Errorf("Expected two top-level sections, got %d", len(chunks))
+	}
+}
+
+func TestAutoStrategy_FlatReadme(t *testing.T) {
+	var paragraphs []string
+	for i := 0; i < 24; i++ {
+		paragraphs = append(paragraphs, "Short paragraph describing one feature of the tool in plain words.")
+	}
+	markdown := "# Tool\n\n" + strings.Join(paragraphs, "\n\n")
+
+	chunks := chunkWithStrategyConfig(t, AutoConfig(), markdown)
+	assertSelection(t, chunks, "element-level", "flat-document")
+	if chunks[1].Type != "packed" {
+		t.Errorf("Expected small paragraphs to be packed, got %s", chunks[1].Type)
+	}
+}
+
+func TestAutoStrategy_CustomRules(t *testing.T) {
+	rule := StrategySelectionRule{
+		Name:     "code-heavy",
+		Match:    func(profile *DocumentProfile) bool { return profile.CodeBlockCount > 0 },
+		Strategy: "fixed-size",
+		Config:   FixedSizeConfig(40, 0),
+		Reason:   "documents with code use fixed windows",
+	}
+
+	chunks := chunkWithStrategyConfig(t, AutoConfig(rule), "Intro.\n\n```go\nfunc main() {}\n```\n\nMore words follow the code block here.")
+	assertSelection(t, chunks, "fixed-size", "code-heavy")
+	if chunks[0].Type != "window" || chunks[0].Metadata["selection_reason"] != rule.Reason {
+		t.Errorf("Unexpected chunk %+v", chunks[0])
+	}
+
+	// 不匹配的自定义规则回退到内置规则
+	chunks = chunkWithStrategyConfig(t, AutoConfig(rule), "Just a note.")
+	assertSelection(t, chunks, "document-level", "short-document")
+}
+
+func TestAutoStrategy_InvalidRules(t *testing.T) {
+	match := func(profile *DocumentProfile) bool { return true }
+	invalid := []StrategySelectionRule{
+		{Name: "", Match: match, Strategy: "hierarchical"},
+		{Name: "no-match", Strategy: "hierarchical"},
+		{Name: "recursive", Match: match, Strategy: "auto"},
+		{Name: "mismatch", Match: match, Strategy: "hierarchical", Config: DocumentLevelConfig()},
+	}
+	chunker := NewMarkdownChunkerWithStrategy("auto")
+	for _, rule := range invalid {
+		if err := chunker.SetStrategy("auto", AutoConfig(rule)); err == nil {
+			t.Errorf("Expected error for rule %q", rule.Name)
+		}
+	}
+
+	// 规则选择了未注册的策略
+	config := DefaultConfig()
+	config.ErrorHandling = ErrorModeStrict
+	config.ChunkingStrategy = AutoConfig(StrategySelectionRule{Name: "missing", Match: match, Strategy: "missing"})
+	if _, err := NewMarkdownChunkerWithConfig(config).ChunkDocument([]byte("Text.")); err == nil {
+		t.Error("Expected error for unregistered strategy")
+	}
+}
+
+func TestNewDocumentProfile(t *testing.T) {
+	chunker := NewMarkdownChunker()
+	content := []byte("# A\n\n## B\n\nSome text here.\n\n| x |\n|---|\n| 1 |\n\n```\ncode\n```")
+	doc, err := chunker.parseDocument(content)
+	if err != nil {
+		t.Fatalf("parseDocument() error = %v", err)
+	}
+
+	profile := NewDocumentProfile(doc, chunker.source, chunker)
+	if profile.HeadingCount != 2 || profile.MaxHeadingLevel != 2 || profile.TableCount != 1 ||
+		profile.CodeBlockCount != 1 || profile.Size != len(content) || profile.Complexity != "moderate" {
+		t.Errorf("Unexpected profile %+v", profile)
+	}
+}
@@ -58,6 +58,18 @@ type Chunk struct {
 	Links    []Link        `json:"links"`    // 包含的链接
 	Images   []Image       `json:"images"`   // 包含的图片
 	Hash     string        `json:"hash"`     // 内容哈希，用于去重
+
+	TokenCount int `json:"token_count"` // 原始内容的 token 数（使用配置的分词器计算）
+
+	SectionPath   []string `json:"section_path,omitempty"`   // 所在章节的标题路径，从最外层标题开始，标题块包括自身
+	EmbeddingText string   `json:"embedding_text,omitempty"` // 带文档标题和章节路径等上下文的嵌入文本，需配置 EmbeddingTextFormat
+
+	ParentID int   `json:"parent_id"`           // 所在章节标题块的 ID，没有时为 NoChunkID
+	PrevID   int   `json:"prev_id"`             // 文档顺序中上一个块的 ID，没有时为 NoChunkID
+	NextID   int   `json:"next_id"`             // 文档顺序中下一个块的 ID，没有时为 NoChunkID
+	ChildIDs []int `json:"child_ids,omitempty"` // 以该块为父块的块 ID
+
+	node ast.Node // 生成该块的 AST 节点，只在分块过程中供拆分等后处理使用，输出前清除
 }
 
 // LogContext 表示日志上下文信息
@@ -97,6 +109,16 @@ const (
 	PerformanceModeSpeedOptimized
 )
 
+// OversizeHandlingMode 超大块处理模式
+type OversizeHandlingMode int
+
+const (
+	// OversizeModeTruncate 截断模式，超出 MaxChunkSize 的内容被丢弃（默认行为）
+	OversizeModeTruncate OversizeHandlingMode = iota
+	// OversizeModeSplit 拆分模式，按段落、行、句子、单词的顺序在自然边界处递归拆分
+	OversizeModeSplit
+)
+
 // MetadataExtractor 元数据提取器接口
 type MetadataExtractor interface {
 	// Extract 从AST节点中提取元数据
@@ -261,9 +283,48 @@ func (c *MarkdownChunker) logWithContext(level, message string, context *LogCont
 
 // ChunkerConfig 分块器配置
 type ChunkerConfig struct {
-	// MaxChunkSize 最大块大小（字符数），0表示无限制
+	// MaxChunkSize 最大块大小（按 SizeUnit 计量），0表示无限制
 	MaxChunkSize int
 
+	// SizeUnit 块大小的计量单位：字节、字符或 token
+	SizeUnit SizeUnit
+
+	// Tokenizer 分词器，用于 token 计量和 TokenCount，nil 时使用 WhitespaceTokenizer
+	Tokenizer Tokenizer
+
+	// OversizeHandling 块超过 MaxChunkSize 时的处理方式
+	OversizeHandling OversizeHandlingMode
+
+	// SplitCodeBlocks 是否在顶层声明边界处拆分大的围栏代码块
+	SplitCodeBlocks bool
+
+	// CodeBlockMaxSize 代码块拆分后每个片段的最大大小，0表示使用 MaxChunkSize
+	CodeBlockMaxSize int
+
+	// SplitTables 是否按数据行拆分大表格，每个片段都重复表头和对齐分隔行
+	SplitTables bool
+
+	// TableMaxSize 表格拆分后每个片段的最大大小，0表示使用 MaxChunkSize
+	TableMaxSize int
+
+	// SplitLists 是否在列表项边界处拆分长列表，拆出的嵌套项会带上祖先项链
+	SplitLists bool
+
+	// ListMaxSize 列表拆分后每个片段的最大大小，0表示使用 MaxChunkSize
+	ListMaxSize int
+
+	// FootnoteHandling 脚注定义的处理方式，默认附加到引用它的块中
+	FootnoteHandling FootnoteHandlingMode
+
+	// FrontMatterKeys 需要复制到每个块元数据中的前置元数据键（如 title、tags、author、date），
+	// 复制时添加 fm_ 前缀，为空表示不复制
+	FrontMatterKeys []string
+
+	// EmbeddingTextFormat 嵌入文本格式，为空表示不生成 Chunk.EmbeddingText
+	// 可用占位符：{title}、{breadcrumb}、{type}、{language}、{text}，必须包含 {text}
+	// 块文本之外的部分计入 MaxChunkSize，最多占用其一半
+	EmbeddingTextFormat string
+
 	// EnabledTypes 启用的内容类型，nil表示启用所有类型
 	EnabledTypes map[string]bool
 
@@ -296,6 +357,10 @@ type ChunkerConfig struct {
 
 	// 策略配置
 	ChunkingStrategy *StrategyConfig `json:"chunking_strategy,omitempty"` // 分块策略配置
+
+	// StrategyOverrides 按章节路径或内容类型为文档局部区域指定的策略配置，按顺序匹配，第一个匹配的生效
+	// 未匹配的内容使用 ChunkingStrategy，各区域的块按文档顺序合并并重新编号
+	StrategyOverrides []StrategyOverride `json:"strategy_overrides,omitempty"`
 }
 
 // MarkdownChunker Markdown 分块器
@@ -312,13 +377,19 @@ type MarkdownChunker struct {
 	stringOps          *OptimizedStringOperations
 	chunks             []Chunk
 	source             []byte
-	logger             log.Logger // 日志器实例
+	frontMatter        *FrontMatter               // 最近一次分块检测到的前置元数据
+	footnotes          map[int]footnoteDefinition // 当前文档中被引用的脚注定义
+	sections           map[ast.Node]sectionInfo   // 当前文档顶层节点所在的章节
+	documentTitle      string                     // 当前文档标题，用于嵌入文本
+	logger             log.Logger                 // 日志器实例
 }
 
 // DefaultConfig 返回默认配置
 func DefaultConfig() *ChunkerConfig {
 	return &ChunkerConfig{
-		MaxChunkSize:       0,   // 无限制
+		MaxChunkSize:       0, // 无限制
+		OversizeHandling:   OversizeModeTruncate,
+		SizeUnit:           SizeUnitBytes,
 		EnabledTypes:       nil, // 启用所有类型
 		CustomExtractors:   []MetadataExtractor{},
 		ErrorHandling:      ErrorModePermissive,
@@ -378,6 +449,20 @@ func EnsureDefaultStrategyConfig(config *ChunkerConfig) {
 	case "document-level":
 		// 文档级策略通常不需要特殊参数
 		// 可以在这里添加文档级策略的默认参数
+
+	case "semantic":
+		// 语义策略未设置语义单元时按句子拆分
+		if config.ChunkingStrategy.SemanticUnit == "" {
+			config.ChunkingStrategy.SemanticUnit = SemanticUnitSentences
+			config.ChunkingStrategy.Parameters["semantic_unit"] = string(SemanticUnitSentences)
+		}
+
+	case "fixed-size":
+		// 固定大小策略未设置窗口大小时使用默认值
+		if config.ChunkingStrategy.WindowSize == 0 {
+			config.ChunkingStrategy.WindowSize = DefaultWindowSize
+			config.ChunkingStrategy.Parameters["window_size"] = DefaultWindowSize
+		}
 	}
 }
 
@@ -569,24 +654,144 @@ func ValidateConfig(config *ChunkerConfig) error {
 			WithContext("minimum_allowed", 0)
 	}
 
+	if config.OversizeHandling < OversizeModeTruncate || config.OversizeHandling > OversizeModeSplit {
+		tempLogger.Errorw("配置验证失败：超大块处理模式无效",
+			"function", "ValidateConfig",
+			"field", "OversizeHandling",
+			"value", config.OversizeHandling,
+			"error_type", "invalid_oversize_handling")
+
+		return NewChunkerError(ErrorTypeConfigInvalid, "无效的超大块处理模式", nil).
+			WithContext("function", "ValidateConfig").
+			WithContext("field", "OversizeHandling").
+			WithContext("value", config.OversizeHandling)
+	}
+
+	if config.FootnoteHandling < FootnoteModeAttach || config.FootnoteHandling > FootnoteModeIgnore {
+		tempLogger.Errorw("配置验证失败：脚注处理模式无效",
+			"function", "ValidateConfig",
+			"field", "FootnoteHandling",
+			"value", config.FootnoteHandling,
+			"error_type", "invalid_footnote_handling")
+
+		return NewChunkerError(ErrorTypeConfigInvalid, "无效的脚注处理模式", nil).
+			WithContext("function", "ValidateConfig").
+			WithContext("field", "FootnoteHandling").
+			WithContext("value", config.FootnoteHandling)
+	}
+
+	if config.CodeBlockMaxSize < 0 {
+		tempLogger.Errorw("配置验证失败：代码块最大大小无效",
+			"function", "ValidateConfig",
+			"field", "CodeBlockMaxSize",
+			"value", config.CodeBlockMaxSize,
+			"minimum_allowed", 0,
+			"error_type", "invalid_code_block_size")
+
+		return NewChunkerError(ErrorTypeConfigInvalid, "代码块最大大小不能为负数", nil).
+			WithContext("function", "ValidateConfig").
+			WithContext("field", "CodeBlockMaxSize").
+			WithContext("value", config.CodeBlockMaxSize).
+			WithContext("minimum_allowed", 0)
+	}
+
+	if config.TableMaxSize < 0 {
+		tempLogger.Errorw("配置验证失败：表格最大大小无效",
+			"function", "ValidateConfig",
+			"field", "TableMaxSize",
+			"value", config.TableMaxSize,
+			"minimum_allowed", 0,
+			"error_type", "invalid_table_size")
+
+		return NewChunkerError(ErrorTypeConfigInvalid, "表格最大大小不能为负数", nil).
+			WithContext("function", "ValidateConfig").
+			WithContext("field", "TableMaxSize").
+			WithContext("value", config.TableMaxSize).
+			WithContext("minimum_allowed", 0)
+	}
+
+	if config.ListMaxSize < 0 {
+		tempLogger.Errorw("配置验证失败：列表最大大小无效",
+			"function", "ValidateConfig",
+			"field", "ListMaxSize",
+			"value", config.ListMaxSize,
+			"minimum_allowed", 0,
+			"error_type", "invalid_list_size")
+
+		return NewChunkerError(ErrorTypeConfigInvalid, "列表最大大小不能为负数", nil).
+			WithContext("function", "ValidateConfig").
+			WithContext("field", "ListMaxSize").
+			WithContext("value", config.ListMaxSize).
+			WithContext("minimum_allowed", 0)
+	}
+
+	for i, key := range config.FrontMatterKeys {
+		if strings.TrimSpace(key) == "" {
+			tempLogger.Errorw("配置验证失败：前置元数据键为空",
+				"function", "ValidateConfig",
+				"field", "FrontMatterKeys",
+				"index", i,
+				"error_type", "empty_front_matter_key")
+
+			return NewChunkerError(ErrorTypeConfigInvalid, "前置元数据键不能为空", nil).
+				WithContext("function", "ValidateConfig").
+				WithContext("field", "FrontMatterKeys").
+				WithContext("index", i)
+		}
+	}
+
+	if config.EmbeddingTextFormat != "" && !strings.Contains(config.EmbeddingTextFormat, EmbeddingPlaceholderText) {
+		tempLogger.Errorw("配置验证失败：嵌入文本格式缺少 {text} 占位符",
+			"function", "ValidateConfig",
+			"field", "EmbeddingTextFormat",
+			"value", config.EmbeddingTextFormat,
+			"error_type", "missing_text_placeholder")
+
+		return NewChunkerError(ErrorTypeConfigInvalid, "嵌入文本格式必须包含 {text} 占位符", nil).
+			WithContext("function", "ValidateConfig").
+			WithContext("field", "EmbeddingTextFormat").
+			WithContext("value", config.EmbeddingTextFormat)
+	}
+
+	if config.SizeUnit < SizeUnitBytes || config.SizeUnit > SizeUnitTokens {
+		tempLogger.Errorw("配置验证失败：大小计量单位无效",
+			"function", "ValidateConfig",
+			"field", "SizeUnit",
+			"value", config.SizeUnit,
+			"error_type", "invalid_size_unit")
+
+		return NewChunkerError(ErrorTypeConfigInvalid, "无效的大小计量单位", nil).
+			WithContext("function", "ValidateConfig").
+			WithContext("field", "SizeUnit").
+			WithContext("value", config.SizeUnit)
+	}
+
+	for i, override := range config.StrategyOverrides {
+		if err := override.Validate(); err != nil {
+			tempLogger.Errorw("配置验证失败：策略覆盖无效",
+				"function", "ValidateConfig",
+				"field", "StrategyOverrides",
+				"index", i,
+				"section_pattern", override.SectionPattern,
+				"error", err.Error(),
+				"error_type", "invalid_strategy_override")
+
+			return NewChunkerError(ErrorTypeConfigInvalid, "策略覆盖无效", err).
+				WithContext("function", "ValidateConfig").
+				WithContext("field", "StrategyOverrides").
+				WithContext("index", i)
+		}
+	}
+
 	// 验证启用的类型
 	if config.EnabledTypes != nil {
 		tempLogger.Debugw("验证启用的内容类型",
 			"function", "ValidateConfig",
 			"enabled_types_count", len(config.EnabledTypes))
 
-		validTypes := map[string]bool{
-			"heading": true, "paragraph": true, "code": true,
-			"table": true, "list": true, "blockquote": true,
-			"thematic_break": true,
-		}
-
 		for typeName := range config.EnabledTypes {
-			if !validTypes[typeName] {
-				var validTypesList []string
-				for vt := range validTypes {
-					validTypesList = append(validTypesList, vt)
-				}
+			if !isValidChunkType(typeName) {
+				validTypesList := validChunkTypeNames()
 
 				tempLogger.Errorw("配置验证失败：无效的内容类型",
 					"function", "ValidateConfig",
@@ -742,6 +947,11 @@ func validateStrategyConfig(config *StrategyConfig) error {
 		"element-level":  true,
 		"hierarchical":   true,
 		"document-level": true,
+		"fixed-size":     true,
+		"semantic":       true,
+		"auto":           true,
+		"qa":             true,
+		"slides":         true,
 	}
 
 	if !validStrategies[config.Name] {
@@ -822,6 +1032,22 @@ func validateStrategyConfig(config *StrategyConfig) error {
 			return err
 		}
 
+	case "fixed-size":
+		tempLogger.Debugw("执行固定大小策略特定验证",
+			"function", "validateStrategyConfig",
+			"strategy_name", config.Name,
+			"window_size", config.WindowSize,
+			"window_stride", config.WindowStride)
+
+		if err := validateFixedSizeStrategyConfig(config); err != nil {
+			tempLogger.Errorw("策略配置验证失败：固定大小策略验证失败",
+				"function", "validateStrategyConfig",
+				"strategy_name", config.Name,
+				"error", err.Error(),
+				"error_type", "fixed_size_validation_failed")
+			return err
+		}
+
 	default:
 		tempLogger.Debugw("跳过未知策略的特定验证",
 			"function", "validateStrategyConfig",
@@ -923,6 +1149,32 @@ func validateDocumentLevelStrategyConfig(config *StrategyConfig) error {
 	return nil
 }
 
+// validateFixedSizeStrategyConfig 验证固定大小策略特定配置
+func validateFixedSizeStrategyConfig(config *StrategyConfig) error {
+	if config == nil {
+		return NewChunkerError(ErrorTypeStrategyConfigInvalid, "固定大小策略配置不能为空", nil).
+			WithContext("function", "validateFixedSizeStrategyConfig")
+	}
+
+	if config.MaxDepth > 0 || config.MinDepth > 0 {
+		return NewChunkerError(ErrorTypeStrategyConfigInvalid, "固定大小策略不支持层级深度配置", nil).
+			WithContext("function", "validateFixedSizeStrategyConfig").
+			WithContext("max_depth", config.MaxDepth).
+			WithContext("min_depth", config.MinDepth).
+			WithContext("recommendation", "固定大小策略按窗口分块，不使用层级信息")
+	}
+
+	if config.WindowSize > 0 && config.WindowStride > config.WindowSize {
+		return NewChunkerError(ErrorTypeStrategyConfigInvalid, "窗口步长不能大于窗口大小", nil).
+			WithContext("function", "validateFixedSizeStrategyConfig").
+			WithContext("window_size", config.WindowSize).
+			WithContext("window_stride", config.WindowStride).
+			WithContext("recommendation", "步长大于窗口大小会跳过窗口之间的内容")
+	}
+
+	return nil
+}
+
 // initializeStrategySystem 初始化策略系统
 func initializeStrategySystem(config *ChunkerConfig, logger log.Logger) (*StrategyRegistry, ChunkingStrategy) {
 	// 创建策略注册器
@@ -932,6 +1184,11 @@ func initializeStrategySystem(config *ChunkerConfig, logger log.Logger) (*Strate
 	var elementStrategy ChunkingStrategy
 	var hierarchicalStrategy ChunkingStrategy
 	var documentStrategy ChunkingStrategy
+	var fixedSizeStrategy ChunkingStrategy
+	var semanticStrategy ChunkingStrategy
+	var autoStrategy ChunkingStrategy
+	var qaStrategy ChunkingStrategy
+	var slidesStrategy ChunkingStrategy
 
 	// 注册元素级策略
 	elementStrategy = NewElementLevelStrategy()
@@ -974,6 +1231,71 @@ func initializeStrategySystem(config *ChunkerConfig, logger log.Logger) (*Strate
 			"strategy", "document-level")
 	}
 
+	// 注册固定大小策略
+	fixedSizeStrategy = NewFixedSizeStrategy()
+	if err := strategyRegistry.Register(fixedSizeStrategy); err != nil {
+		logger.Errorw("注册固定大小策略失败",
+			"function", "initializeStrategySystem",
+			"strategy", "fixed-size",
+			"error", err.Error())
+	} else {
+		logger.Debugw("成功注册固定大小策略",
+			"function", "initializeStrategySystem",
+			"strategy", "fixed-size")
+	}
+
+	// 注册语义策略
+	semanticStrategy = NewSemanticStrategy()
+	if err := strategyRegistry.Register(semanticStrategy); err != nil {
+		logger.Errorw("注册语义策略失败",
+			"function", "initializeStrategySystem",
+			"strategy", "semantic",
+			"error", err.Error())
+	} else {
+		logger.Debugw("成功注册语义策略",
+			"function", "initializeStrategySystem",
+			"strategy", "semantic")
+	}
+
+	// 注册自动策略
+	autoStrategy = NewAutoStrategy()
+	if err := strategyRegistry.Register(autoStrategy); err != nil {
+		logger.Errorw("注册自动策略失败",
+			"function", "initializeStrategySystem",
+			"strategy", "auto",
+			"error", err.Error())
+	} else {
+		logger.Debugw("成功注册自动策略",
+			"function", "initializeStrategySystem",
+			"strategy", "auto")
+	}
+
+	// 注册问答对策略
+	qaStrategy = NewQAStrategy()
+	if err := strategyRegistry.Register(qaStrategy); err != nil {
+		logger.Errorw("注册问答对策略失败",
+			"function", "initializeStrategySystem",
+			"strategy", "qa",
+			"error", err.Error())
+	} else {
+		logger.Debugw("成功注册问答对策略",
+			"function", "initializeStrategySystem",
+			"strategy", "qa")
+	}
+
+	// 注册幻灯片策略
+	slidesStrategy = NewSlidesStrategy()
+	if err := strategyRegistry.Register(slidesStrategy); err != nil {
+		logger.Errorw("注册幻灯片策略失败",
+			"function", "initializeStrategySystem",
+			"strategy", "slides",
+			"error", err.Error())
+	} else {
+		logger.Debugw("成功注册幻灯片策略",
+			"function", "initializeStrategySystem",
+			"strategy", "slides")
+	}
+
 	// 确定当前使用的策略
 	currentStrategy := determineCurrentStrategy(config, strategyRegistry, elementStrategy, logger)
 
@@ -1170,6 +1492,7 @@ func (c *MarkdownChunker) SetStrategy(strategyName string, config *StrategyConfi
 
 	// 设置新策略
 	c.strategy = clonedStrategy
+	c.invalidateStrategyCache()
 
 	// 更新配置中的策略信息
 	if config != nil {
@@ -1296,6 +1619,7 @@ func (c *MarkdownChunker) UpdateStrategyConfig(config *StrategyConfig) error {
 
 	// 更新配置
 	c.config.ChunkingStrategy = config.Clone()
+	c.invalidateStrategyCache()
 
 	// 记录配置更新成功
 	successLogCtx := NewLogContext("UpdateStrategyConfig").
@@ -1437,6 +1761,10 @@ func (c *MarkdownChunker) isTypeEnabled(chunkType string) bool {
 	}
 
 	enabled, exists := c.config.EnabledTypes[chunkType]
+	if !exists && synthesizedChunkTypes[chunkType] {
+		// 合成块不对应单个元素类型，未显式配置时保留
+		return true
+	}
 	return exists && enabled
 }
 
@@ -1458,6 +1786,16 @@ func NewMarkdownChunkerWithStrategy(strategyName string) *MarkdownChunker {
 		config.ChunkingStrategy = DocumentLevelConfig()
 	case "element-level":
 		config.ChunkingStrategy = ElementLevelConfig()
+	case "fixed-size":
+		config.ChunkingStrategy = FixedSizeConfig(DefaultWindowSize, 0)
+	case "semantic":
+		config.ChunkingStrategy = SemanticConfig(0, 0)
+	case "auto":
+		config.ChunkingStrategy = AutoConfig()
+	case "qa":
+		config.ChunkingStrategy = QAConfig()
+	case "slides":
+		config.ChunkingStrategy = SlidesConfig(0)
 	default:
 		// 对于未知策略，使用默认策略并记录警告
 		config.ChunkingStrategy = ElementLevelConfig()
@@ -1503,10 +1841,14 @@ func NewMarkdownChunkerWithConfig(config *ChunkerConfig) *MarkdownChunker {
 
 	md := goldmark.New(
 		goldmark.WithExtensions(
-			extension.GFM, // GitHub Flavored Markdown (包含表格支持)
+			extension.GFM,          // GitHub Flavored Markdown (包含表格支持)
+			extension.Footnote,     // 脚注 [^1]
+			&mathExtension{},       // $$ 行间公式和 $ 行内公式
+			&admonitionExtension{}, // GitHub/MkDocs/Docusaurus 提示块
+			&headingIDExtension{},  // GitHub 风格的标题锚点
 		),
 		goldmark.WithParserOptions(
-			parser.WithAutoHeadingID(),
+			parser.WithHeadingAttribute(), // 显式标题锚点 {#custom-id}
 		),
 		goldmark.WithRendererOptions(
 			html.WithHardWraps(),
@@ -1774,11 +2116,106 @@ func (c *MarkdownChunker) ChunkDocument(content []byte) ([]Chunk, error) {
 		c.logWithContext("warn", "处理大型文档", largeDocLogCtx)
 	}
 
+	doc, err := c.parseDocument(content)
+	if err != nil {
+		return nil, err
+	}
+	if doc == nil {
+		return []Chunk{}, nil
+	}
+	// 后续处理使用剥离前置元数据后的内容
+	content = c.source
+
+	parseCompleteLogCtx := NewLogContext("ChunkDocument").WithDocumentInfo(len(content), 0)
+	c.logWithContext("debug", "Markdown AST 解析完成", parseCompleteLogCtx)
+
+	// 使用策略进行分块
+	strategyName := "unknown"
+	if c.strategy != nil {
+		strategyName = c.strategy.GetName()
+	}
+	strategyLogCtx := NewLogContext("ChunkDocument").
+		WithDocumentInfo(len(content), 0).
+		WithMetadata("strategy", strategyName)
+	c.logWithContext("debug", "开始使用策略进行分块", strategyLogCtx)
+
+	// 使用策略处理文档，包含错误恢复机制；配置了策略覆盖时按区域使用不同的策略
+	var strategyChunks []Chunk
+	if len(c.config.StrategyOverrides) > 0 {
+		strategyChunks, err = c.executeWithOverrides(doc, content)
+	} else {
+		strategyChunks, err = c.executeStrategyWithRecovery(doc, content)
+	}
+	if err != nil {
+		// 策略执行失败的详细错误处理已在 executeStrategyWithRecovery 中完成
+		return nil, err
+	}
+
+	// 后处理：应用配置级别的过滤和处理
+	c.chunks, err = c.postProcessChunks(strategyChunks, len(content))
+	if err != nil {
+		return nil, err
+	}
+
+	strategyNameForLog := "unknown"
+	if c.strategy != nil {
+		strategyNameForLog = c.strategy.GetName()
+	}
+	completeStrategyLogCtx := NewLogContext("ChunkDocument").
+		WithDocumentInfo(len(content), len(c.chunks)).
+		WithMetadata("strategy", strategyNameForLog).
+		WithMetadata("total_chunks", len(strategyChunks)).
+		WithMetadata("filtered_chunks", len(c.chunks))
+	c.logWithContext("debug", "完成策略分块处理", completeStrategyLogCtx)
+
+	// 最终的资源使用情况检查和报告
+	if c.memoryOptimizer != nil {
+		// 检查最终内存状态
+		if err := c.memoryOptimizer.CheckMemoryLimit(); err != nil {
+			finalMemoryLogCtx := NewLogContext("ChunkDocument").
+				WithDocumentInfo(len(content), len(c.chunks)).
+				WithMetadata("error", err.Error()).
+				WithMetadata("recommendation", "考虑在后续处理中释放资源")
+			c.logWithContext("warn", "处理完成时内存使用仍然较高", finalMemoryLogCtx)
+		}
+
+		// 获取内存优化器统计信息
+		memStats := c.memoryOptimizer.GetMemoryStats()
+		memStatsLogCtx := NewLogContext("ChunkDocument").
+			WithDocumentInfo(len(content), len(c.chunks)).
+			WithMetadata("current_memory_mb", memStats.CurrentMemory/(1024*1024)).
+			WithMetadata("memory_limit_mb", memStats.MemoryLimit/(1024*1024)).
+			WithMetadata("processed_bytes_mb", memStats.ProcessedBytes/(1024*1024)).
+			WithMetadata("gc_threshold_mb", memStats.GCThreshold/(1024*1024)).
+			WithMetadata("total_allocations_mb", memStats.TotalAllocations/(1024*1024)).
+			WithMetadata("gc_cycles", memStats.GCCycles)
+		c.logWithContext("info", "内存优化器统计", memStatsLogCtx)
+	}
+
+	// 最终性能检查
+	c.performanceMonitor.CheckMemoryThresholds()
+
+	return c.chunks, nil
+}
+
+// parseDocument 剥离前置元数据并解析 Markdown，收集脚注、章节和文档标题等文档级信息
+// 解析失败且错误处理模式允许继续时返回 nil 节点
+func (c *MarkdownChunker) parseDocument(content []byte) (ast.Node, error) {
+	// 检测并剥离前置元数据，避免被解析为分隔线和段落
+	c.frontMatter, content = extractFrontMatter(content)
+	if c.frontMatter != nil {
+		frontMatterLogCtx := NewLogContext("parseDocument").
+			WithMetadata("format", string(c.frontMatter.Format)).
+			WithMetadata("field_count", len(c.frontMatter.Fields)).
+			WithMetadata("end_line", c.frontMatter.EndLine)
+		c.logWithContext("debug", "检测到前置元数据", frontMatterLogCtx)
+	}
+
 	c.source = content
 	c.chunks = []Chunk{}
 
 	// 解析 Markdown
-	parseLogCtx := NewLogContext("ChunkDocument").WithDocumentInfo(len(content), 0)
+	parseLogCtx := NewLogContext("parseDocument").WithDocumentInfo(len(content), 0)
 	c.logWithContext("debug", "开始解析 Markdown AST", parseLogCtx)
 
 	reader := text.NewReader(content)
@@ -1786,11 +2223,11 @@ func (c *MarkdownChunker) ChunkDocument(content []byte) ([]Chunk, error) {
 
 	// 检查解析结果
 	if doc == nil {
-		parseFailLogCtx := NewLogContext("ChunkDocument").WithDocumentInfo(len(content), 0)
+		parseFailLogCtx := NewLogContext("parseDocument").WithDocumentInfo(len(content), 0)
 		c.logWithContext("error", "Markdown AST 解析失败", parseFailLogCtx)
 
 		err := NewChunkerError(ErrorTypeParsingFailed, "Markdown文档解析失败", nil).
-			WithContext("function", "ChunkDocument").
+			WithContext("function", "parseDocument").
 			WithContext("document_size_bytes", len(content)).
 			WithContext("parser_type", "goldmark").
 			WithContext("content_preview", string(content[:min(500, len(content))])).
@@ -1798,35 +2235,30 @@ func (c *MarkdownChunker) ChunkDocument(content []byte) ([]Chunk, error) {
 		if handlerErr := c.errorHandler.HandleError(err); handlerErr != nil {
 			return nil, handlerErr
 		}
-		return []Chunk{}, nil
+		return nil, nil
 	}
 
-	parseCompleteLogCtx := NewLogContext("ChunkDocument").WithDocumentInfo(len(content), 0)
-	c.logWithContext("debug", "Markdown AST 解析完成", parseCompleteLogCtx)
+	// 收集脚注定义，供引用脚注的块使用
+	c.footnotes = c.collectFootnotes(doc)
 
-	// 使用策略进行分块
-	strategyName := "unknown"
-	if c.strategy != nil {
-		strategyName = c.strategy.GetName()
-	}
-	strategyLogCtx := NewLogContext("ChunkDocument").
-		WithDocumentInfo(len(content), 0).
-		WithMetadata("strategy", strategyName)
-	c.logWithContext("debug", "开始使用策略进行分块", strategyLogCtx)
+	// 记录每个顶层节点所在的章节，供块的锚点和章节路径使用
+	c.sections = c.collectSections(doc)
 
-	// 使用策略处理文档，包含错误恢复机制
-	strategyChunks, err := c.executeStrategyWithRecovery(doc, content)
-	if err != nil {
-		// 策略执行失败的详细错误处理已在 executeStrategyWithRecovery 中完成
-		return nil, err
-	}
+	// 文档标题用于嵌入文本前缀
+	c.documentTitle = c.findDocumentTitle(doc)
 
-	// 后处理：应用配置级别的过滤和处理
-	c.chunks = []Chunk{}
+	return doc, nil
+}
+
+// postProcessChunks 对策略输出应用配置级别的过滤、拆分和元数据处理
+// 拆分产生新块时重新编号，最后按结果建立块之间的导航关系
+func (c *MarkdownChunker) postProcessChunks(strategyChunks []Chunk, contentSize int) ([]Chunk, error) {
+	chunks := []Chunk{}
+	splitOccurred := false
 	for i, chunk := range strategyChunks {
 		// 检查类型是否启用
 		if !c.isTypeEnabled(chunk.Type) {
-			skipTypeLogCtx := NewLogContext("ChunkDocument").
+			skipTypeLogCtx := NewLogContext("postProcessChunks").
 				WithNodeInfo(chunk.Type, chunk.ID).
 				WithMetadata("chunk_type", chunk.Type)
 			c.logWithContext("debug", "跳过未启用的块类型", skipTypeLogCtx)
@@ -1835,89 +2267,162 @@ func (c *MarkdownChunker) ChunkDocument(content []byte) ([]Chunk, error) {
 
 		// 检查是否过滤空块
 		if c.config.FilterEmptyChunks && strings.TrimSpace(chunk.Text) == "" {
-			filterEmptyLogCtx := NewLogContext("ChunkDocument").
+			filterEmptyLogCtx := NewLogContext("postProcessChunks").
 				WithNodeInfo(chunk.Type, chunk.ID).
 				WithMetadata("chunk_type", chunk.Type)
 			c.logWithContext("debug", "过滤空块", filterEmptyLogCtx)
 			continue
 		}
 
-		// 检查块大小限制
-		if c.config.MaxChunkSize > 0 && len(chunk.Content) > c.config.MaxChunkSize {
-			oversizeLogCtx := NewLogContext("ChunkDocument").
-				WithNodeInfo(chunk.Type, chunk.ID).
-				WithMetadata("chunk_size", len(chunk.Content)).
-				WithMetadata("max_size", c.config.MaxChunkSize).
-				WithMetadata("size_ratio", float64(len(chunk.Content))/float64(c.config.MaxChunkSize))
-			c.logWithContext("warn", "块大小超过限制", oversizeLogCtx)
-
-			err := NewChunkerError(ErrorTypeChunkTooLarge, "生成的块大小超过配置限制", nil).
-				WithContext("function", "ChunkDocument").
-				WithContext("chunk_id", chunk.ID).
-				WithContext("chunk_type", chunk.Type).
-				WithContext("chunk_size", len(chunk.Content)).
-				WithContext("chunk_size_bytes", len(chunk.Content)).
-				WithContext("max_size", c.config.MaxChunkSize).
-				WithContext("max_size_bytes", c.config.MaxChunkSize).
-				WithContext("size_ratio", float64(len(chunk.Content))/float64(c.config.MaxChunkSize)).
-				WithContext("content_preview", chunk.Content[:min(100, len(chunk.Content))]).
-				WithContext("handling_mode", c.config.ErrorHandling).
-				WithContext("recommendation", "考虑增加MaxChunkSize或启用内容截断")
-
-			if handlerErr := c.errorHandler.HandleError(err); handlerErr != nil {
-				return nil, handlerErr
+		// 按声明边界拆分大代码块
+		candidates := []Chunk{chunk}
+		if c.shouldSplitCodeChunk(chunk) {
+			candidates = c.splitCodeChunk(chunk, c.codeSplitLimit(chunk))
+			if len(candidates) > 1 {
+				splitOccurred = true
+
+				codeSplitLogCtx := NewLogContext("postProcessChunks").
+					WithNodeInfo(chunk.Type, chunk.ID).
+					WithMetadata("language", chunk.Metadata["language"]).
+					WithMetadata("max_size", c.codeSplitLimit(chunk)).
+					WithMetadata("code_parts", len(candidates))
+				c.logWithContext("info", "拆分大代码块", codeSplitLogCtx)
 			}
+		}
+
+		// 按数据行拆分大表格
+		if c.shouldSplitTableChunk(chunk) {
+			candidates = c.splitTableChunk(chunk, c.tableSplitLimit(chunk))
+			if len(candidates) > 1 {
+				splitOccurred = true
 
-			// 在宽松模式下截断内容
-			if c.config.ErrorHandling != ErrorModeStrict {
-				truncateLogCtx := NewLogContext("ChunkDocument").
+				tableSplitLogCtx := NewLogContext("postProcessChunks").
 					WithNodeInfo(chunk.Type, chunk.ID).
-					WithMetadata("original_size", len(chunk.Content)).
-					WithMetadata("truncated_size", c.config.MaxChunkSize)
-				c.logWithContext("info", "截断超大块内容", truncateLogCtx)
+					WithMetadata("max_size", c.tableSplitLimit(chunk)).
+					WithMetadata("table_parts", len(candidates))
+				c.logWithContext("info", "拆分大表格", tableSplitLogCtx)
+			}
+		}
+
+		// 按列表项拆分长列表
+		if c.shouldSplitListChunk(chunk) {
+			candidates = c.splitListChunk(chunk, c.listSplitLimit(chunk))
+			if len(candidates) > 1 {
+				splitOccurred = true
 
-				chunk.Content = chunk.Content[:c.config.MaxChunkSize]
-				chunk.Text = chunk.Text[:min(len(chunk.Text), c.config.MaxChunkSize)]
+				listSplitLogCtx := NewLogContext("postProcessChunks").
+					WithNodeInfo(chunk.Type, chunk.ID).
+					WithMetadata("list_type", chunk.Metadata["list_type"]).
+					WithMetadata("max_size", c.listSplitLimit(chunk)).
+					WithMetadata("list_parts", len(candidates))
+				c.logWithContext("info", "拆分长列表", listSplitLogCtx)
 			}
 		}
 
-		// 应用自定义元数据提取器（需要重新解析以获取AST节点）
-		if len(c.config.CustomExtractors) > 0 {
-			// 为了应用自定义提取器，我们需要重新解析这个块的内容
-			// 这是一个权衡：为了保持兼容性，我们在这里重新解析
-			reader := text.NewReader([]byte(chunk.Content))
-			chunkDoc := c.md.Parser().Parse(reader)
-			if chunkDoc != nil && chunkDoc.FirstChild() != nil {
-				for _, extractor := range c.config.CustomExtractors {
-					supportedTypes := extractor.SupportedTypes()
-					if len(supportedTypes) == 0 || slices.Contains(supportedTypes, chunk.Type) {
-						maps.Copy(chunk.Metadata, extractor.Extract(chunkDoc.FirstChild(), []byte(chunk.Content)))
+		var pieces []Chunk
+		for _, chunk := range candidates {
+			// 检查块大小限制
+			chunkPieces := []Chunk{chunk}
+			chunkSize := c.measureSize(chunk.Content)
+			// 启用嵌入文本时，标题和章节路径等前缀也计入块大小限制
+			sizeLimit := c.chunkSizeLimit(chunk)
+			if sizeLimit > 0 && chunkSize > sizeLimit {
+				if c.config.OversizeHandling == OversizeModeSplit {
+					// 拆分模式：在自然边界处递归拆分，不丢弃任何内容
+					chunkPieces = c.splitOversizedChunk(chunk, sizeLimit)
+					if len(chunkPieces) > 1 {
+						splitOccurred = true
+					}
+
+					splitLogCtx := NewLogContext("postProcessChunks").
+						WithNodeInfo(chunk.Type, chunk.ID).
+						WithMetadata("original_size", chunkSize).
+						WithMetadata("max_size", sizeLimit).
+						WithMetadata("size_unit", c.config.SizeUnit.String()).
+						WithMetadata("split_total", len(chunkPieces))
+					c.logWithContext("info", "拆分超大块内容", splitLogCtx)
+				} else {
+					oversizeLogCtx := NewLogContext("postProcessChunks").
+						WithNodeInfo(chunk.Type, chunk.ID).
+						WithMetadata("chunk_size", chunkSize).
+						WithMetadata("max_size", sizeLimit).
+						WithMetadata("size_unit", c.config.SizeUnit.String()).
+						WithMetadata("size_ratio", float64(chunkSize)/float64(sizeLimit))
+					c.logWithContext("warn", "块大小超过限制", oversizeLogCtx)
+
+					err := NewChunkerError(ErrorTypeChunkTooLarge, "生成的块大小超过配置限制", nil).
+						WithContext("function", "ChunkDocument").
+						WithContext("chunk_id", chunk.ID).
+						WithContext("chunk_type", chunk.Type).
+						WithContext("chunk_size", chunkSize).
+						WithContext("chunk_size_bytes", len(chunk.Content)).
+						WithContext("max_size", sizeLimit).
+						WithContext("max_size_bytes", c.config.MaxChunkSize).
+						WithContext("size_unit", c.config.SizeUnit.String()).
+						WithContext("size_ratio", float64(chunkSize)/float64(sizeLimit)).
+						WithContext("content_preview", truncateUTF8(chunk.Content, 100)).
+						WithContext("handling_mode", c.config.ErrorHandling).
+						WithContext("recommendation", "考虑增加MaxChunkSize或启用OversizeModeSplit拆分模式")
+
+					if handlerErr := c.errorHandler.HandleError(err); handlerErr != nil {
+						return nil, handlerErr
+					}
+
+					// 在宽松模式下截断内容
+					if c.config.ErrorHandling != ErrorModeStrict {
+						truncateLogCtx := NewLogContext("postProcessChunks").
+							WithNodeInfo(chunk.Type, chunk.ID).
+							WithMetadata("original_size", chunkSize).
+							WithMetadata("truncated_size", sizeLimit)
+						c.logWithContext("info", "截断超大块内容", truncateLogCtx)
+
+						chunk.Content = c.truncateToSize(chunk.Content, sizeLimit)
+						chunk.Text = c.truncateToSize(chunk.Text, sizeLimit)
+						chunkPieces[0] = chunk
 					}
 				}
 			}
+			pieces = append(pieces, chunkPieces...)
 		}
 
-		c.chunks = append(c.chunks, chunk)
+		for _, piece := range pieces {
+			// 应用自定义元数据提取器（需要重新解析以获取AST节点）
+			c.applyCustomExtractors(&piece)
 
-		// 记录处理的块
-		c.performanceMonitor.RecordChunk(&chunk)
+			// 复制配置的前置元数据键
+			c.applyFrontMatterMetadata(&piece)
 
-		// 记录成功处理的块
-		successChunkLogCtx := NewLogContext("ChunkDocument").
-			WithNodeInfo(chunk.Type, chunk.ID).
-			WithContentInfo(len(chunk.Content), len(chunk.Text), len(strings.Fields(chunk.Text)))
-		c.logWithContext("debug", "成功处理块", successChunkLogCtx)
+			// 生成带上下文前缀的嵌入文本
+			c.applyEmbeddingText(&piece)
+
+			// 记录 token 数，便于下游直接使用
+			piece.TokenCount = c.countTokens(piece.Content)
+
+			// AST 节点只在分块过程中使用，不随结果返回
+			piece.node = nil
+
+			chunks = append(chunks, piece)
+
+			// 记录处理的块
+			c.performanceMonitor.RecordChunk(&piece)
+
+			// 记录成功处理的块
+			successChunkLogCtx := NewLogContext("postProcessChunks").
+				WithNodeInfo(piece.Type, piece.ID).
+				WithContentInfo(len(piece.Content), len(piece.Text), len(strings.Fields(piece.Text)))
+			c.logWithContext("debug", "成功处理块", successChunkLogCtx)
+		}
 
 		// 每处理100个块记录一次进度（用于大型文档）
-		if i%100 == 0 && len(content) > 1024*1024 { // 只对大于1MB的文档记录进度
+		if i%100 == 0 && contentSize > 1024*1024 { // 只对大于1MB的文档记录进度
 			// 检查内存使用情况
 			c.performanceMonitor.CheckMemoryThresholds()
 
 			// 如果启用了内存优化器，检查内存限制
 			if c.memoryOptimizer != nil {
 				if err := c.memoryOptimizer.CheckMemoryLimit(); err != nil {
-					memoryErrorLogCtx := NewLogContext("ChunkDocument").
-						WithDocumentInfo(len(content), len(c.chunks)).
+					memoryErrorLogCtx := NewLogContext("postProcessChunks").
+						WithDocumentInfo(contentSize, len(chunks)).
 						WithMetadata("processed_chunks", i).
 						WithMetadata("error", err.Error())
 					c.logWithContext("warn", "内存限制检查失败", memoryErrorLogCtx)
@@ -1931,58 +2436,65 @@ func (c *MarkdownChunker) ChunkDocument(content []byte) ([]Chunk, error) {
 				}
 
 				// 记录已处理的字节数（用于GC触发）
-				c.memoryOptimizer.RecordProcessedBytes(int64(len(content)) / 100) // 分摊到每100个块
+				c.memoryOptimizer.RecordProcessedBytes(int64(contentSize) / 100) // 分摊到每100个块
 			}
 
-			progressLogCtx := NewLogContext("ChunkDocument").
-				WithDocumentInfo(len(content), len(c.chunks)).
+			progressLogCtx := NewLogContext("postProcessChunks").
+				WithDocumentInfo(contentSize, len(chunks)).
 				WithMetadata("processed_chunks", i).
 				WithMetadata("total_chunks", len(strategyChunks)).
-				WithMetadata("document_size_mb", len(content)/(1024*1024)).
+				WithMetadata("document_size_mb", contentSize/(1024*1024)).
 				WithMetadata("progress_percentage", float64(i*100)/float64(len(strategyChunks)))
 			c.logWithContext("info", "文档处理进度", progressLogCtx)
 		}
 	}
 
-	strategyNameForLog := "unknown"
-	if c.strategy != nil {
-		strategyNameForLog = c.strategy.GetName()
+	// 拆分会产生新的块，重新编号以保证ID唯一且连续
+	if splitOccurred {
+		for i := range chunks {
+			chunks[i].ID = i
+		}
 	}
-	completeStrategyLogCtx := NewLogContext("ChunkDocument").
-		WithDocumentInfo(len(content), len(c.chunks)).
-		WithMetadata("strategy", strategyNameForLog).
-		WithMetadata("total_chunks", len(strategyChunks)).
-		WithMetadata("filtered_chunks", len(c.chunks))
-	c.logWithContext("debug", "完成策略分块处理", completeStrategyLogCtx)
 
-	// 最终的资源使用情况检查和报告
-	if c.memoryOptimizer != nil {
-		// 检查最终内存状态
-		if err := c.memoryOptimizer.CheckMemoryLimit(); err != nil {
-			finalMemoryLogCtx := NewLogContext("ChunkDocument").
-				WithDocumentInfo(len(content), len(c.chunks)).
-				WithMetadata("error", err.Error()).
-				WithMetadata("recommendation", "考虑在后续处理中释放资源")
-			c.logWithContext("warn", "处理完成时内存使用仍然较高", finalMemoryLogCtx)
-		}
+	// 拆分和过滤会改变块列表，按最终结果重新建立导航关系
+	return linkChunks(chunks), nil
+}
 
-		// 获取内存优化器统计信息
-		memStats := c.memoryOptimizer.GetMemoryStats()
-		memStatsLogCtx := NewLogContext("ChunkDocument").
-			WithDocumentInfo(len(content), len(c.chunks)).
-			WithMetadata("current_memory_mb", memStats.CurrentMemory/(1024*1024)).
-			WithMetadata("memory_limit_mb", memStats.MemoryLimit/(1024*1024)).
-			WithMetadata("processed_bytes_mb", memStats.ProcessedBytes/(1024*1024)).
-			WithMetadata("gc_threshold_mb", memStats.GCThreshold/(1024*1024)).
-			WithMetadata("total_allocations_mb", memStats.TotalAllocations/(1024*1024)).
-			WithMetadata("gc_cycles", memStats.GCCycles)
-		c.logWithContext("info", "内存优化器统计", memStatsLogCtx)
+// applyFrontMatterMetadata 将配置的前置元数据键复制到块元数据中
+func (c *MarkdownChunker) applyFrontMatterMetadata(chunk *Chunk) {
+	if c.frontMatter == nil || len(c.config.FrontMatterKeys) == 0 {
+		return
 	}
 
-	// 最终性能检查
-	c.performanceMonitor.CheckMemoryThresholds()
+	if chunk.Metadata == nil {
+		chunk.Metadata = make(map[string]string)
+	}
+	maps.Copy(chunk.Metadata, frontMatterMetadata(c.frontMatter, c.config.FrontMatterKeys))
+}
 
-	return c.chunks, nil
+// applyCustomExtractors 对块应用自定义元数据提取器
+func (c *MarkdownChunker) applyCustomExtractors(chunk *Chunk) {
+	if len(c.config.CustomExtractors) == 0 {
+		return
+	}
+
+	// 为了应用自定义提取器，我们需要重新解析这个块的内容
+	// 这是一个权衡：为了保持兼容性，我们在这里重新解析
+	reader := text.NewReader([]byte(chunk.Content))
+	chunkDoc := c.md.Parser().Parse(reader)
+	if chunkDoc == nil || chunkDoc.FirstChild() == nil {
+		return
+	}
+
+	if chunk.Metadata == nil {
+		chunk.Metadata = make(map[string]string)
+	}
+	for _, extractor := range c.config.CustomExtractors {
+		supportedTypes := extractor.SupportedTypes()
+		if len(supportedTypes) == 0 || slices.Contains(supportedTypes, chunk.Type) {
+			maps.Copy(chunk.Metadata, extractor.Extract(chunkDoc.FirstChild(), []byte(chunk.Content)))
+		}
+	}
 }
 
 // executeStrategyWithRecovery 执行策略分块，包含错误恢复机制
@@ -2161,6 +2673,7 @@ func (c *MarkdownChunker) recoverWithDefaultStrategy() error {
 
 	// 设置为当前策略
 	c.strategy = clonedStrategy
+	c.invalidateStrategyCache()
 
 	c.logWithContext("info", "成功恢复到默认策略", logCtx.WithMetadata("recovery_strategy", c.strategy.GetName()))
 	return nil
@@ -2237,6 +2750,33 @@ func (c *MarkdownChunker) createStrategyExecutionContext(doc ast.Node, content [
 	return context
 }
 
+// elementChunkTypes 由单个 Markdown 元素生成的块类型
+var elementChunkTypes = map[string]bool{
+	"heading": true, "paragraph": true, "code": true,
+	"table": true, "list": true, "blockquote": true,
+	"thematic_break": true, "html": true, "footnote": true,
+	"math": true, "admonition": true,
+}
+
+// synthesizedChunkTypes 由策略合成的块类型，不对应单个 Markdown 元素
+var synthesizedChunkTypes = map[string]bool{
+	"document": true, "packed": true, "window": true,
+	"semantic": true, "qa": true, "slide": true,
+}
+
+// isValidChunkType 检查块类型是否有效，配置校验和策略输出校验共用
+func isValidChunkType(chunkType string) bool {
+	return elementChunkTypes[chunkType] || synthesizedChunkTypes[chunkType]
+}
+
+// validChunkTypeNames 返回排序后的有效块类型列表，用于错误信息
+func validChunkTypeNames() []string {
+	names := slices.Collect(maps.Keys(elementChunkTypes))
+	names = append(names, slices.Collect(maps.Keys(synthesizedChunkTypes))...)
+	slices.Sort(names)
+	return names
+}
+
 // validateStrategyOutput 验证策略输出的有效性
 func (c *MarkdownChunker) validateStrategyOutput(chunks []Chunk, context map[string]any) error {
 	logCtx := NewLogContext("validateStrategyOutput").
@@ -2268,12 +2808,7 @@ func (c *MarkdownChunker) validateStrategyOutput(chunks []Chunk, context map[str
 		}
 
 		// 检查块类型是否有效
-		validTypes := map[string]bool{
-			"heading": true, "paragraph": true, "code": true,
-			"table": true, "list": true, "blockquote": true,
-			"thematic_break": true, "document": true,
-		}
-		if !validTypes[chunk.Type] {
+		if !isValidChunkType(chunk.Type) {
 			c.logWithContext("warn", "发现无效的块类型", logCtx.WithMetadata("chunk_id", chunk.ID).WithMetadata("invalid_type", chunk.Type))
 		}
 
@@ -2296,9 +2831,12 @@ func (c *MarkdownChunker) sanitizeStrategyOutput(chunks []Chunk) []Chunk {
 
 	var sanitizedChunks []Chunk
 	idMap := make(map[int]bool)
+	// 原 ID 到新 ID 的映射，重复的 ID 以第一次出现的块为准，用于修正导航关系
+	linkMap := make(map[int]int)
 	nextID := 0
 
 	for _, chunk := range chunks {
+		originalID := chunk.ID
 		// 修复重复或无效的ID
 		if idMap[chunk.ID] || chunk.ID < 0 {
 			c.logWithContext("debug", "修复块ID", logCtx.WithMetadata("original_id", chunk.ID).WithMetadata("new_id", nextID))
@@ -2314,12 +2852,7 @@ func (c *MarkdownChunker) sanitizeStrategyOutput(chunks []Chunk) []Chunk {
 		}
 
 		// 修复块类型
-		validTypes := map[string]bool{
-			"heading": true, "paragraph": true, "code": true,
-			"table": true, "list": true, "blockquote": true,
-			"thematic_break": true, "document": true,
-		}
-		if !validTypes[chunk.Type] {
+		if !isValidChunkType(chunk.Type) {
 			c.logWithContext("debug", "修复块类型", logCtx.WithMetadata("chunk_id", chunk.ID).WithMetadata("original_type", chunk.Type))
 			chunk.Type = "paragraph" // 默认类型
 		}
@@ -2334,9 +2867,15 @@ func (c *MarkdownChunker) sanitizeStrategyOutput(chunks []Chunk) []Chunk {
 		chunk.Metadata["sanitized"] = "true"
 		chunk.Metadata["sanitized_at"] = time.Now().Format(time.RFC3339)
 
+		if _, ok := linkMap[originalID]; !ok {
+			linkMap[originalID] = chunk.ID
+		}
 		sanitizedChunks = append(sanitizedChunks, chunk)
 	}
 
+	// 保持父子和前后关系与修复后的 ID 一致
+	sanitizedChunks = remapChunkLinks(sanitizedChunks, linkMap)
+
 	c.logWithContext("info", "策略输出修复完成", logCtx.
 		WithMetadata("sanitized_chunks_count", len(sanitizedChunks)).
 		WithMetadata("removed_chunks", len(chunks)-len(sanitizedChunks)))
@@ -2355,6 +2894,17 @@ func (c *MarkdownChunker) getOptimizedStrategyInstance(strategyName string) Chun
 	}
 
 	// 如果缓存中没有，从注册器获取并缓存
+	// 当前策略可能带有自定义配置，而注册器中的实例只有默认配置，因此同名时缓存当前策略
+	if c.strategy != nil && c.strategy.GetName() == strategyName {
+		strategy := c.strategy.Clone()
+		c.strategyCache.Put(strategyName, strategy)
+
+		// 从池中获取实例
+		return c.strategyPool.Get(strategyName, func() ChunkingStrategy {
+			return strategy.Clone()
+		})
+	}
+
 	if strategy, err := c.strategyRegistry.Get(strategyName); err == nil {
 		c.strategyCache.Put(strategyName, strategy)
 
@@ -2368,6 +2918,24 @@ func (c *MarkdownChunker) getOptimizedStrategyInstance(strategyName string) Chun
 	return c.strategy.Clone()
 }
 
+// invalidateStrategyCache 移除当前策略的缓存和实例池，切换策略或更新配置后调用，避免沿用旧配置
+func (c *MarkdownChunker) invalidateStrategyCache() {
+	if c.strategy == nil {
+		return
+	}
+	c.removeCachedStrategy(c.strategy.GetName())
+}
+
+// removeCachedStrategy 移除指定策略的缓存和实例池
+func (c *MarkdownChunker) removeCachedStrategy(strategyName string) {
+	if c.strategyCache == nil || c.strategyPool == nil {
+		return
+	}
+
+	c.strategyCache.Remove(strategyName)
+	c.strategyPool.RemovePool(strategyName)
+}
+
 // returnStrategyInstance 将策略实例返回到池中
 func (c *MarkdownChunker) returnStrategyInstance(strategyName string, strategy ChunkingStrategy) {
 	log.Info("Returning strategy instance to pool", "strategy", strategyName)
@@ -2483,12 +3051,33 @@ func (c *MarkdownChunker) processNode(node ast.Node, id int) *Chunk {
 		chunk = c.processBlockquote(n, id)
 	case *ast.ThematicBreak:
 		chunk = c.processThematicBreak(n, id)
+	case *ast.HTMLBlock:
+		chunk = c.processHTMLBlock(n, id)
+	case *mathBlock:
+		chunk = c.processMathBlock(n, id)
+	case *Admonition:
+		chunk = c.processAdmonition(n, id)
+	case *extast.FootnoteList:
+		// 脚注定义默认附加到引用它们的块中，只有单独模式才输出脚注块
+		if c.footnoteMode() != FootnoteModeSeparate {
+			skipLogCtx := NewLogContext("processNode").WithNodeInfo(nodeType, id)
+			c.logWithContext("debug", "跳过脚注定义列表", skipLogCtx)
+			return nil
+		}
+		chunk = c.processFootnoteList(n, id)
 	default:
 		skipLogCtx := NewLogContext("processNode").WithNodeInfo(nodeType, id)
 		c.logWithContext("debug", "跳过不支持的节点类型", skipLogCtx)
 		return nil
 	}
 
+	// 关联块中引用的脚注和所在章节
+	if chunk != nil {
+		c.attachFootnotes(chunk, node)
+		c.attachSectionInfo(chunk, node)
+		chunk.node = node
+	}
+
 	// 记录节点处理结果日志
 	if chunk != nil {
 		successLogCtx := NewLogContext("processNode").
@@ -2586,6 +3175,19 @@ func (c *MarkdownChunker) processParagraph(para *ast.Paragraph, id int) *Chunk {
 
 	c.logWithContext("debug", "段落内容提取完成", completeParaLogCtx)
 
+	metadata := map[string]string{
+		"word_count": fmt.Sprintf("%d", len(strings.Fields(text))),
+		"char_count": fmt.Sprintf("%d", len(text)),
+		// 添加位置信息到元数据以保持向后兼容性
+		"line_start": fmt.Sprintf("%d", position.StartLine),
+		"line_end":   fmt.Sprintf("%d", position.EndLine),
+		"char_start": fmt.Sprintf("%d", position.StartCol),
+		"char_end":   fmt.Sprintf("%d", position.EndCol),
+	}
+	if inlineMathCount := countInlineMath(para); inlineMathCount > 0 {
+		metadata["inline_math_count"] = fmt.Sprintf("%d", inlineMathCount)
+	}
+
 	return &Chunk{
 		ID:       id,
 		Type:     "paragraph",
@@ -2596,15 +3198,7 @@ func (c *MarkdownChunker) processParagraph(para *ast.Paragraph, id int) *Chunk {
 		Links:    links,
 		Images:   images,
 		Hash:     hash,
-		Metadata: map[string]string{
-			"word_count": fmt.Sprintf("%d", len(strings.Fields(text))),
-			"char_count": fmt.Sprintf("%d", len(text)),
-			// 添加位置信息到元数据以保持向后兼容性
-			"line_start": fmt.Sprintf("%d", position.StartLine),
-			"line_end":   fmt.Sprintf("%d", position.EndLine),
-			"char_start": fmt.Sprintf("%d", position.StartCol),
-			"char_end":   fmt.Sprintf("%d", position.EndCol),
-		},
+		Metadata: metadata,
 	}
 }
 
@@ -2946,6 +3540,166 @@ func (c *MarkdownChunker) processThematicBreak(hr *ast.ThematicBreak, id int) *C
 	}
 }
 
+// processMathBlock 处理行间公式块
+func (c *MarkdownChunker) processMathBlock(block *mathBlock, id int) *Chunk {
+	// 创建公式块处理日志上下文
+	mathLogCtx := NewLogContext("processMathBlock").WithNodeInfo("MathBlock", id)
+	c.logWithContext("debug", "处理公式块节点", mathLogCtx)
+
+	content := mathBlockLatex(block, c.source)
+	text := latexToText(content)
+
+	position := c.calculatePosition(block)
+	hash := c.calculateContentHash(content)
+
+	// 记录提取的内容统计信息
+	completeMathLogCtx := NewLogContext("processMathBlock").
+		WithNodeInfo("MathBlock", id).
+		WithContentInfo(len(content), len(text), len(strings.Fields(text))).
+		WithPositionInfo(position.StartLine, position.EndLine, position.StartCol, position.EndCol)
+
+	c.logWithContext("debug", "公式块内容提取完成", completeMathLogCtx)
+
+	return &Chunk{
+		ID:       id,
+		Type:     "math",
+		Content:  content,
+		Text:     text,
+		Level:    0,
+		Position: position,
+		Links:    []Link{},
+		Images:   []Image{},
+		Hash:     hash,
+		Metadata: map[string]string{
+			"math_type":  "display",
+			"line_count": fmt.Sprintf("%d", len(strings.Split(content, "\n"))),
+			// 添加位置信息到元数据以保持向后兼容性
+			"line_start": fmt.Sprintf("%d", position.StartLine),
+			"line_end":   fmt.Sprintf("%d", position.EndLine),
+			"char_start": fmt.Sprintf("%d", position.StartCol),
+			"char_end":   fmt.Sprintf("%d", position.EndCol),
+		},
+	}
+}
+
+// processAdmonition 处理提示块
+func (c *MarkdownChunker) processAdmonition(block *Admonition, id int) *Chunk {
+	// 创建提示块处理日志上下文
+	admonitionLogCtx := NewLogContext("processAdmonition").
+		WithNodeInfo("Admonition", id).
+		WithMetadata("syntax", block.Syntax).
+		WithMetadata("admonition_type", block.AdmonitionType)
+	c.logWithContext("debug", "处理提示块节点", admonitionLogCtx)
+
+	content := c.getAdmonitionContent(block)
+	text := c.getAdmonitionText(block)
+	if block.Title != "" {
+		// 标题也是检索时有用的内容
+		text = strings.TrimSpace(block.Title + " " + text)
+	}
+
+	position := c.calculatePosition(block)
+	if block.Syntax != AdmonitionSyntaxGitHub {
+		// 原始行包含换行符，按内容重新计算结束位置
+		contentLines := strings.Split(content, "\n")
+		position.EndLine = position.StartLine + len(contentLines) - 1
+		position.EndCol = len(contentLines[len(contentLines)-1]) + 1
+	}
+
+	links := c.extractLinks(block)
+	images := c.extractImages(block)
+	hash := c.calculateContentHash(content)
+
+	// 记录提取的内容统计信息
+	completeAdmonitionLogCtx := NewLogContext("processAdmonition").
+		WithNodeInfo("Admonition", id).
+		WithContentInfo(len(content), len(text), len(strings.Fields(text))).
+		WithPositionInfo(position.StartLine, position.EndLine, position.StartCol, position.EndCol).
+		WithLinksAndImages(len(links), len(images))
+
+	c.logWithContext("debug", "提示块内容提取完成", completeAdmonitionLogCtx)
+
+	metadata := map[string]string{
+		"admonition_kind":   admonitionKind(block.AdmonitionType),
+		"admonition_type":   block.AdmonitionType,
+		"admonition_syntax": block.Syntax,
+		"word_count":        fmt.Sprintf("%d", len(strings.Fields(text))),
+		// 添加位置信息到元数据以保持向后兼容性
+		"line_start": fmt.Sprintf("%d", position.StartLine),
+		"line_end":   fmt.Sprintf("%d", position.EndLine),
+		"char_start": fmt.Sprintf("%d", position.StartCol),
+		"char_end":   fmt.Sprintf("%d", position.EndCol),
+	}
+	if block.Title != "" {
+		metadata["admonition_title"] = block.Title
+	}
+
+	return &Chunk{
+		ID:       id,
+		Type:     "admonition",
+		Content:  content,
+		Text:     text,
+		Level:    0,
+		Position: position,
+		Links:    links,
+		Images:   images,
+		Hash:     hash,
+		Metadata: metadata,
+	}
+}
+
+// processHTMLBlock 处理 HTML 块
+func (c *MarkdownChunker) processHTMLBlock(block *ast.HTMLBlock, id int) *Chunk {
+	// 创建HTML块处理日志上下文
+	htmlLogCtx := NewLogContext("processHTMLBlock").WithNodeInfo("HTMLBlock", id)
+	c.logWithContext("debug", "处理HTML块节点", htmlLogCtx)
+
+	content := c.getHTMLBlockContent(block)
+	text := htmlVisibleText(content)
+	tagName := htmlBlockTagName(content)
+
+	// HTML 块的行包含换行符，按内容重新计算结束位置
+	position := c.calculatePosition(block)
+	contentLines := strings.Split(content, "\n")
+	position.EndLine = position.StartLine + len(contentLines) - 1
+	position.EndCol = len(contentLines[len(contentLines)-1]) + 1
+
+	links := c.extractHTMLLinks(content)
+	images := extractHTMLImages(content)
+	hash := c.calculateContentHash(content)
+
+	// 记录提取的内容统计信息
+	completeHTMLLogCtx := NewLogContext("processHTMLBlock").
+		WithNodeInfo("HTMLBlock", id).
+		WithMetadata("tag_name", tagName).
+		WithContentInfo(len(content), len(text), len(strings.Fields(text))).
+		WithPositionInfo(position.StartLine, position.EndLine, position.StartCol, position.EndCol).
+		WithLinksAndImages(len(links), len(images))
+
+	c.logWithContext("debug", "HTML块内容提取完成", completeHTMLLogCtx)
+
+	return &Chunk{
+		ID:       id,
+		Type:     "html",
+		Content:  content,
+		Text:     text,
+		Level:    0,
+		Position: position,
+		Links:    links,
+		Images:   images,
+		Hash:     hash,
+		Metadata: map[string]string{
+			"tag_name":   tagName,
+			"word_count": fmt.Sprintf("%d", len(strings.Fields(text))),
+			// 添加位置信息到元数据以保持向后兼容性
+			"line_start": fmt.Sprintf("%d", position.StartLine),
+			"line_end":   fmt.Sprintf("%d", position.EndLine),
+			"char_start": fmt.Sprintf("%d", position.StartCol),
+			"char_end":   fmt.Sprintf("%d", position.EndCol),
+		},
+	}
+}
+
 // getNodeRawContent 获取节点的原始 markdown 内容
 func (c *MarkdownChunker) getNodeRawContent(node ast.Node) string {
 	// 特殊处理某些节点类型
@@ -3041,7 +3795,13 @@ func (c *MarkdownChunker) getNodeRawContent(node ast.Node) string {
 }
 
 // reconstructList 重构列表的原始markdown
+// 保留有序列表的起始编号，嵌套列表按父列表标记宽度缩进
 func (c *MarkdownChunker) reconstructList(list *ast.List) string {
+	// 启用列表拆分时渲染嵌套列表并保留起始编号，拆分片段才能带上完整的祖先项链
+	if c.config != nil && c.config.SplitLists {
+		return c.renderList(list)
+	}
+
 	var buf bytes.Buffer
 	itemIndex := 1
 
@@ -3228,6 +3988,9 @@ func (c *MarkdownChunker) getNodeText(node ast.Node) string {
 						}
 					}
 				}
+			case kindInlineMath:
+				// 行内公式原样保留，包括 $ 分隔符
+				buf.Write(n.(*inlineMath).Segment.Value(c.source))
 			case ast.KindEmphasis:
 				// 强调标记本身不添加文本，只处理其子节点
 			case ast.KindLink:
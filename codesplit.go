@@ -0,0 +1,304 @@
+package markdownchunker
+
+import (
+	"fmt"
+	"maps"
+	"regexp"
+	"strings"
+)
+
+// 代码拆分的边界层级，按优先级从粗到细排列
+const (
+	codeSplitLevelDeclaration = iota // 顶层声明边界
+	codeSplitLevelBlankLine          // 空行边界
+	codeSplitLevelLine               // 行边界（不会拆开单行）
+)
+
+var (
+	// codeFenceOpenPattern 匹配开始围栏行
+	codeFenceOpenPattern = regexp.MustCompile("^ {0,3}(`{3,}|~{3,})(.*)$")
+
+	// codeDeclarationCommentPattern 匹配紧邻声明之前、应归属该声明的注释和装饰器行
+	codeDeclarationCommentPattern = regexp.MustCompile(`^\s*(//|#|/\*|\*|@|--|"""|''')`)
+
+	goDeclarationPattern     = regexp.MustCompile(`^(func|type|var|const)\b`)
+	pythonDeclarationPattern = regexp.MustCompile(`^(async\s+def|def|class)\b`)
+	jsDeclarationPattern     = regexp.MustCompile(`^(export\s+)?(default\s+)?(declare\s+)?(async\s+)?(function\*?|class|interface|type|enum|const|let|var|namespace)\b`)
+	jvmDeclarationPattern    = regexp.MustCompile(`^((public|private|protected|internal|static|final|abstract|sealed|open|data|partial)\s+)*(class|interface|enum|record|object|fun|struct|namespace|trait|def)\b`)
+	rustDeclarationPattern   = regexp.MustCompile(`^(pub(\([^)]*\))?\s+)?((async|unsafe|const|extern)\s+)*(fn|struct|enum|impl|trait|mod|type|const|static|macro_rules!)`)
+	cDeclarationPattern      = regexp.MustCompile(`^((struct|class|union|enum|typedef|namespace|template)\b|[A-Za-z_][\w:<>,\*&\s]*[\s\*&]\**~?[A-Za-z_][\w:]*\s*\([^;]*$)`)
+	rubyDeclarationPattern   = regexp.MustCompile(`^(def|class|module)\b`)
+	phpDeclarationPattern    = regexp.MustCompile(`^((abstract|final)\s+)?(function|class|interface|trait|enum)\b`)
+	swiftDeclarationPattern  = regexp.MustCompile(`^((public|private|internal|fileprivate|open|final)\s+)*(func|class|struct|enum|protocol|extension|actor)\b`)
+	shellDeclarationPattern  = regexp.MustCompile(`^(function\s+[\w-]+|[A-Za-z_][\w-]*\s*\(\)\s*\{?)`)
+)
+
+// codeDeclarationPatterns 语言标识到顶层声明正则的映射
+var codeDeclarationPatterns = map[string]*regexp.Regexp{
+	"go": goDeclarationPattern, "golang": goDeclarationPattern,
+	"python": pythonDeclarationPattern, "py": pythonDeclarationPattern, "python3": pythonDeclarationPattern,
+	"javascript": jsDeclarationPattern, "js": jsDeclarationPattern, "jsx": jsDeclarationPattern, "mjs": jsDeclarationPattern,
+	"typescript": jsDeclarationPattern, "ts": jsDeclarationPattern, "tsx": jsDeclarationPattern,
+	"java": jvmDeclarationPattern, "kotlin": jvmDeclarationPattern, "kt": jvmDeclarationPattern,
+	"scala": jvmDeclarationPattern, "csharp": jvmDeclarationPattern, "cs": jvmDeclarationPattern, "c#": jvmDeclarationPattern,
+	"rust": rustDeclarationPattern, "rs": rustDeclarationPattern,
+	"c": cDeclarationPattern, "h": cDeclarationPattern, "cpp": cDeclarationPattern, "c++": cDeclarationPattern,
+	"cc": cDeclarationPattern, "hpp": cDeclarationPattern, "objc": cDeclarationPattern,
+	"ruby": rubyDeclarationPattern, "rb": rubyDeclarationPattern,
+	"php":   phpDeclarationPattern,
+	"swift": swiftDeclarationPattern,
+	"bash":  shellDeclarationPattern, "sh": shellDeclarationPattern, "shell": shellDeclarationPattern, "zsh": shellDeclarationPattern,
+}
+
+// codeFence 解析后的围栏代码块
+type codeFence struct {
+	opening  string   // 开始围栏行（包含信息字符串）
+	closing  string   // 结束围栏
+	language string   // 语言标识（信息字符串的第一个单词，小写）
+	lines    []string // 代码行
+}
+
+// lineRange 表示代码行区间 [start, end)
+type lineRange struct {
+	start int
+	end   int
+}
+
+// parseCodeFence 解析围栏代码块的原始内容
+func parseCodeFence(content string) (*codeFence, bool) {
+	lines := strings.Split(strings.TrimRight(content, "\n"), "\n")
+	if len(lines) == 0 {
+		return nil, false
+	}
+
+	match := codeFenceOpenPattern.FindStringSubmatch(lines[0])
+	if match == nil {
+		return nil, false
+	}
+
+	marker := match[1]
+	fence := &codeFence{
+		opening:  strings.TrimLeft(lines[0], " "),
+		closing:  marker,
+		language: codeLanguageFromInfo(match[2]),
+	}
+
+	body := lines[1:]
+	if len(body) > 0 {
+		last := strings.TrimSpace(body[len(body)-1])
+		if strings.HasPrefix(last, marker) && strings.Trim(last, marker[:1]) == "" {
+			fence.closing = last
+			body = body[:len(body)-1]
+		}
+	}
+	fence.lines = body
+
+	return fence, true
+}
+
+// codeLanguageFromInfo 从围栏信息字符串中提取语言标识
+func codeLanguageFromInfo(info string) string {
+	fields := strings.Fields(info)
+	if len(fields) == 0 {
+		return ""
+	}
+	language := strings.ToLower(fields[0])
+	language = strings.TrimPrefix(language, "{")
+	language = strings.TrimPrefix(language, ".")
+	language = strings.TrimSuffix(language, "}")
+	return language
+}
+
+// render 将指定行区间重新包装为围栏代码块
+func (f *codeFence) render(r lineRange) string {
+	return f.opening + "\n" + strings.Join(f.lines[r.start:r.end], "\n") + "\n" + f.closing
+}
+
+// shouldSplitCodeChunk 判断代码块是否需要按语言拆分
+func (c *MarkdownChunker) shouldSplitCodeChunk(chunk Chunk) bool {
+	if c.config == nil || !c.config.SplitCodeBlocks || chunk.Type != "code" {
+		return false
+	}
+	limit := c.codeSplitLimit(chunk)
+	return limit > 0 && c.measureSize(chunk.Content) > limit
+}
+
+// codeSplitLimit 返回代码块拆分的大小上限
+func (c *MarkdownChunker) codeSplitLimit(chunk Chunk) int {
+	if c.config == nil {
+		return 0
+	}
+	return c.splitLimit(chunk, c.config.CodeBlockMaxSize)
+}
+
+// splitCodeChunk 在顶层声明边界处拆分围栏代码块，无法识别语言时按空行拆分
+// 每个片段都使用原始围栏和语言标识重新包装
+func (c *MarkdownChunker) splitCodeChunk(chunk Chunk, maxSize int) []Chunk {
+	fence, ok := parseCodeFence(chunk.Content)
+	if !ok || maxSize <= 0 || c.measureSize(chunk.Content) <= maxSize {
+		return []Chunk{chunk}
+	}
+
+	level := codeSplitLevelDeclaration
+	if codeDeclarationPatterns[fence.language] == nil {
+		level = codeSplitLevelBlankLine
+	}
+
+	ranges := c.splitCodeRange(fence, lineRange{0, len(fence.lines)}, maxSize, level)
+	ranges = trimBlankLineRanges(fence.lines, ranges)
+	if len(ranges) <= 1 {
+		return []Chunk{chunk}
+	}
+
+	pieces := make([]Chunk, 0, len(ranges))
+	for i, r := range ranges {
+		content := fence.render(r)
+		text := strings.Join(fence.lines[r.start:r.end], "\n")
+		lastLine := fence.lines[r.end-1]
+
+		position := ChunkPosition{
+			StartLine: chunk.Position.StartLine + r.start,
+			EndLine:   chunk.Position.StartLine + r.end - 1,
+			StartCol:  chunk.Position.StartCol,
+			EndCol:    len(lastLine) + 1,
+		}
+
+		metadata := make(map[string]string, len(chunk.Metadata)+4)
+		maps.Copy(metadata, chunk.Metadata)
+		metadata["code_part"] = fmt.Sprintf("%d", i+1)
+		metadata["code_part_total"] = fmt.Sprintf("%d", len(ranges))
+		metadata["original_chunk_id"] = fmt.Sprintf("%d", chunk.ID)
+		metadata["line_count"] = fmt.Sprintf("%d", r.end-r.start)
+		metadata["line_start"] = fmt.Sprintf("%d", position.StartLine)
+		metadata["line_end"] = fmt.Sprintf("%d", position.EndLine)
+		metadata["char_start"] = fmt.Sprintf("%d", position.StartCol)
+		metadata["char_end"] = fmt.Sprintf("%d", position.EndCol)
+
+		pieces = append(pieces, Chunk{
+			ID:          chunk.ID,
+			Type:        chunk.Type,
+			Content:     content,
+			Text:        text,
+			Level:       chunk.Level,
+			Metadata:    metadata,
+			Position:    position,
+			Links:       filterLinksInContent(chunk.Links, content),
+			Images:      filterImagesInContent(chunk.Images, content),
+			Hash:        c.calculateContentHash(content),
+			SectionPath: chunk.SectionPath,
+		})
+	}
+
+	return pieces
+}
+
+// splitCodeRange 在指定层级的边界处拆分代码行区间，过大的片段继续在更细的层级拆分
+func (c *MarkdownChunker) splitCodeRange(fence *codeFence, r lineRange, maxSize, level int) []lineRange {
+	if r.end-r.start <= 1 || c.measureSize(fence.render(r)) <= maxSize || level > codeSplitLevelLine {
+		return []lineRange{r}
+	}
+
+	segments := codeSegments(fence, r, level)
+	if len(segments) <= 1 {
+		return c.splitCodeRange(fence, r, maxSize, level+1)
+	}
+
+	var result []lineRange
+	current := lineRange{start: -1}
+	flush := func() {
+		if current.start >= 0 {
+			result = append(result, current)
+			current = lineRange{start: -1}
+		}
+	}
+
+	// 贪心合并相邻片段，直到达到大小上限
+	for _, segment := range segments {
+		if c.measureSize(fence.render(segment)) > maxSize {
+			flush()
+			result = append(result, c.splitCodeRange(fence, segment, maxSize, level+1)...)
+			continue
+		}
+
+		if current.start < 0 {
+			current = segment
+			continue
+		}
+
+		if c.measureSize(fence.render(lineRange{current.start, segment.end})) <= maxSize {
+			current.end = segment.end
+		} else {
+			flush()
+			current = segment
+		}
+	}
+	flush()
+
+	return result
+}
+
+// codeSegments 按指定层级的边界将代码行区间切分为连续片段
+func codeSegments(fence *codeFence, r lineRange, level int) []lineRange {
+	var starts []int
+
+	switch level {
+	case codeSplitLevelDeclaration:
+		pattern := codeDeclarationPatterns[fence.language]
+		if pattern == nil {
+			break
+		}
+		for i := r.start + 1; i < r.end; i++ {
+			if !pattern.MatchString(fence.lines[i]) {
+				continue
+			}
+			// 将紧邻的注释和装饰器归属到该声明
+			start := i
+			for start-1 > r.start && codeDeclarationCommentPattern.MatchString(fence.lines[start-1]) {
+				start--
+			}
+			if len(starts) == 0 || start > starts[len(starts)-1] {
+				starts = append(starts, start)
+			}
+		}
+	case codeSplitLevelBlankLine:
+		for i := r.start + 1; i < r.end; i++ {
+			if strings.TrimSpace(fence.lines[i]) != "" && strings.TrimSpace(fence.lines[i-1]) == "" {
+				starts = append(starts, i)
+			}
+		}
+	default:
+		for i := r.start + 1; i < r.end; i++ {
+			starts = append(starts, i)
+		}
+	}
+
+	var segments []lineRange
+	previous := r.start
+	for _, start := range starts {
+		if start <= previous {
+			continue
+		}
+		segments = append(segments, lineRange{previous, start})
+		previous = start
+	}
+	segments = append(segments, lineRange{previous, r.end})
+
+	return segments
+}
+
+// trimBlankLineRanges 去除每个区间首尾的空行，并丢弃空区间
+func trimBlankLineRanges(lines []string, ranges []lineRange) []lineRange {
+	var trimmed []lineRange
+	for _, r := range ranges {
+		for r.start < r.end && strings.TrimSpace(lines[r.start]) == "" {
+			r.start++
+		}
+		for r.end > r.start && strings.TrimSpace(lines[r.end-1]) == "" {
+			r.end--
+		}
+		if r.start < r.end {
+			trimmed = append(trimmed, r)
+		}
+	}
+	return trimmed
+}
@@ -0,0 +1,148 @@
+package markdownchunker
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+const goCodeMarkdown = "# Code\n\n```go title=\"main.go\"\npackage main\n\n// First does one thing\nfunc First() {\n\tprintln(\"first\")\n\n\tprintln(\"more\")\n}\n\n// Second does another\nfunc Second() {\n\tprintln(\"second\")\n}\n\ntype Third struct {\n\tName string\n}\n```"
+
+func TestCodeSplit_DisabledByDefault(t *testing.T) {
+	chunker := NewMarkdownChunker()
+	chunks, err := chunker.ChunkDocument([]byte(goCodeMarkdown))
+	if err != nil {
+		t.Fatalf("ChunkDocument() error = %v", err)
+	}
+
+	if len(chunksOfType(chunks, "code")) != 1 {
+		t.Errorf("Expected code block to stay whole by default")
+	}
+}
+
+func TestCodeSplit_GoDeclarations(t *testing.T) {
+	config := DefaultConfig()
+	config.SplitCodeBlocks = true
+	config.CodeBlockMaxSize = 110
+
+	chunker := NewMarkdownChunkerWithConfig(config)
+	chunks, err := chunker.ChunkDocument([]byte(goCodeMarkdown))
+	if err != nil {
+		t.Fatalf("ChunkDocument() error = %v", err)
+	}
+
+	parts := chunksOfType(chunks, "code")
+	if len(parts) != 4 {
+		t.Fatalf("Expected 4 code parts, got %d", len(parts))
+	}
+
+	expectedStarts := []string{"package main", "// First does one thing", "// Second does another", "type Third struct"}
+	expectedLines := [][2]string{{"4", "4"}, {"6", "11"}, {"13", "16"}, {"18", "20"}}
+
+	for i, part := range parts {
+		if !strings.HasPrefix(part.Content, "```go title=\"main.go\"\n") || !strings.HasSuffix(part.Content, "\n```") {
+			t.Errorf("Expected part %d to be re-fenced with the original info string, got %q", i, part.Content)
+		}
+		if !strings.HasPrefix(part.Text, expectedStarts[i]) {
+			t.Errorf("Expected part %d to start with %q, got %q", i, expectedStarts[i], part.Text)
+		}
+		if part.Metadata["line_start"] != expectedLines[i][0] || part.Metadata["line_end"] != expectedLines[i][1] {
+			t.Errorf("Expected part %d lines %v, got %s-%s", i, expectedLines[i], part.Metadata["line_start"], part.Metadata["line_end"])
+		}
+		if part.Metadata["code_part"] != fmt.Sprintf("%d", i+1) || part.Metadata["code_part_total"] != "4" {
+			t.Errorf("Unexpected code_part metadata for part %d: %v", i, part.Metadata)
+		}
+		if part.Metadata["language"] != "go title=\"main.go\"" {
+			t.Errorf("Expected language metadata to be preserved, got %q", part.Metadata["language"])
+		}
+	}
+}
+
+func TestCodeSplit_PythonDecorators(t *testing.T) {
+	markdown := "```python\nimport os\n\n@decorator\ndef first():\n    return 1\n\nclass Second:\n    def method(self):\n        return 2\n```"
+
+	config := DefaultConfig()
+	config.SplitCodeBlocks = true
+	config.CodeBlockMaxSize = 60
+
+	chunker := NewMarkdownChunkerWithConfig(config)
+	chunks, err := chunker.ChunkDocument([]byte(markdown))
+	if err != nil {
+		t.Fatalf("ChunkDocument() error = %v", err)
+	}
+
+	var found bool
+	for _, chunk := range chunks {
+		if strings.HasPrefix(chunk.Text, "@decorator\ndef first():") {
+			found = true
+		}
+		if strings.Contains(chunk.Text, "class Second") && strings.Contains(chunk.Text, "def first") {
+			t.Error("Expected class and function to be in separate parts")
+		}
+	}
+	if !found {
+		t.Error("Expected decorator to stay with its function")
+	}
+}
+
+func TestCodeSplit_UnknownLanguageUsesBlankLines(t *testing.T) {
+	markdown := "```\nblock one line a\nblock one line b\n\nblock two line a\nblock two line b\n\nblock three\n```"
+
+	config := DefaultConfig()
+	config.SplitCodeBlocks = true
+	config.CodeBlockMaxSize = 45
+
+	chunker := NewMarkdownChunkerWithConfig(config)
+	chunks, err := chunker.ChunkDocument([]byte(markdown))
+	if err != nil {
+		t.Fatalf("ChunkDocument() error = %v", err)
+	}
+
+	if len(chunks) != 3 {
+		t.Fatalf("Expected 3 blank-line separated parts, got %d", len(chunks))
+	}
+	if chunks[1].Text != "block two line a\nblock two line b" {
+		t.Errorf("Unexpected second part %q", chunks[1].Text)
+	}
+	for i, chunk := range chunks {
+		if chunk.ID != i {
+			t.Errorf("Expected sequential IDs, got %d at %d", chunk.ID, i)
+		}
+	}
+}
+
+func TestCodeSplit_UsesMaxChunkSizeInSplitMode(t *testing.T) {
+	config := DefaultConfig()
+	config.MaxChunkSize = 80
+	config.OversizeHandling = OversizeModeSplit
+
+	chunker := NewMarkdownChunkerWithConfig(config)
+	chunks, err := chunker.ChunkDocument([]byte(goCodeMarkdown))
+	if err != nil {
+		t.Fatalf("ChunkDocument() error = %v", err)
+	}
+
+	parts := chunksOfType(chunks, "code")
+	if len(parts) < 2 {
+		t.Fatalf("Expected oversized code to be split, got %d parts", len(parts))
+	}
+	for _, part := range parts {
+		if !strings.HasPrefix(part.Content, "```go") {
+			t.Errorf("Expected split-mode code parts to be re-fenced, got %q", part.Content)
+		}
+	}
+}
+
+func TestParseCodeFence(t *testing.T) {
+	fence, ok := parseCodeFence("~~~~{.python}\nprint(1)\n~~~~")
+	if !ok {
+		t.Fatal("Expected fence to be parsed")
+	}
+	if fence.language != "python" || fence.closing != "~~~~" || len(fence.lines) != 1 {
+		t.Errorf("Unexpected fence %+v", fence)
+	}
+
+	if _, ok := parseCodeFence("    indented code"); ok {
+		t.Error("Expected indented code not to be parsed as a fence")
+	}
+}
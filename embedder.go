@@ -0,0 +1,135 @@
+package markdownchunker
+
+import (
+	"hash/fnv"
+	"math"
+	"strings"
+	"unicode"
+)
+
+// DefaultHashEmbedderDimensions 哈希词袋嵌入器的默认向量维度
+const DefaultHashEmbedderDimensions = 512
+
+// Embedder 文本嵌入接口，语义分块策略通过它计算相邻单元的相似度
+// 可以接入本地模型或远程嵌入服务，只需保证返回的向量与输入文本一一对应且维度一致
+type Embedder interface {
+	// Name 返回嵌入器名称
+	Name() string
+
+	// Embed 批量计算文本的嵌入向量
+	Embed(texts []string) ([][]float64, error)
+}
+
+// defaultEmbedder 未配置嵌入器时使用的默认嵌入器
+var defaultEmbedder Embedder = NewHashEmbedder(DefaultHashEmbedderDimensions)
+
+// hashEmbedderStopWords 哈希词袋嵌入器忽略的常见英文虚词
+var hashEmbedderStopWords = map[string]bool{
+	"a": true, "an": true, "the": true, "and": true, "or": true, "but": true,
+	"of": true, "to": true, "in": true, "on": true, "at": true, "by": true,
+	"for": true, "with": true, "from": true, "as": true, "is": true, "are": true,
+	"was": true, "were": true, "be": true, "been": true, "it": true, "its": true,
+	"this": true, "that": true, "these": true, "those": true, "if": true, "then": true,
+	"you": true, "we": true, "they": true, "he": true, "she": true, "i": true,
+	"can": true, "will": true, "not": true, "no": true, "do": true, "does": true,
+}
+
+// HashEmbedder 确定性的本地哈希词袋嵌入器
+// 文本转为小写后按 WhitespaceTokenizer 分词，再以标点拆开并去掉常见虚词，
+// 每个词通过 FNV 哈希映射到固定维度的向量上，最后做 L2 归一化。
+// 不依赖任何模型文件或网络，适合离线使用和测试
+type HashEmbedder struct {
+	dimensions int
+	tokenizer  *WhitespaceTokenizer
+}
+
+// NewHashEmbedder 创建指定维度的哈希词袋嵌入器，维度不大于 0 时使用默认维度
+func NewHashEmbedder(dimensions int) *HashEmbedder {
+	if dimensions <= 0 {
+		dimensions = DefaultHashEmbedderDimensions
+	}
+	return &HashEmbedder{
+		dimensions: dimensions,
+		tokenizer:  NewWhitespaceTokenizer(),
+	}
+}
+
+// Name 返回嵌入器名称
+func (e *HashEmbedder) Name() string {
+	return "hash"
+}
+
+// Dimensions 返回向量维度
+func (e *HashEmbedder) Dimensions() int {
+	return e.dimensions
+}
+
+// Embed 批量计算文本的嵌入向量
+func (e *HashEmbedder) Embed(texts []string) ([][]float64, error) {
+	vectors := make([][]float64, len(texts))
+	for i, text := range texts {
+		vectors[i] = e.embed(text)
+	}
+	return vectors, nil
+}
+
+// embed 计算单个文本的嵌入向量
+func (e *HashEmbedder) embed(text string) []float64 {
+	vector := make([]float64, e.dimensions)
+	for _, word := range e.words(text) {
+		hash := fnv.New64a()
+		hash.Write([]byte(word))
+		sum := hash.Sum64()
+
+		// 用哈希的最高位决定符号，减少不同词落在同一维度时的相互抵消
+		weight := 1.0
+		if sum>>63 == 1 {
+			weight = -1.0
+		}
+		vector[sum%uint64(e.dimensions)] += weight
+	}
+
+	var norm float64
+	for _, value := range vector {
+		norm += value * value
+	}
+	if norm > 0 {
+		norm = math.Sqrt(norm)
+		for i := range vector {
+			vector[i] /= norm
+		}
+	}
+	return vector
+}
+
+// words 将文本切分为小写的词，标点和符号作为分隔符，并去掉常见虚词
+func (e *HashEmbedder) words(text string) []string {
+	var words []string
+	for _, token := range e.tokenizer.Tokenize(strings.ToLower(text)) {
+		for _, word := range strings.FieldsFunc(token, func(r rune) bool {
+			return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+		}) {
+			if !hashEmbedderStopWords[word] {
+				words = append(words, word)
+			}
+		}
+	}
+	return words
+}
+
+// cosineSimilarity 计算两个向量的余弦相似度，任一向量为零向量时返回 0
+func cosineSimilarity(a, b []float64) float64 {
+	var dot, normA, normB float64
+	for i := range a {
+		if i >= len(b) {
+			break
+		}
+		dot += a[i] * b[i]
+		normA += a[i] * a[i]
+		normB += b[i] * b[i]
+	}
+	if normA == 0 || normB == 0 {
+		return 0
+	}
+	return dot / (math.Sqrt(normA) * math.Sqrt(normB))
+}
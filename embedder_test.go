@@ -0,0 +1,57 @@
+package markdownchunker
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestHashEmbedder_Deterministic(t *testing.T) {
+	embedder := NewHashEmbedder(64)
+	if embedder.Dimensions() != 64 || embedder.Name() != "hash" {
+		t.Fatalf("Unexpected embedder %s with %d dimensions", embedder.Name(), embedder.Dimensions())
+	}
+
+	first, err := embedder.Embed([]string{"Rockets burn fuel.", ""})
+	if err != nil {
+		t.Fatalf("Embed() error = %v", err)
+	}
+	second, _ := NewHashEmbedder(64).Embed([]string{"Rockets burn fuel.", ""})
+	if !reflect.DeepEqual(first, second) {
+		t.Error("Expected identical vectors for identical input")
+	}
+
+	var norm float64
+	for _, value := range first[0] {
+		norm += value * value
+	}
+	if math.Abs(norm-1) > 1e-9 {
+		t.Errorf("Expected unit vector, got squared norm %f", norm)
+	}
+	for _, value := range first[1] {
+		if value != 0 {
+			t.Fatal("Expected zero vector for empty text")
+		}
+	}
+}
+
+func TestHashEmbedder_Similarity(t *testing.T) {
+	embedder := NewHashEmbedder(0)
+	if embedder.Dimensions() != DefaultHashEmbedderDimensions {
+		t.Errorf("Expected default dimensions, got %d", embedder.Dimensions())
+	}
+
+	vectors, _ := embedder.Embed([]string{
+		"The rocket engine burns fuel.",
+		"Rocket fuel burns in the ENGINE!",
+		"Boil the pasta in salted water.",
+	})
+	related := cosineSimilarity(vectors[0], vectors[1])
+	unrelated := cosineSimilarity(vectors[0], vectors[2])
+	if related < 0.99 {
+		t.Errorf("Expected same words to be similar regardless of case and punctuation, got %f", related)
+	}
+	if unrelated >= related || unrelated > 0.5 {
+		t.Errorf("Expected unrelated texts to be less similar, got %f vs %f", unrelated, related)
+	}
+}
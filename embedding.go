@@ -0,0 +1,167 @@
+package markdownchunker
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/yuin/goldmark/ast"
+)
+
+// 嵌入文本格式中可用的占位符
+const (
+	// EmbeddingPlaceholderTitle 文档标题：前置元数据 title，没有时为第一个一级标题
+	EmbeddingPlaceholderTitle = "{title}"
+	// EmbeddingPlaceholderBreadcrumb 章节路径，如 "Install > Linux"
+	EmbeddingPlaceholderBreadcrumb = "{breadcrumb}"
+	// EmbeddingPlaceholderType 块类型
+	EmbeddingPlaceholderType = "{type}"
+	// EmbeddingPlaceholderLanguage 代码块语言
+	EmbeddingPlaceholderLanguage = "{language}"
+	// EmbeddingPlaceholderText 块的纯文本
+	EmbeddingPlaceholderText = "{text}"
+)
+
+// DefaultEmbeddingTextFormat 默认的嵌入文本格式：标题和章节路径各占一行，空行之后是块文本
+const DefaultEmbeddingTextFormat = "{title}\n{breadcrumb}\n\n{text}"
+
+// embeddingPlaceholderPattern 匹配格式中除 {text} 之外的占位符
+var embeddingPlaceholderPattern = regexp.MustCompile(`\{(title|breadcrumb|type|language)\}`)
+
+// findDocumentTitle 返回文档标题：优先使用前置元数据的 title，否则使用第一个一级标题
+func (c *MarkdownChunker) findDocumentTitle(doc ast.Node) string {
+	if title, ok := c.frontMatter.Get("title"); ok && strings.TrimSpace(title) != "" {
+		return strings.TrimSpace(title)
+	}
+
+	for child := doc.FirstChild(); child != nil; child = child.NextSibling() {
+		if heading, ok := child.(*ast.Heading); ok && heading.Level == 1 {
+			return c.getNodeText(heading)
+		}
+	}
+	return ""
+}
+
+// embeddingAffixes 按配置的格式返回块文本前后的内容
+func (c *MarkdownChunker) embeddingAffixes(chunk Chunk) (string, string) {
+	return c.formatEmbeddingAffixes(c.embeddingValues(chunk))
+}
+
+// embeddingValues 返回块的占位符取值
+func (c *MarkdownChunker) embeddingValues(chunk Chunk) map[string]string {
+	return map[string]string{
+		EmbeddingPlaceholderTitle:      c.documentTitle,
+		EmbeddingPlaceholderBreadcrumb: strings.Join(chunk.SectionPath, " > "),
+		EmbeddingPlaceholderType:       chunk.Type,
+		EmbeddingPlaceholderLanguage:   chunk.Metadata["language"],
+	}
+}
+
+// formatEmbeddingAffixes 用占位符取值填充格式，返回 {text} 前后的内容
+// 所有占位符都为空且不包含 {text} 的行会被省略，以免出现空的标签行
+func (c *MarkdownChunker) formatEmbeddingAffixes(values map[string]string) (string, string) {
+	var lines []string
+	for _, line := range strings.Split(c.config.EmbeddingTextFormat, "\n") {
+		placeholders := embeddingPlaceholderPattern.FindAllString(line, -1)
+		if len(placeholders) > 0 && !strings.Contains(line, EmbeddingPlaceholderText) {
+			empty := true
+			for _, placeholder := range placeholders {
+				if values[placeholder] != "" {
+					empty = false
+					break
+				}
+			}
+			if empty {
+				continue
+			}
+		}
+		lines = append(lines, embeddingPlaceholderPattern.ReplaceAllStringFunc(line, func(placeholder string) string {
+			return values[placeholder]
+		}))
+	}
+
+	prefix, suffix, _ := strings.Cut(strings.Join(lines, "\n"), EmbeddingPlaceholderText)
+	return strings.TrimLeft(prefix, " \t\n"), strings.TrimRight(suffix, " \t\n")
+}
+
+// embeddingDropOrder 前后缀超出预算时依次清空的占位符
+var embeddingDropOrder = []string{
+	EmbeddingPlaceholderBreadcrumb,
+	EmbeddingPlaceholderTitle,
+	EmbeddingPlaceholderLanguage,
+	EmbeddingPlaceholderType,
+}
+
+// fittedEmbeddingAffixes 返回不超过 MaxChunkSize 一半的前后缀
+// 超出时先从最外层开始逐段去掉章节路径，再依次清空整个占位符，不会截断在占位符中间，格式中的分隔行保持不变
+func (c *MarkdownChunker) fittedEmbeddingAffixes(chunk Chunk) (string, string) {
+	values := c.embeddingValues(chunk)
+	prefix, suffix := c.formatEmbeddingAffixes(values)
+	if c.config.MaxChunkSize <= 0 {
+		return prefix, suffix
+	}
+
+	budget := c.config.MaxChunkSize / 2
+	fits := func() bool {
+		return c.measureSize(prefix)+c.measureSize(suffix) <= budget
+	}
+	for path := chunk.SectionPath; !fits() && len(path) > 1; {
+		path = path[1:]
+		values[EmbeddingPlaceholderBreadcrumb] = strings.Join(path, " > ")
+		prefix, suffix = c.formatEmbeddingAffixes(values)
+	}
+	for _, placeholder := range embeddingDropOrder {
+		if fits() {
+			break
+		}
+		values[placeholder] = ""
+		prefix, suffix = c.formatEmbeddingAffixes(values)
+	}
+	return prefix, suffix
+}
+
+// embeddingOverhead 返回嵌入文本中块文本之外部分的大小，计入块大小限制
+func (c *MarkdownChunker) embeddingOverhead(chunk Chunk) int {
+	if c.config == nil || c.config.EmbeddingTextFormat == "" {
+		return 0
+	}
+	prefix, suffix := c.fittedEmbeddingAffixes(chunk)
+	return c.measureSize(prefix) + c.measureSize(suffix)
+}
+
+// chunkSizeLimit 返回块内容的大小上限，启用嵌入文本时扣除前后缀的大小
+// 前后缀最多占用 MaxChunkSize 的一半，超出部分在生成嵌入文本时省略
+func (c *MarkdownChunker) chunkSizeLimit(chunk Chunk) int {
+	limit := c.config.MaxChunkSize
+	if limit <= 0 {
+		return limit
+	}
+	return limit - min(c.embeddingOverhead(chunk), limit/2)
+}
+
+// splitLimit 返回按类型拆分时的大小上限，类型上限未配置或更大时使用 chunkSizeLimit，避免拆分片段再被截断
+func (c *MarkdownChunker) splitLimit(chunk Chunk, typeMaxSize int) int {
+	limit := c.chunkSizeLimit(chunk)
+	if typeMaxSize > 0 && (limit <= 0 || typeMaxSize < limit) {
+		return typeMaxSize
+	}
+	return limit
+}
+
+// applyEmbeddingText 按配置的格式生成块的嵌入文本，Text 保持不变
+// Text 可能带有重叠上下文或脚注而超出上限，此时只截断嵌入文本中的块文本
+func (c *MarkdownChunker) applyEmbeddingText(chunk *Chunk) {
+	if c.config == nil || c.config.EmbeddingTextFormat == "" {
+		return
+	}
+
+	prefix, suffix := c.fittedEmbeddingAffixes(*chunk)
+	body := chunk.Text
+	if c.config.MaxChunkSize > 0 {
+		room := max(c.config.MaxChunkSize-c.measureSize(prefix)-c.measureSize(suffix), 0)
+		if c.measureSize(body) > room {
+			body = c.truncateToSize(body, room)
+		}
+	}
+
+	chunk.EmbeddingText = strings.TrimSpace(prefix + body + suffix)
+}
@@ -0,0 +1,247 @@
+package markdownchunker
+
+import (
+	"strings"
+	"testing"
+)
+
+const embeddingMarkdown = `# User Guide
+
+Welcome to the guide.
+
+## Install
+
+### Linux
+
+Run the installer script.
+
+` + "```bash\n./install.sh --prefix /usr/local\n```"
+
+func TestEmbeddingText_DisabledByDefault(t *testing.T) {
+	chunker := NewMarkdownChunker()
+	chunks, err := chunker.ChunkDocument([]byte(embeddingMarkdown))
+	if err != nil {
+		t.Fatalf("ChunkDocument() error = %v", err)
+	}
+	for _, chunk := range chunks {
+		if chunk.EmbeddingText != "" {
+			t.Errorf("Expected no embedding text by default, got %q", chunk.EmbeddingText)
+		}
+	}
+}
+
+func TestEmbeddingText_DefaultFormat(t *testing.T) {
+	config := DefaultConfig()
+	config.EmbeddingTextFormat = DefaultEmbeddingTextFormat
+
+	chunker := NewMarkdownChunkerWithConfig(config)
+	chunks, err := chunker.ChunkDocument([]byte(embeddingMarkdown))
+	if err != nil {
+		t.Fatalf("ChunkDocument() error = %v", err)
+	}
+
+	var paragraph *Chunk
+	for i := range chunks {
+		if chunks[i].Text == "Run the installer script." {
+			paragraph = &chunks[i]
+		}
+	}
+	if paragraph == nil {
+		t.Fatalf("Expected paragraph chunk, got %+v", chunks)
+	}
+
+	expected := "User Guide\nUser Guide > Install > Linux\n\nRun the installer script."
+	if paragraph.EmbeddingText != expected {
+		t.Errorf("Expected embedding text %q, got %q", expected, paragraph.EmbeddingText)
+	}
+	if paragraph.Text != "Run the installer script." {
+		t.Errorf("Expected Text to stay untouched, got %q", paragraph.Text)
+	}
+}
+
+func TestEmbeddingText_CustomFormatWithTypeAndLanguage(t *testing.T) {
+	config := DefaultConfig()
+	config.EmbeddingTextFormat = "[{type} {language}] {breadcrumb}: {text}"
+
+	chunker := NewMarkdownChunkerWithConfig(config)
+	chunks, err := chunker.ChunkDocument([]byte(embeddingMarkdown))
+	if err != nil {
+		t.Fatalf("ChunkDocument() error = %v", err)
+	}
+
+	code := chunks[len(chunks)-1]
+	if code.Type != "code" {
+		t.Fatalf("Expected last chunk to be code, got %s", code.Type)
+	}
+	if !strings.HasPrefix(code.EmbeddingText, "[code bash] User Guide > Install > Linux: ") {
+		t.Errorf("Unexpected code embedding text %q", code.EmbeddingText)
+	}
+}
+
+func TestEmbeddingText_FrontMatterTitleAndEmptyLines(t *testing.T) {
+	config := DefaultConfig()
+	config.EmbeddingTextFormat = "Document: {title}\nSection: {breadcrumb}\n\n{text}"
+
+	chunker := NewMarkdownChunkerWithConfig(config)
+	chunks, err := chunker.ChunkDocument([]byte("---\ntitle: Release Notes\n---\n\nPreamble text."))
+	if err != nil {
+		t.Fatalf("ChunkDocument() error = %v", err)
+	}
+	if len(chunks) != 1 {
+		t.Fatalf("Expected 1 chunk, got %d", len(chunks))
+	}
+
+	// 没有章节路径时省略 Section 行
+	if chunks[0].EmbeddingText != "Document: Release Notes\n\nPreamble text." {
+		t.Errorf("Unexpected embedding text %q", chunks[0].EmbeddingText)
+	}
+}
+
+func TestEmbeddingText_PrefixCountsAgainstMaxChunkSize(t *testing.T) {
+	paragraph := strings.Repeat("word ", 30)
+	markdown := "# Docs\n\n## Setup\n\n" + paragraph
+
+	config := DefaultConfig()
+	config.MaxChunkSize = 100
+	config.OversizeHandling = OversizeModeSplit
+	config.EmbeddingTextFormat = DefaultEmbeddingTextFormat
+
+	chunker := NewMarkdownChunkerWithConfig(config)
+	chunks, err := chunker.ChunkDocument([]byte(markdown))
+	if err != nil {
+		t.Fatalf("ChunkDocument() error = %v", err)
+	}
+
+	split := 0
+	for _, chunk := range chunks {
+		if chunk.Type != "paragraph" {
+			continue
+		}
+		split++
+		prefix, suffix := chunker.fittedEmbeddingAffixes(chunk)
+		if size := len(prefix) + len(chunk.Content) + len(suffix); size > config.MaxChunkSize {
+			t.Errorf("Expected prefix and content to fit in %d bytes, got %d", config.MaxChunkSize, size)
+		}
+		if len(chunk.EmbeddingText) > config.MaxChunkSize {
+			t.Errorf("Expected embedding text within %d bytes, got %d: %q", config.MaxChunkSize, len(chunk.EmbeddingText), chunk.EmbeddingText)
+		}
+	}
+	if split < 2 {
+		t.Errorf("Expected the paragraph to be split to make room for the prefix, got %d pieces", split)
+	}
+}
+
+func TestEmbeddingText_SplitCodeBlocksFitPrefix(t *testing.T) {
+	markdown := "# Code\n\n```go\nfunc first() int {\n\treturn 1\n}\n\nfunc second() int {\n\treturn 2\n}\n```\n"
+
+	config := DefaultConfig()
+	config.MaxChunkSize = 80
+	config.SplitCodeBlocks = true
+	config.EmbeddingTextFormat = DefaultEmbeddingTextFormat
+
+	chunker := NewMarkdownChunkerWithConfig(config)
+	chunks, err := chunker.ChunkDocument([]byte(markdown))
+	if err != nil {
+		t.Fatalf("ChunkDocument() error = %v", err)
+	}
+
+	code := chunksOfType(chunks, "code")
+	if len(code) < 2 {
+		t.Fatalf("Expected the code block to be split, got %d pieces", len(code))
+	}
+
+	joined := ""
+	for _, chunk := range code {
+		if !strings.HasSuffix(strings.TrimSpace(chunk.Content), "```") {
+			t.Errorf("Expected piece to keep its closing fence, got %q", chunk.Content)
+		}
+		if len(chunk.EmbeddingText) > config.MaxChunkSize {
+			t.Errorf("Expected embedding text within %d bytes, got %d: %q", config.MaxChunkSize, len(chunk.EmbeddingText), chunk.EmbeddingText)
+		}
+		joined += chunk.Content
+	}
+	for _, line := range []string{"func first() int {", "\treturn 1", "func second() int {", "\treturn 2"} {
+		if !strings.Contains(joined, line) {
+			t.Errorf("Expected split pieces to keep %q, got %q", line, joined)
+		}
+	}
+}
+
+func TestEmbeddingText_OverlapCountsAgainstMaxChunkSize(t *testing.T) {
+	markdown := "# Notes\n\n" + strings.Repeat("alpha ", 9) + "\n\n" + strings.Repeat("beta ", 10)
+
+	for _, mode := range []OversizeHandlingMode{OversizeModeTruncate, OversizeModeSplit} {
+		config := DefaultConfig()
+		config.MaxChunkSize = 80
+		config.OversizeHandling = mode
+		config.EmbeddingTextFormat = DefaultEmbeddingTextFormat
+		config.ChunkingStrategy = ElementLevelConfigWithOverlap(40, OverlapUnitChars)
+
+		chunker := NewMarkdownChunkerWithConfig(config)
+		chunks, err := chunker.ChunkDocument([]byte(markdown))
+		if err != nil {
+			t.Fatalf("ChunkDocument() error = %v", err)
+		}
+
+		overlapped := 0
+		for _, chunk := range chunks {
+			if prefix := chunk.Metadata["overlap_prefix"]; prefix != "" {
+				overlapped++
+				// 只截断嵌入文本，Text 保留完整的重叠内容和块文本
+				expected := prefix + " " + strings.TrimSpace(chunk.Content)
+				if chunk.Type == "paragraph" && chunk.Text != expected {
+					t.Errorf("Expected Text %q to stay untouched, got %q", expected, chunk.Text)
+				}
+			}
+			if len(chunk.EmbeddingText) > config.MaxChunkSize {
+				t.Errorf("Expected embedding text within %d bytes, got %d: %q", config.MaxChunkSize, len(chunk.EmbeddingText), chunk.EmbeddingText)
+			}
+		}
+		if overlapped == 0 {
+			t.Error("Expected overlap to be applied")
+		}
+	}
+}
+
+func TestEmbeddingText_OversizedPrefixDropsWholeSegments(t *testing.T) {
+	markdown := "# A fairly long heading title\n\n## Second level\n\nA This is a paragraph with a few words."
+
+	config := DefaultConfig()
+	config.MaxChunkSize = 60
+	config.OversizeHandling = OversizeModeSplit
+	config.EmbeddingTextFormat = DefaultEmbeddingTextFormat
+
+	chunker := NewMarkdownChunkerWithConfig(config)
+	chunks, err := chunker.ChunkDocument([]byte(markdown))
+	if err != nil {
+		t.Fatalf("ChunkDocument() error = %v", err)
+	}
+
+	paragraphs := chunksOfType(chunks, "paragraph")
+	if len(paragraphs) == 0 {
+		t.Fatalf("Expected paragraph chunks, got %+v", chunks)
+	}
+
+	for _, chunk := range paragraphs {
+		if len(chunk.EmbeddingText) > config.MaxChunkSize {
+			t.Errorf("Expected embedding text within %d bytes, got %d: %q", config.MaxChunkSize, len(chunk.EmbeddingText), chunk.EmbeddingText)
+		}
+		// 超出预算时整段去掉章节路径，标题完整保留，分隔空行之后才是块文本
+		expected := "A fairly long heading title\nSecond level\n\n"
+		if !strings.HasPrefix(chunk.EmbeddingText, expected) {
+			expected = "A fairly long heading title\n\n"
+		}
+		if !strings.HasPrefix(chunk.EmbeddingText, expected) || strings.TrimPrefix(chunk.EmbeddingText, expected) != chunk.Text {
+			t.Errorf("Expected whole prefix lines followed by the text, got %q", chunk.EmbeddingText)
+		}
+	}
+}
+
+func TestEmbeddingText_FormatRequiresTextPlaceholder(t *testing.T) {
+	config := DefaultConfig()
+	config.EmbeddingTextFormat = "{title} > {breadcrumb}"
+
+	if err := ValidateConfig(config); err == nil {
+		t.Error("Expected format without {text} to be rejected")
+	}
+}
@@ -0,0 +1,493 @@
+package markdownchunker
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/text"
+)
+
+// 默认窗口配置
+const (
+	// DefaultWindowSize 默认窗口大小（按分块器的 SizeUnit 计量）
+	DefaultWindowSize = 1000
+)
+
+// 窗口边界的优先级，数值越小越适合作为窗口边界
+const (
+	boundaryParagraph = iota // 空行之后或标题之前
+	boundaryLine             // 行首
+	boundarySentence         // 句末之后
+	boundaryWord             // 单词之间
+)
+
+// windowBoundary 窗口可以开始或结束的安全位置
+type windowBoundary struct {
+	offset   int // 在源内容中的字节偏移
+	priority int // 边界优先级
+}
+
+// windowProtectedPatterns 窗口边界不能落在其中的行内结构：链接、图片、自动链接、网址、行内代码和行内公式
+var windowProtectedPatterns = []*regexp.Regexp{
+	regexp.MustCompile(`!?\[(?:[^\]\n]|\n[^\]\n])*\](?:\([^)\n]*\)|\[[^\]\n]*\])`),
+	regexp.MustCompile(`<[a-zA-Z][a-zA-Z0-9+.-]*:[^>\s]*>`),
+	regexp.MustCompile(`https?://[^\s<>)\]]+`),
+	regexp.MustCompile("`+[^`\n]*`+"),
+	regexp.MustCompile(`\$[^$\n]+\$`),
+}
+
+// FixedSizeStrategy 固定大小滑动窗口分块策略
+// 按 WindowSize 和 WindowStride 生成窗口，窗口边界对齐到最近的安全 Markdown 边界，
+// 不会落在代码块、表格行或链接内部，适合作为与结构化策略对比的基准
+type FixedSizeStrategy struct {
+	config *StrategyConfig
+}
+
+// NewFixedSizeStrategy 创建新的固定大小分块策略
+func NewFixedSizeStrategy() *FixedSizeStrategy {
+	return &FixedSizeStrategy{
+		config: FixedSizeConfig(DefaultWindowSize, 0),
+	}
+}
+
+// NewFixedSizeStrategyWithConfig 使用指定配置创建固定大小分块策略
+func NewFixedSizeStrategyWithConfig(config *StrategyConfig) *FixedSizeStrategy {
+	if config == nil {
+		config = FixedSizeConfig(DefaultWindowSize, 0)
+	}
+	return &FixedSizeStrategy{
+		config: config,
+	}
+}
+
+// GetName 返回策略名称
+func (s *FixedSizeStrategy) GetName() string {
+	return "fixed-size"
+}
+
+// GetDescription 返回策略描述
+func (s *FixedSizeStrategy) GetDescription() string {
+	return "按固定大小和步长滑动窗口分块，窗口边界对齐到安全的 Markdown 边界"
+}
+
+// ChunkDocument 使用固定大小滑动窗口对文档进行分块
+func (s *FixedSizeStrategy) ChunkDocument(doc ast.Node, source []byte, chunker *MarkdownChunker) ([]Chunk, error) {
+	if doc == nil {
+		return nil, NewChunkerError(ErrorTypeStrategyExecutionFailed, "文档节点不能为空", nil).
+			WithContext("strategy", s.GetName()).
+			WithContext("function", "ChunkDocument")
+	}
+
+	if chunker == nil {
+		return nil, NewChunkerError(ErrorTypeStrategyExecutionFailed, "分块器实例不能为空", nil).
+			WithContext("strategy", s.GetName()).
+			WithContext("function", "ChunkDocument")
+	}
+
+	size, stride := s.windowSize(), s.windowStride()
+	boundaries := scanWindowBoundaries(source)
+	lineStarts := lineStartOffsets(source)
+
+	var chunks []Chunk
+	start, previousEnd := skipWhitespace(source, 0), 0
+	for start < len(source) {
+		end, extended := s.pickBoundary(source, boundaries, start, size, chunker)
+		if end <= previousEnd {
+			// 重叠窗口没有超出上一个窗口的结尾，从上一个窗口的结尾继续
+			start = skipWhitespace(source, previousEnd)
+			continue
+		}
+		previousEnd = end
+
+		content := strings.TrimRight(string(source[start:end]), " \t\r\n")
+		if content != "" {
+			chunk := s.createWindowChunk(source, start, start+len(content), lineStarts, len(chunks), chunker)
+			if extended {
+				chunk.Metadata["window_extended"] = "true"
+			}
+			chunks = append(chunks, chunk)
+		}
+
+		if end >= len(source) {
+			break
+		}
+
+		// 步长小于窗口大小时，下一个窗口从对齐后的步长位置开始，与当前窗口重叠
+		next := end
+		if stride < size {
+			next, _ = s.pickBoundary(source, boundaries, start, stride, chunker)
+			if next <= start || next > end {
+				next = end
+			}
+		}
+		start = skipWhitespace(source, next)
+	}
+
+	return linkChunks(chunks), nil
+}
+
+// windowSize 返回配置的窗口大小
+func (s *FixedSizeStrategy) windowSize() int {
+	if s.config == nil || s.config.WindowSize <= 0 {
+		return DefaultWindowSize
+	}
+	return s.config.WindowSize
+}
+
+// windowStride 返回配置的窗口步长，未配置时等于窗口大小（窗口之间不重叠）
+func (s *FixedSizeStrategy) windowStride() int {
+	if s.config == nil || s.config.WindowStride <= 0 {
+		return s.windowSize()
+	}
+	return s.config.WindowStride
+}
+
+// pickBoundary 从 start 开始，选择大小不超过 limit 的最佳安全边界
+// 优先在窗口后半段中选择优先级最高的边界（如段落之间），同优先级取最靠后的；
+// 没有边界能放进窗口时（如超长代码块），延伸到下一个安全边界，并返回 true
+func (s *FixedSizeStrategy) pickBoundary(source []byte, boundaries []windowBoundary, start, limit int, chunker *MarkdownChunker) (int, bool) {
+	first := sort.Search(len(boundaries), func(i int) bool { return boundaries[i].offset > start })
+	candidates := boundaries[first:]
+	if len(candidates) == 0 {
+		return len(source), false
+	}
+
+	sizeAt := func(i int) int {
+		return measureChunkSize(chunker, string(source[start:candidates[i].offset]))
+	}
+
+	// 最后一个能放进窗口的边界
+	fit := sort.Search(len(candidates), func(i int) bool { return sizeAt(i) > limit }) - 1
+	if fit < 0 {
+		return candidates[0].offset, true
+	}
+	if candidates[fit].offset == len(source) {
+		return len(source), false
+	}
+
+	// 在窗口后半段中选择优先级最高的边界
+	half := sort.Search(fit+1, func(i int) bool { return sizeAt(i) >= limit/2 })
+	best := fit
+	for i := half; i <= fit; i++ {
+		if candidates[i].priority <= candidates[best].priority {
+			best = i
+		}
+	}
+	return candidates[best].offset, false
+}
+
+// createWindowChunk 为源内容中 [start, end) 范围的窗口创建块
+// 窗口内容会被重新解析，以提取纯文本、链接和图片
+func (s *FixedSizeStrategy) createWindowChunk(source []byte, start, end int, lineStarts []int, index int, chunker *MarkdownChunker) Chunk {
+	content := source[start:end]
+	windowDoc := chunker.md.Parser().Parse(text.NewReader(content))
+	plainText := windowPlainText(windowDoc, content)
+	links, images := (&DocumentLevelStrategy{}).extractLinksAndImages(windowDoc, content)
+	if links == nil {
+		links = []Link{}
+	}
+	if images == nil {
+		images = []Image{}
+	}
+
+	startLine, startCol := offsetLineCol(lineStarts, start)
+	endLine, endCol := offsetLineCol(lineStarts, end-1)
+	position := ChunkPosition{
+		StartLine: startLine,
+		EndLine:   endLine,
+		StartCol:  startCol,
+		EndCol:    endCol + 1,
+	}
+
+	metadata := map[string]string{
+		"strategy":     s.GetName(),
+		"window_index": fmt.Sprintf("%d", index),
+		"window_size":  fmt.Sprintf("%d", measureChunkSize(chunker, string(content))),
+		"offset_start": fmt.Sprintf("%d", start),
+		"offset_end":   fmt.Sprintf("%d", end),
+		"line_start":   fmt.Sprintf("%d", position.StartLine),
+		"line_end":     fmt.Sprintf("%d", position.EndLine),
+		"word_count":   fmt.Sprintf("%d", len(strings.Fields(plainText))),
+	}
+
+	return Chunk{
+		ID:       index,
+		Type:     "window",
+		Content:  string(content),
+		Text:     plainText,
+		Level:    0,
+		Metadata: metadata,
+		Position: position,
+		Links:    links,
+		Images:   images,
+		Hash:     fmt.Sprintf("%x", sha256.Sum256(content)),
+	}
+}
+
+// ValidateConfig 验证策略特定的配置
+func (s *FixedSizeStrategy) ValidateConfig(config *StrategyConfig) error {
+	if config == nil {
+		return NewChunkerError(ErrorTypeStrategyConfigInvalid, "策略配置不能为空", nil).
+			WithContext("strategy", s.GetName()).
+			WithContext("function", "ValidateConfig")
+	}
+
+	if err := config.ValidateConfig(); err != nil {
+		return err
+	}
+
+	// 步长大于窗口大小会跳过窗口之间的内容
+	if config.WindowSize > 0 && config.WindowStride > config.WindowSize {
+		return NewChunkerError(ErrorTypeStrategyConfigInvalid, "窗口步长不能大于窗口大小", nil).
+			WithContext("strategy", s.GetName()).
+			WithContext("function", "ValidateConfig").
+			WithContext("window_size", config.WindowSize).
+			WithContext("window_stride", config.WindowStride)
+	}
+
+	return nil
+}
+
+// Clone 创建策略的副本（用于并发安全）
+func (s *FixedSizeStrategy) Clone() ChunkingStrategy {
+	var configClone *StrategyConfig
+	if s.config != nil {
+		configClone = s.config.Clone()
+	}
+
+	return &FixedSizeStrategy{
+		config: configClone,
+	}
+}
+
+// GetConfig 获取策略配置
+func (s *FixedSizeStrategy) GetConfig() *StrategyConfig {
+	if s.config == nil {
+		return nil
+	}
+	return s.config.Clone()
+}
+
+// SetConfig 设置策略配置
+func (s *FixedSizeStrategy) SetConfig(config *StrategyConfig) error {
+	if config == nil {
+		s.config = FixedSizeConfig(DefaultWindowSize, 0)
+		return nil
+	}
+
+	if err := s.ValidateConfig(config); err != nil {
+		return err
+	}
+
+	s.config = config.Clone()
+	return nil
+}
+
+// scanWindowBoundaries 扫描源内容中所有可以作为窗口边界的位置，按偏移排序
+// 代码块和公式块内部、表格行内部、表头与分隔行之间以及链接等行内结构内部都不是安全边界
+func scanWindowBoundaries(source []byte) []windowBoundary {
+	protected := inlineProtectedSpans(source)
+
+	lines := strings.SplitAfter(string(source), "\n")
+	tableRows := tableRowLines(lines)
+
+	var boundaries []windowBoundary
+	add := func(offset, priority int) {
+		if offset > 0 && offset < len(source) && !insideSpans(protected, offset) {
+			boundaries = append(boundaries, windowBoundary{offset: offset, priority: priority})
+		}
+	}
+
+	var fence string // 当前所在围栏的标记，空表示不在围栏内
+	offset := 0
+	previousBlank := false
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+
+		if fence == "" {
+			// 行首：表头与分隔行之间不能断开
+			switch {
+			case previousBlank || isATXHeadingLine(trimmed):
+				add(offset, boundaryParagraph)
+			case !(tableRows[i] && i > 0 && tableRows[i-1] && tableDelimiterPattern.MatchString(line)):
+				add(offset, boundaryLine)
+			}
+
+			if marker := fenceMarker(trimmed); marker != "" {
+				fence = marker
+			} else if !tableRows[i] {
+				addInlineBoundaries(line, offset, add)
+			}
+		} else if strings.HasPrefix(trimmed, fence) && strings.Trim(trimmed, fence[:1]) == "" {
+			fence = ""
+		}
+
+		previousBlank = trimmed == ""
+		offset += len(line)
+	}
+
+	boundaries = append(boundaries, windowBoundary{offset: len(source), priority: boundaryParagraph})
+	return boundaries
+}
+
+// inlineProtectedSpans 返回源内容中不能拆开的行内结构的字节范围
+// 范围按起始偏移排序，重叠的范围合并为一个，便于 insideSpans 二分查找
+func inlineProtectedSpans(source []byte) [][2]int {
+	var spans [][2]int
+	for _, pattern := range windowProtectedPatterns {
+		for _, match := range pattern.FindAllIndex(source, -1) {
+			spans = append(spans, [2]int{match[0], match[1]})
+		}
+	}
+	slices.SortFunc(spans, func(a, b [2]int) int {
+		return a[0] - b[0]
+	})
+
+	// 只合并真正重叠的范围，首尾相接的范围之间仍然可以断开
+	merged := spans[:0]
+	for _, span := range spans {
+		if last := len(merged) - 1; last >= 0 && span[0] < merged[last][1] {
+			merged[last][1] = max(merged[last][1], span[1])
+			continue
+		}
+		merged = append(merged, span)
+	}
+	return merged
+}
+
+// insideSpans 判断偏移是否严格位于某个范围内部，spans 需按起始偏移排序且互不重叠
+func insideSpans(spans [][2]int, offset int) bool {
+	i := sort.Search(len(spans), func(i int) bool { return spans[i][1] > offset })
+	return i < len(spans) && offset > spans[i][0]
+}
+
+// addInlineBoundaries 添加行内的句子和单词边界，边界位于空白之后的第一个字符
+func addInlineBoundaries(line string, lineOffset int, add func(offset, priority int)) {
+	content := strings.TrimLeft(line, " \t")
+	base := lineOffset + len(line) - len(content)
+
+	var previous rune
+	for i, r := range content {
+		switch {
+		case r == ' ' || r == '\t' || r == '\n' || r == '\r':
+		case previous == ' ' || previous == '\t':
+			priority := boundaryWord
+			if before := strings.TrimRight(content[:i], " \t"); before != "" {
+				last, _ := utf8.DecodeLastRuneInString(before)
+				if strings.ContainsRune(".!?;:", last) {
+					priority = boundarySentence
+				}
+			}
+			add(base+i, priority)
+		case strings.ContainsRune("。！？；", previous):
+			// 中文句末标点之后没有空格
+			add(base+i, boundarySentence)
+		}
+		previous = r
+	}
+}
+
+// tableRowLines 标记属于表格的行：分隔行、其上方的表头以及其后连续的含 | 的非空行
+func tableRowLines(lines []string) []bool {
+	rows := make([]bool, len(lines))
+	for i := 1; i < len(lines); i++ {
+		if !strings.Contains(lines[i-1], "|") || !strings.Contains(lines[i], "-") ||
+			!tableDelimiterPattern.MatchString(lines[i]) {
+			continue
+		}
+		rows[i-1], rows[i] = true, true
+		for j := i + 1; j < len(lines) && strings.TrimSpace(lines[j]) != "" && strings.Contains(lines[j], "|"); j++ {
+			rows[j] = true
+		}
+	}
+	return rows
+}
+
+// fenceMarker 返回代码块或公式块的起始围栏标记，不是围栏时返回空字符串
+func fenceMarker(trimmed string) string {
+	for _, char := range []string{"`", "~"} {
+		if strings.HasPrefix(trimmed, strings.Repeat(char, 3)) {
+			return strings.Repeat(char, len(trimmed)-len(strings.TrimLeft(trimmed, char)))
+		}
+	}
+	if strings.HasPrefix(trimmed, "$$") && !(len(trimmed) > 2 && strings.HasSuffix(trimmed, "$$")) {
+		return "$$"
+	}
+	return ""
+}
+
+// isATXHeadingLine 判断行是否为 ATX 标题
+func isATXHeadingLine(trimmed string) bool {
+	level := len(trimmed) - len(strings.TrimLeft(trimmed, "#"))
+	return level >= 1 && level <= 6 && (len(trimmed) == level || trimmed[level] == ' ')
+}
+
+// skipWhitespace 跳过 offset 处开始的空白字符
+func skipWhitespace(source []byte, offset int) int {
+	for offset < len(source) && strings.ContainsRune(" \t\r\n", rune(source[offset])) {
+		offset++
+	}
+	return offset
+}
+
+// lineStartOffsets 返回每一行起始位置的字节偏移
+func lineStartOffsets(source []byte) []int {
+	starts := []int{0}
+	for i, b := range source {
+		if b == '\n' {
+			starts = append(starts, i+1)
+		}
+	}
+	return starts
+}
+
+// offsetLineCol 将字节偏移转换为从 1 开始的行号和列号
+func offsetLineCol(lineStarts []int, offset int) (int, int) {
+	line := sort.Search(len(lineStarts), func(i int) bool { return lineStarts[i] > offset }) - 1
+	if line < 0 {
+		line = 0
+	}
+	return line + 1, offset - lineStarts[line] + 1
+}
+
+// windowPlainText 提取窗口的纯文本，包括代码块和公式块的内容
+func windowPlainText(doc ast.Node, source []byte) string {
+	var buf strings.Builder
+	ast.Walk(doc, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			return ast.WalkContinue, nil
+		}
+		switch node := n.(type) {
+		case *ast.Text:
+			buf.Write(node.Segment.Value(source))
+			if node.SoftLineBreak() || node.HardLineBreak() {
+				buf.WriteByte(' ')
+			}
+		case *ast.String:
+			buf.Write(node.Value)
+		case *ast.AutoLink:
+			buf.Write(node.URL(source))
+		case *inlineMath:
+			buf.Write(node.Segment.Value(source))
+		case *ast.FencedCodeBlock, *ast.CodeBlock, *mathBlock, *ast.HTMLBlock:
+			lines := n.Lines()
+			for i := 0; i < lines.Len(); i++ {
+				segment := lines.At(i)
+				buf.Write(segment.Value(source))
+			}
+			buf.WriteByte(' ')
+			return ast.WalkSkipChildren, nil
+		}
+		if n.Type() == ast.TypeBlock {
+			buf.WriteByte(' ')
+		}
+		return ast.WalkContinue, nil
+	})
+	return strings.Join(strings.Fields(buf.String()), " ")
+}
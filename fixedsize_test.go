@@ -0,0 +1,178 @@
+package markdownchunker
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+)
+
+const fixedSizeMarkdown = "# Title\n\n" +
+	"First paragraph with a [link to docs](https://example.com/docs) and more words here. Second sentence follows.\n\n" +
+	"```go\nfunc main() {\n\tprintln(\"hi\")\n}\n```\n\n" +
+	"| A | B |\n|---|---|\n| 1 | 2 |\n| 3 | 4 |\n\n" +
+	"![img](a.png) Closing text that is long enough to split somewhere.\n"
+
+func TestFixedSizeStrategy_WindowsRespectSize(t *testing.T) {
+	chunks := chunkWithStrategyConfig(t, FixedSizeConfig(60, 0), fixedSizeMarkdown)
+	if len(chunks) < 4 {
+		t.Fatalf("Expected several windows, got %d", len(chunks))
+	}
+
+	for i, chunk := range chunks {
+		if chunk.Type != "window" || chunk.Metadata["strategy"] != "fixed-size" {
+			t.Errorf("Window %d: unexpected type %q or strategy %q", i, chunk.Type, chunk.Metadata["strategy"])
+		}
+		if len(chunk.Content) > 60 {
+			t.Errorf("Window %d exceeds window size: %q", i, chunk.Content)
+		}
+		if i > 0 && chunk.PrevID != chunks[i-1].ID {
+			t.Errorf("Window %d: expected PrevID %d, got %d", i, chunks[i-1].ID, chunk.PrevID)
+		}
+	}
+
+	// 没有重叠时窗口按顺序覆盖整个文档
+	var rebuilt []string
+	for _, chunk := range chunks {
+		rebuilt = append(rebuilt, strings.Fields(chunk.Content)...)
+	}
+	if strings.Join(rebuilt, " ") != strings.Join(strings.Fields(fixedSizeMarkdown), " ") {
+		t.Errorf("Windows do not cover the document:\n%v", rebuilt)
+	}
+}
+
+func TestFixedSizeStrategy_SafeBoundaries(t *testing.T) {
+	for _, size := range []int{20, 35, 50, 80} {
+		for _, chunk := range chunkWithStrategyConfig(t, FixedSizeConfig(size, 0), fixedSizeMarkdown) {
+			if strings.Count(chunk.Content, "```")%2 != 0 {
+				t.Errorf("Size %d: window splits a code fence: %q", size, chunk.Content)
+			}
+			if strings.Count(chunk.Content, "[link to docs") != strings.Count(chunk.Content, "example.com/docs)") {
+				t.Errorf("Size %d: window splits a link: %q", size, chunk.Content)
+			}
+			for _, line := range strings.Split(chunk.Content, "\n") {
+				if strings.HasPrefix(line, "|") && !strings.HasSuffix(line, "|") {
+					t.Errorf("Size %d: window splits a table row: %q", size, chunk.Content)
+				}
+			}
+			if strings.HasPrefix(chunk.Content, "|---") {
+				t.Errorf("Size %d: window separates table header from delimiter: %q", size, chunk.Content)
+			}
+		}
+	}
+
+	// 超长代码块无法放进窗口时整体保留，并标记窗口被延伸
+	chunks := chunkWithStrategyConfig(t, FixedSizeConfig(20, 0), fixedSizeMarkdown)
+	for _, chunk := range chunks {
+		if strings.HasPrefix(chunk.Content, "```go") {
+			if !strings.HasSuffix(chunk.Content, "```") || chunk.Metadata["window_extended"] != "true" {
+				t.Errorf("Expected extended window for the whole code block, got %q %v", chunk.Content, chunk.Metadata)
+			}
+		}
+	}
+}
+
+func TestFixedSizeStrategy_StrideOverlap(t *testing.T) {
+	markdown := strings.Repeat("Alpha beta gamma delta. ", 20)
+	chunks := chunkWithStrategyConfig(t, FixedSizeConfig(100, 50), markdown)
+	if len(chunks) < 2 {
+		t.Fatalf("Expected several windows, got %d", len(chunks))
+	}
+
+	for i := 1; i < len(chunks); i++ {
+		previousEnd, _ := strconv.Atoi(chunks[i-1].Metadata["offset_end"])
+		start, _ := strconv.Atoi(chunks[i].Metadata["offset_start"])
+		if start >= previousEnd {
+			t.Errorf("Window %d does not overlap the previous window: start %d, previous end %d", i, start, previousEnd)
+		}
+		if !strings.HasPrefix(chunks[i].Content, "Alpha") {
+			t.Errorf("Window %d should start at a sentence boundary, got %q", i, chunks[i].Content)
+		}
+	}
+}
+
+func TestFixedSizeStrategy_PositionLinksImages(t *testing.T) {
+	chunks := chunkWithStrategyConfig(t, FixedSizeConfig(80, 0), fixedSizeMarkdown)
+
+	var foundLink, foundImage bool
+	for _, chunk := range chunks {
+		lines := strings.Split(fixedSizeMarkdown, "\n")
+		firstLine := strings.SplitN(chunk.Content, "\n", 2)[0]
+		if !strings.Contains(lines[chunk.Position.StartLine-1], firstLine) {
+			t.Errorf("Window position %+v does not match content %q", chunk.Position, chunk.Content)
+		}
+		if chunk.Position.StartCol < 1 || chunk.Position.EndLine < chunk.Position.StartLine {
+			t.Errorf("Invalid position %+v", chunk.Position)
+		}
+
+		for _, link := range chunk.Links {
+			if link.URL == "https://example.com/docs" && link.Text == "link to docs" {
+				foundLink = true
+			}
+		}
+		for _, image := range chunk.Images {
+			if image.URL == "a.png" {
+				foundImage = true
+			}
+		}
+	}
+	if !foundLink || !foundImage {
+		t.Errorf("Expected link and image to be extracted, link=%v image=%v", foundLink, foundImage)
+	}
+}
+
+func TestFixedSizeStrategy_Config(t *testing.T) {
+	strategy := NewFixedSizeStrategy()
+	if err := strategy.ValidateConfig(FixedSizeConfig(100, 200)); err == nil {
+		t.Error("Expected error when stride exceeds window size")
+	}
+	if err := strategy.ValidateConfig(FixedSizeConfig(-1, 0)); err == nil {
+		t.Error("Expected error for negative window size")
+	}
+	if err := strategy.SetConfig(FixedSizeConfig(100, 50)); err != nil {
+		t.Fatalf("SetConfig() error = %v", err)
+	}
+	if config := strategy.GetConfig(); config.WindowSize != 100 || config.WindowStride != 50 {
+		t.Errorf("Unexpected config %+v", config)
+	}
+
+	config, err := CreateConfigFromParameters("fixed-size", map[string]any{"window_size": 200, "window_stride": 100})
+	if err != nil {
+		t.Fatalf("CreateConfigFromParameters() error = %v", err)
+	}
+	if config.WindowSize != 200 || config.WindowStride != 100 {
+		t.Errorf("Unexpected config from parameters %+v", config)
+	}
+
+	clone := strategy.Clone().(*FixedSizeStrategy)
+	if clone.GetConfig().WindowSize != 100 {
+		t.Errorf("Clone lost window size")
+	}
+}
+
+func TestFixedSizeStrategy_EnabledTypes(t *testing.T) {
+	assertKeptWithEnabledTypes(t, FixedSizeConfig(60, 0), fixedSizeMarkdown, "window")
+}
+
+func TestInlineProtectedSpans_SortedAndMerged(t *testing.T) {
+	source := []byte("Math $a$ before [the `code` docs](https://example.com/a b) and `x`, see https://example.com/b.")
+
+	spans := inlineProtectedSpans(source)
+	for i := 1; i < len(spans); i++ {
+		if spans[i][0] < spans[i-1][1] {
+			t.Fatalf("Expected sorted, non-overlapping spans, got %v", spans)
+		}
+	}
+
+	// 二分查找的结果应与逐个检查原始匹配一致
+	for offset := 0; offset <= len(source); offset++ {
+		want := false
+		for _, pattern := range windowProtectedPatterns {
+			for _, match := range pattern.FindAllIndex(source, -1) {
+				want = want || (offset > match[0] && offset < match[1])
+			}
+		}
+		if got := insideSpans(spans, offset); got != want {
+			t.Errorf("insideSpans(%d) = %v, want %v", offset, got, want)
+		}
+	}
+}
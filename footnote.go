@@ -0,0 +1,231 @@
+package markdownchunker
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/yuin/goldmark/ast"
+	extast "github.com/yuin/goldmark/extension/ast"
+	"github.com/yuin/goldmark/text"
+)
+
+// FootnoteHandlingMode 脚注处理模式
+type FootnoteHandlingMode int
+
+const (
+	// FootnoteModeAttach 附加模式，脚注内容附加到引用它的块的 Text 和 footnotes 元数据中（默认行为）
+	FootnoteModeAttach FootnoteHandlingMode = iota
+	// FootnoteModeSeparate 单独模式，脚注定义列表作为单独的 footnote 块输出
+	FootnoteModeSeparate
+	// FootnoteModeIgnore 忽略模式，脚注定义不出现在任何块中
+	FootnoteModeIgnore
+)
+
+// footnoteDefinition 脚注定义
+type footnoteDefinition struct {
+	label string // 脚注标签，如 [^note] 中的 note
+	text  string // 脚注纯文本
+}
+
+// footnoteMode 返回当前配置的脚注处理模式
+func (c *MarkdownChunker) footnoteMode() FootnoteHandlingMode {
+	if c.config == nil {
+		return FootnoteModeAttach
+	}
+	return c.config.FootnoteHandling
+}
+
+// collectFootnotes 收集文档中被引用的脚注定义，按脚注序号索引
+func (c *MarkdownChunker) collectFootnotes(doc ast.Node) map[int]footnoteDefinition {
+	footnotes := make(map[int]footnoteDefinition)
+
+	for child := doc.FirstChild(); child != nil; child = child.NextSibling() {
+		list, ok := child.(*extast.FootnoteList)
+		if !ok {
+			continue
+		}
+		for node := list.FirstChild(); node != nil; node = node.NextSibling() {
+			if footnote, ok := node.(*extast.Footnote); ok {
+				footnotes[footnote.Index] = footnoteDefinition{
+					label: string(footnote.Ref),
+					text:  c.getFootnoteText(footnote),
+				}
+			}
+		}
+	}
+
+	return footnotes
+}
+
+// getFootnoteText 获取脚注定义的纯文本，段落之间用空格分隔
+func (c *MarkdownChunker) getFootnoteText(footnote *extast.Footnote) string {
+	var parts []string
+	for child := footnote.FirstChild(); child != nil; child = child.NextSibling() {
+		if text := c.getNodeText(child); text != "" {
+			parts = append(parts, text)
+		}
+	}
+	return strings.Join(strings.Fields(strings.Join(parts, " ")), " ")
+}
+
+// referencedFootnotes 返回节点中引用的脚注序号，按首次出现顺序去重
+func referencedFootnotes(node ast.Node) []int {
+	var indexes []int
+	seen := make(map[int]bool)
+
+	ast.Walk(node, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if link, ok := n.(*extast.FootnoteLink); ok && entering && !seen[link.Index] {
+			seen[link.Index] = true
+			indexes = append(indexes, link.Index)
+		}
+		return ast.WalkContinue, nil
+	})
+
+	return indexes
+}
+
+// attachFootnotes 记录块引用的脚注，附加模式下将脚注内容加入 Text 和 footnotes 元数据
+func (c *MarkdownChunker) attachFootnotes(chunk *Chunk, node ast.Node) {
+	c.attachFootnoteIndexes(chunk, referencedFootnotes(node))
+}
+
+// attachFootnoteIndexes 按文档中的脚注序号为块附加脚注
+func (c *MarkdownChunker) attachFootnoteIndexes(chunk *Chunk, indexes []int) {
+	if len(c.footnotes) == 0 || c.footnoteMode() == FootnoteModeIgnore {
+		return
+	}
+
+	var labels, definitions, texts []string
+	for _, index := range indexes {
+		footnote, ok := c.footnotes[index]
+		if !ok {
+			continue
+		}
+		labels = append(labels, footnote.label)
+		definitions = append(definitions, fmt.Sprintf("[^%s]: %s", footnote.label, footnote.text))
+		texts = append(texts, footnote.text)
+	}
+	if len(labels) == 0 {
+		return
+	}
+
+	if chunk.Metadata == nil {
+		chunk.Metadata = make(map[string]string)
+	}
+	chunk.Metadata["footnote_refs"] = strings.Join(labels, ",")
+
+	if c.footnoteMode() == FootnoteModeAttach {
+		chunk.Metadata["footnotes"] = strings.Join(definitions, "\n")
+		chunk.Text = strings.TrimSpace(chunk.Text + " " + strings.Join(texts, " "))
+	}
+}
+
+// fragmentSource 返回解析拆分片段时使用的源内容
+// 片段单独解析时没有脚注定义，引用会被当作普通文本，因此在片段后追加它引用的脚注定义
+func (c *MarkdownChunker) fragmentSource(fragment string) []byte {
+	var definitions []string
+	for _, footnote := range c.footnotes {
+		if strings.Contains(fragment, "[^"+footnote.label+"]") {
+			definitions = append(definitions, fmt.Sprintf("[^%s]: %s", footnote.label, footnote.text))
+		}
+	}
+	if len(definitions) == 0 {
+		return []byte(fragment)
+	}
+	return []byte(fragment + "\n\n" + strings.Join(definitions, "\n") + "\n")
+}
+
+// reattachFootnotes 为拆分片段重新附加脚注，只保留片段自身引用的脚注
+func (c *MarkdownChunker) reattachFootnotes(piece *Chunk) {
+	if _, ok := piece.Metadata["footnote_refs"]; !ok {
+		return
+	}
+	delete(piece.Metadata, "footnote_refs")
+	delete(piece.Metadata, "footnotes")
+
+	doc := c.md.Parser().Parse(text.NewReader(c.fragmentSource(piece.Content)))
+	if doc == nil {
+		return
+	}
+
+	// 片段中的脚注序号与文档不同，按标签映射回文档中的序号
+	labels := make(map[int]string)
+	for child := doc.FirstChild(); child != nil; child = child.NextSibling() {
+		list, ok := child.(*extast.FootnoteList)
+		if !ok {
+			continue
+		}
+		for node := list.FirstChild(); node != nil; node = node.NextSibling() {
+			if footnote, ok := node.(*extast.Footnote); ok {
+				labels[footnote.Index] = string(footnote.Ref)
+			}
+		}
+	}
+
+	var indexes []int
+	for _, fragmentIndex := range referencedFootnotes(doc) {
+		for index, footnote := range c.footnotes {
+			if footnote.label == labels[fragmentIndex] {
+				indexes = append(indexes, index)
+				break
+			}
+		}
+	}
+	c.attachFootnoteIndexes(piece, indexes)
+}
+
+// processFootnoteList 处理脚注定义列表（仅用于单独模式）
+func (c *MarkdownChunker) processFootnoteList(list *extast.FootnoteList, id int) *Chunk {
+	// 创建脚注处理日志上下文
+	footnoteLogCtx := NewLogContext("processFootnoteList").WithNodeInfo("FootnoteList", id)
+	c.logWithContext("debug", "处理脚注定义列表", footnoteLogCtx)
+
+	var lines, labels, texts []string
+	for node := list.FirstChild(); node != nil; node = node.NextSibling() {
+		footnote, ok := node.(*extast.Footnote)
+		if !ok {
+			continue
+		}
+		text := c.getFootnoteText(footnote)
+		labels = append(labels, string(footnote.Ref))
+		lines = append(lines, fmt.Sprintf("[^%s]: %s", footnote.Ref, text))
+		texts = append(texts, text)
+	}
+
+	content := strings.Join(lines, "\n")
+	text := strings.Join(texts, " ")
+
+	position := c.calculatePosition(list)
+	links := c.extractLinks(list)
+	images := c.extractImages(list)
+	hash := c.calculateContentHash(content)
+
+	// 记录提取的内容统计信息
+	completeFootnoteLogCtx := NewLogContext("processFootnoteList").
+		WithNodeInfo("FootnoteList", id).
+		WithMetadata("footnote_count", len(labels)).
+		WithContentInfo(len(content), len(text), len(strings.Fields(text))).
+		WithLinksAndImages(len(links), len(images))
+	c.logWithContext("debug", "脚注定义列表提取完成", completeFootnoteLogCtx)
+
+	return &Chunk{
+		ID:       id,
+		Type:     "footnote",
+		Content:  content,
+		Text:     text,
+		Level:    0,
+		Position: position,
+		Links:    links,
+		Images:   images,
+		Hash:     hash,
+		Metadata: map[string]string{
+			"footnote_count": fmt.Sprintf("%d", len(labels)),
+			"footnote_refs":  strings.Join(labels, ","),
+			// 添加位置信息到元数据以保持向后兼容性
+			"line_start": fmt.Sprintf("%d", position.StartLine),
+			"line_end":   fmt.Sprintf("%d", position.EndLine),
+			"char_start": fmt.Sprintf("%d", position.StartCol),
+			"char_end":   fmt.Sprintf("%d", position.EndCol),
+		},
+	}
+}
@@ -0,0 +1,188 @@
+package markdownchunker
+
+import (
+	"strings"
+	"testing"
+)
+
+const footnoteMarkdown = `# Notes
+
+Go was announced in 2009[^1] and reached 1.0 later[^v1].
+
+Unrelated paragraph.
+
+- A list item citing the spec[^1]
+
+[^1]: Announced by Google.
+[^v1]: Version 1.0 shipped in March 2012.`
+
+func TestFootnote_AttachedByDefault(t *testing.T) {
+	chunker := NewMarkdownChunker()
+	chunks, err := chunker.ChunkDocument([]byte(footnoteMarkdown))
+	if err != nil {
+		t.Fatalf("ChunkDocument() error = %v", err)
+	}
+
+	if len(chunks) != 4 {
+		t.Fatalf("Expected footnote definitions not to become a chunk, got %d chunks: %+v", len(chunks), chunks)
+	}
+
+	paragraph := chunks[1]
+	if !strings.Contains(paragraph.Content, "[^1]") {
+		t.Errorf("Expected content to keep the reference marker, got %q", paragraph.Content)
+	}
+	if !strings.HasSuffix(paragraph.Text, "Announced by Google. Version 1.0 shipped in March 2012.") {
+		t.Errorf("Expected footnote text to be appended, got %q", paragraph.Text)
+	}
+	if paragraph.Metadata["footnote_refs"] != "1,v1" {
+		t.Errorf("Unexpected footnote_refs %q", paragraph.Metadata["footnote_refs"])
+	}
+	if paragraph.Metadata["footnotes"] != "[^1]: Announced by Google.\n[^v1]: Version 1.0 shipped in March 2012." {
+		t.Errorf("Unexpected footnotes metadata %q", paragraph.Metadata["footnotes"])
+	}
+
+	if _, ok := chunks[2].Metadata["footnotes"]; ok {
+		t.Error("Expected paragraph without references to have no footnotes")
+	}
+
+	list := chunks[3]
+	if list.Type != "list" || list.Metadata["footnote_refs"] != "1" {
+		t.Errorf("Expected list to carry its footnote reference, got %+v", list)
+	}
+}
+
+func TestFootnote_SeparateMode(t *testing.T) {
+	config := DefaultConfig()
+	config.FootnoteHandling = FootnoteModeSeparate
+
+	chunker := NewMarkdownChunkerWithConfig(config)
+	chunks, err := chunker.ChunkDocument([]byte(footnoteMarkdown))
+	if err != nil {
+		t.Fatalf("ChunkDocument() error = %v", err)
+	}
+
+	last := chunks[len(chunks)-1]
+	if last.Type != "footnote" {
+		t.Fatalf("Expected a footnote chunk, got %s", last.Type)
+	}
+	if last.Content != "[^1]: Announced by Google.\n[^v1]: Version 1.0 shipped in March 2012." {
+		t.Errorf("Unexpected footnote content %q", last.Content)
+	}
+	if last.Metadata["footnote_count"] != "2" {
+		t.Errorf("Unexpected footnote_count %q", last.Metadata["footnote_count"])
+	}
+
+	if strings.Contains(chunks[1].Text, "Announced") || chunks[1].Metadata["footnote_refs"] != "1,v1" {
+		t.Errorf("Expected references to be recorded without attaching text, got %+v", chunks[1])
+	}
+}
+
+func TestFootnote_IgnoreMode(t *testing.T) {
+	config := DefaultConfig()
+	config.FootnoteHandling = FootnoteModeIgnore
+
+	chunker := NewMarkdownChunkerWithConfig(config)
+	chunks, err := chunker.ChunkDocument([]byte(footnoteMarkdown))
+	if err != nil {
+		t.Fatalf("ChunkDocument() error = %v", err)
+	}
+
+	for _, chunk := range chunks {
+		if chunk.Type == "footnote" || strings.Contains(chunk.Text, "Announced") {
+			t.Errorf("Expected footnotes to be ignored, got %+v", chunk)
+		}
+	}
+}
+
+func TestFootnote_Hierarchical(t *testing.T) {
+	chunks := chunkWithStrategyConfig(t, HierarchicalConfig(2), footnoteMarkdown)
+
+	if len(chunks) != 1 {
+		t.Fatalf("Expected a single section without an orphan footnote chunk, got %d", len(chunks))
+	}
+	if !strings.Contains(chunks[0].Text, "later. Announced by Google. Version 1.0 shipped in March 2012.") {
+		t.Errorf("Expected hierarchical section to include the footnote text, got %q", chunks[0].Text)
+	}
+	if strings.Contains(chunks[0].Content, "[^1]: Announced") {
+		t.Errorf("Expected definitions not to be appended to section content, got %q", chunks[0].Content)
+	}
+}
+
+func TestFootnote_InvalidConfig(t *testing.T) {
+	config := DefaultConfig()
+	config.FootnoteHandling = FootnoteHandlingMode(9)
+	if err := ValidateConfig(config); err == nil {
+		t.Error("Expected error for invalid footnote handling mode")
+	}
+}
+
+func TestFootnote_SplitPiecesKeepTheirReferences(t *testing.T) {
+	markdown := "# Notes\n\n" + strings.Repeat("Filler sentence without notes. ", 4) +
+		"Go was announced in 2009[^1]. " + strings.Repeat("More filler text here. ", 4) +
+		"\n\n[^1]: Announced by Google."
+
+	config := DefaultConfig()
+	config.MaxChunkSize = 80
+	config.OversizeHandling = OversizeModeSplit
+
+	chunker := NewMarkdownChunkerWithConfig(config)
+	chunks, err := chunker.ChunkDocument([]byte(markdown))
+	if err != nil {
+		t.Fatalf("ChunkDocument() error = %v", err)
+	}
+
+	referencing := 0
+	for _, chunk := range chunks {
+		if chunk.Type != "paragraph" {
+			continue
+		}
+		if strings.Contains(chunk.Text, "[^1]") {
+			t.Errorf("Expected the reference marker not to leak into Text, got %q", chunk.Text)
+		}
+		if !strings.Contains(chunk.Content, "[^1]") {
+			if _, ok := chunk.Metadata["footnote_refs"]; ok || strings.Contains(chunk.Text, "Announced") {
+				t.Errorf("Expected piece without the reference to carry no footnote, got %+v", chunk)
+			}
+			continue
+		}
+		referencing++
+		if !strings.HasSuffix(chunk.Text, "Announced by Google.") {
+			t.Errorf("Expected the definition to be attached to the referencing piece, got %q", chunk.Text)
+		}
+		if chunk.Metadata["footnote_refs"] != "1" || chunk.Metadata["footnotes"] != "[^1]: Announced by Google." {
+			t.Errorf("Unexpected footnote metadata %+v", chunk.Metadata)
+		}
+	}
+	if referencing != 1 {
+		t.Errorf("Expected exactly one piece to reference the footnote, got %d", referencing)
+	}
+}
+
+func TestFootnote_SplitListItems(t *testing.T) {
+	markdown := "- First item with a note[^a]\n- Second item without notes\n- Third item citing[^b]\n\n" +
+		"[^a]: Note A.\n[^b]: Note B."
+
+	config := DefaultConfig()
+	config.SplitLists = true
+	config.ListMaxSize = 40
+
+	chunker := NewMarkdownChunkerWithConfig(config)
+	chunks, err := chunker.ChunkDocument([]byte(markdown))
+	if err != nil {
+		t.Fatalf("ChunkDocument() error = %v", err)
+	}
+
+	expected := map[string]string{"1-1": "a", "2-2": "", "3-3": "b"}
+	for _, chunk := range chunksOfType(chunks, "list") {
+		refs, ok := expected[chunk.Metadata["item_range"]]
+		if !ok {
+			t.Fatalf("Unexpected list part %q: %+v", chunk.Metadata["item_range"], chunk)
+		}
+		if strings.Contains(chunk.Text, "[^") {
+			t.Errorf("Expected the reference marker not to leak into Text, got %q", chunk.Text)
+		}
+		if chunk.Metadata["footnote_refs"] != refs {
+			t.Errorf("Expected footnote_refs %q for items %s, got %q", refs, chunk.Metadata["item_range"], chunk.Metadata["footnote_refs"])
+		}
+	}
+}
@@ -0,0 +1,352 @@
+package markdownchunker
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// FrontMatterFormat 前置元数据格式
+type FrontMatterFormat string
+
+const (
+	// FrontMatterYAML 以 --- 分隔的 YAML 前置元数据
+	FrontMatterYAML FrontMatterFormat = "yaml"
+	// FrontMatterTOML 以 +++ 分隔的 TOML 前置元数据
+	FrontMatterTOML FrontMatterFormat = "toml"
+)
+
+// frontMatterMetadataPrefix 前置元数据键复制到块元数据时使用的前缀
+const frontMatterMetadataPrefix = "fm_"
+
+// FrontMatter 文档开头的前置元数据
+type FrontMatter struct {
+	Format    FrontMatterFormat // 格式
+	Raw       string            // 分隔符之间的原始内容
+	Fields    map[string]any    // 解析后的字段，值为 string 或 []string，嵌套键以点号连接
+	StartLine int               // 开始分隔符所在行（从1开始）
+	EndLine   int               // 结束分隔符所在行
+}
+
+// Get 返回字段的字符串值，列表值以逗号连接
+func (fm *FrontMatter) Get(key string) (string, bool) {
+	if fm == nil {
+		return "", false
+	}
+	value, ok := fm.Fields[key]
+	if !ok {
+		return "", false
+	}
+	switch v := value.(type) {
+	case []string:
+		return strings.Join(v, ","), true
+	case string:
+		return v, true
+	default:
+		return fmt.Sprintf("%v", v), true
+	}
+}
+
+// Keys 返回排序后的字段名
+func (fm *FrontMatter) Keys() []string {
+	if fm == nil {
+		return nil
+	}
+	keys := make([]string, 0, len(fm.Fields))
+	for key := range fm.Fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// extractFrontMatter 检测文档开头的 YAML/TOML 前置元数据
+// 返回解析结果和去除前置元数据后的内容，前置元数据所在行被替换为空行以保持行号不变
+func extractFrontMatter(content []byte) (*FrontMatter, []byte) {
+	body := bytes.TrimPrefix(content, []byte("\ufeff"))
+	lines := strings.Split(string(body), "\n")
+	if len(lines) < 2 {
+		return nil, content
+	}
+
+	var format FrontMatterFormat
+	var closers []string
+	switch strings.TrimRight(lines[0], " \t\r") {
+	case "---":
+		format = FrontMatterYAML
+		closers = []string{"---", "..."}
+	case "+++":
+		format = FrontMatterTOML
+		closers = []string{"+++"}
+	default:
+		return nil, content
+	}
+
+	end := -1
+	for i := 1; i < len(lines) && end < 0; i++ {
+		trimmed := strings.TrimRight(lines[i], " \t\r")
+		for _, closer := range closers {
+			if trimmed == closer {
+				end = i
+				break
+			}
+		}
+	}
+	if end < 0 {
+		return nil, content
+	}
+
+	raw := strings.Join(lines[1:end], "\n")
+	var fields map[string]any
+	if format == FrontMatterYAML {
+		fields = parseYAMLFrontMatter(raw)
+	} else {
+		fields = parseTOMLFrontMatter(raw)
+	}
+
+	// "---" 开头也可能是分隔线加 setext 标题，没有任何键值对时不视为前置元数据
+	if len(fields) == 0 && strings.TrimSpace(raw) != "" {
+		return nil, content
+	}
+
+	fm := &FrontMatter{
+		Format:    format,
+		Raw:       raw,
+		Fields:    fields,
+		StartLine: 1,
+		EndLine:   end + 1,
+	}
+
+	stripped := strings.Repeat("\n", end) + strings.Join(lines[end:], "\n")[len(lines[end]):]
+	return fm, []byte(stripped)
+}
+
+// parseYAMLFrontMatter 解析常见的 YAML 前置元数据子集：
+// 标量、引号字符串、行内列表、块列表、块标量以及按缩进嵌套的映射
+func parseYAMLFrontMatter(raw string) map[string]any {
+	type frame struct {
+		indent int
+		prefix string
+	}
+
+	fields := make(map[string]any)
+	lines := strings.Split(raw, "\n")
+	var stack []frame
+	lastKey := ""
+
+	for i := 0; i < len(lines); i++ {
+		line := strings.TrimRight(lines[i], " \t\r")
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+		indent := len(line) - len(strings.TrimLeft(line, " "))
+
+		// 块列表项
+		if trimmed == "-" || strings.HasPrefix(trimmed, "- ") {
+			if lastKey == "" {
+				continue
+			}
+			item := parseFrontMatterScalar(strings.TrimSpace(strings.TrimPrefix(trimmed, "-")))
+			list, _ := fields[lastKey].([]string)
+			fields[lastKey] = append(list, item)
+			continue
+		}
+
+		key, value, ok := splitYAMLKeyValue(trimmed)
+		if !ok {
+			continue
+		}
+
+		for len(stack) > 0 && stack[len(stack)-1].indent >= indent {
+			stack = stack[:len(stack)-1]
+		}
+		if len(stack) > 0 {
+			key = stack[len(stack)-1].prefix + key
+		}
+		lastKey = key
+
+		switch {
+		case value == "":
+			// 嵌套映射或块列表
+			stack = append(stack, frame{indent: indent, prefix: key + "."})
+		case strings.HasPrefix(value, "|") || strings.HasPrefix(value, ">"):
+			// 块标量：收集缩进更深的后续行
+			var block []string
+			for i+1 < len(lines) {
+				next := strings.TrimRight(lines[i+1], " \t\r")
+				nextIndent := len(next) - len(strings.TrimLeft(next, " "))
+				if strings.TrimSpace(next) != "" && nextIndent <= indent {
+					break
+				}
+				block = append(block, strings.TrimSpace(next))
+				i++
+			}
+			separator := "\n"
+			if strings.HasPrefix(value, ">") {
+				separator = " "
+			}
+			fields[key] = strings.TrimSpace(strings.Join(block, separator))
+		default:
+			fields[key] = parseFrontMatterValue(value)
+		}
+	}
+
+	return fields
+}
+
+// splitYAMLKeyValue 拆分 YAML 的 "key: value" 行
+func splitYAMLKeyValue(line string) (string, string, bool) {
+	idx := strings.Index(line, ": ")
+	if idx < 0 {
+		if !strings.HasSuffix(line, ":") {
+			return "", "", false
+		}
+		idx = len(line) - 1
+	}
+
+	key := strings.Trim(strings.TrimSpace(line[:idx]), `"'`)
+	quoted := strings.HasPrefix(line, `"`) || strings.HasPrefix(line, "'")
+	if key == "" || (strings.ContainsAny(key, " \t") && !quoted) {
+		return "", "", false
+	}
+	return key, strings.TrimSpace(line[idx+1:]), true
+}
+
+// parseTOMLFrontMatter 解析常见的 TOML 前置元数据子集：
+// 键值对、字符串、数组（可跨行）以及 [table] 段落
+func parseTOMLFrontMatter(raw string) map[string]any {
+	fields := make(map[string]any)
+	lines := strings.Split(raw, "\n")
+	prefix := ""
+
+	for i := 0; i < len(lines); i++ {
+		trimmed := strings.TrimSpace(lines[i])
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+
+		if strings.HasPrefix(trimmed, "[") && strings.HasSuffix(trimmed, "]") {
+			name := strings.Trim(trimmed, "[] \t")
+			prefix = ""
+			if name != "" {
+				prefix = name + "."
+			}
+			continue
+		}
+
+		idx := strings.Index(trimmed, "=")
+		if idx <= 0 {
+			continue
+		}
+		key := strings.Trim(strings.TrimSpace(trimmed[:idx]), `"'`)
+		value := strings.TrimSpace(trimmed[idx+1:])
+
+		// 跨行数组
+		if strings.HasPrefix(value, "[") && !strings.Contains(stripFrontMatterComment(value), "]") {
+			for i+1 < len(lines) {
+				i++
+				part := stripFrontMatterComment(strings.TrimSpace(lines[i]))
+				value += " " + part
+				if strings.Contains(part, "]") {
+					break
+				}
+			}
+		}
+
+		fields[prefix+key] = parseFrontMatterValue(value)
+	}
+
+	return fields
+}
+
+// parseFrontMatterValue 解析标量或行内列表值
+func parseFrontMatterValue(value string) any {
+	value = stripFrontMatterComment(value)
+	if strings.HasPrefix(value, "[") && strings.HasSuffix(value, "]") {
+		items := make([]string, 0)
+		for _, item := range splitFrontMatterList(value[1 : len(value)-1]) {
+			if item = parseFrontMatterScalar(item); item != "" {
+				items = append(items, item)
+			}
+		}
+		return items
+	}
+	return parseFrontMatterScalar(value)
+}
+
+// parseFrontMatterScalar 去除标量值的引号和注释
+func parseFrontMatterScalar(value string) string {
+	value = strings.TrimSpace(stripFrontMatterComment(value))
+	if len(value) >= 2 {
+		first, last := value[0], value[len(value)-1]
+		if (first == '"' || first == '\'') && first == last {
+			return value[1 : len(value)-1]
+		}
+	}
+	return value
+}
+
+// stripFrontMatterComment 去除引号之外以 " #" 开始的行尾注释
+func stripFrontMatterComment(value string) string {
+	var quote byte
+	for i := 0; i < len(value); i++ {
+		switch ch := value[i]; {
+		case quote != 0:
+			if ch == quote {
+				quote = 0
+			}
+		case ch == '"' || ch == '\'':
+			quote = ch
+		case ch == '#' && (i == 0 || value[i-1] == ' ' || value[i-1] == '\t'):
+			return strings.TrimSpace(value[:i])
+		}
+	}
+	return strings.TrimSpace(value)
+}
+
+// splitFrontMatterList 按引号之外的逗号拆分行内列表
+func splitFrontMatterList(value string) []string {
+	var items []string
+	var quote byte
+	start := 0
+	for i := 0; i < len(value); i++ {
+		switch ch := value[i]; {
+		case quote != 0:
+			if ch == quote {
+				quote = 0
+			}
+		case ch == '"' || ch == '\'':
+			quote = ch
+		case ch == ',':
+			items = append(items, strings.TrimSpace(value[start:i]))
+			start = i + 1
+		}
+	}
+	return append(items, strings.TrimSpace(value[start:]))
+}
+
+// frontMatterMetadata 返回需要复制到块元数据中的前置元数据键值，键带有 fm_ 前缀
+// keys 为空时返回所有字段
+func frontMatterMetadata(fm *FrontMatter, keys []string) map[string]string {
+	if fm == nil {
+		return nil
+	}
+	if len(keys) == 0 {
+		keys = fm.Keys()
+	}
+
+	metadata := make(map[string]string, len(keys))
+	for _, key := range keys {
+		if value, ok := fm.Get(key); ok {
+			metadata[frontMatterMetadataPrefix+key] = value
+		}
+	}
+	return metadata
+}
+
+// GetFrontMatter 返回最近一次 ChunkDocument 检测到的前置元数据，没有时返回 nil
+func (c *MarkdownChunker) GetFrontMatter() *FrontMatter {
+	return c.frontMatter
+}
@@ -0,0 +1,157 @@
+package markdownchunker
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const yamlFrontMatterMarkdown = `---
+title: "Getting Started"
+author: Jane Doe # maintainer
+date: 2025-01-02
+tags: [go, markdown]
+categories:
+  - docs
+  - guide
+params:
+  draft: false
+summary: >
+  A short
+  introduction.
+---
+
+# Intro
+
+Body paragraph.`
+
+func TestFrontMatter_YAMLExcludedFromChunks(t *testing.T) {
+	chunker := NewMarkdownChunker()
+	chunks, err := chunker.ChunkDocument([]byte(yamlFrontMatterMarkdown))
+	if err != nil {
+		t.Fatalf("ChunkDocument() error = %v", err)
+	}
+
+	if len(chunks) != 2 {
+		t.Fatalf("Expected heading and paragraph only, got %d chunks: %+v", len(chunks), chunks)
+	}
+	for _, chunk := range chunks {
+		if chunk.Type == "thematic_break" || strings.Contains(chunk.Content, "author:") {
+			t.Errorf("Expected front matter to be excluded, got %q", chunk.Content)
+		}
+	}
+	if chunks[0].Position.StartLine != 16 {
+		t.Errorf("Expected line numbers to be preserved, got heading at line %d", chunks[0].Position.StartLine)
+	}
+
+	fm := chunker.GetFrontMatter()
+	if fm == nil || fm.Format != FrontMatterYAML || fm.EndLine != 14 {
+		t.Fatalf("Unexpected front matter %+v", fm)
+	}
+
+	expected := map[string]any{
+		"title":        "Getting Started",
+		"author":       "Jane Doe",
+		"date":         "2025-01-02",
+		"tags":         []string{"go", "markdown"},
+		"categories":   []string{"docs", "guide"},
+		"params.draft": "false",
+		"summary":      "A short introduction.",
+	}
+	if !reflect.DeepEqual(fm.Fields, expected) {
+		t.Errorf("Unexpected fields:\n got %v\nwant %v", fm.Fields, expected)
+	}
+}
+
+func TestFrontMatter_TOML(t *testing.T) {
+	markdown := "+++\ntitle = 'Release Notes'\ntags = [\n  \"a\",\n  \"b\",\n]\n\n[author]\nname = \"Sam\"\n+++\n\nText."
+
+	chunker := NewMarkdownChunker()
+	chunks, err := chunker.ChunkDocument([]byte(markdown))
+	if err != nil {
+		t.Fatalf("ChunkDocument() error = %v", err)
+	}
+
+	if len(chunks) != 1 || chunks[0].Content != "Text." {
+		t.Fatalf("Expected only the body paragraph, got %+v", chunks)
+	}
+
+	fm := chunker.GetFrontMatter()
+	if fm == nil || fm.Format != FrontMatterTOML {
+		t.Fatalf("Expected TOML front matter, got %+v", fm)
+	}
+	if title, _ := fm.Get("title"); title != "Release Notes" {
+		t.Errorf("Unexpected title %q", title)
+	}
+	if tags, _ := fm.Get("tags"); tags != "a,b" {
+		t.Errorf("Unexpected tags %q", tags)
+	}
+	if name, _ := fm.Get("author.name"); name != "Sam" {
+		t.Errorf("Unexpected author.name %q", name)
+	}
+}
+
+func TestFrontMatter_KeysCopiedToChunks(t *testing.T) {
+	config := DefaultConfig()
+	config.FrontMatterKeys = []string{"title", "tags", "missing"}
+
+	chunker := NewMarkdownChunkerWithConfig(config)
+	chunks, err := chunker.ChunkDocument([]byte(yamlFrontMatterMarkdown))
+	if err != nil {
+		t.Fatalf("ChunkDocument() error = %v", err)
+	}
+
+	for _, chunk := range chunks {
+		if chunk.Metadata["fm_title"] != "Getting Started" || chunk.Metadata["fm_tags"] != "go,markdown" {
+			t.Errorf("Expected front matter keys on chunk %d, got %v", chunk.ID, chunk.Metadata)
+		}
+		if _, ok := chunk.Metadata["fm_author"]; ok {
+			t.Errorf("Expected unselected keys not to be copied, chunk %d", chunk.ID)
+		}
+		if _, ok := chunk.Metadata["fm_missing"]; ok {
+			t.Errorf("Expected missing keys to be skipped, chunk %d", chunk.ID)
+		}
+	}
+}
+
+func TestFrontMatter_DocumentLevelMetadata(t *testing.T) {
+	chunker := NewMarkdownChunkerWithStrategy("document-level")
+	chunks, err := chunker.ChunkDocument([]byte(yamlFrontMatterMarkdown))
+	if err != nil {
+		t.Fatalf("ChunkDocument() error = %v", err)
+	}
+
+	if len(chunks) != 1 {
+		t.Fatalf("Expected a single document chunk, got %d", len(chunks))
+	}
+	metadata := chunks[0].Metadata
+	if metadata["front_matter_format"] != "yaml" || metadata["fm_author"] != "Jane Doe" {
+		t.Errorf("Expected document metadata to include front matter, got %v", metadata)
+	}
+	if metadata["heading_count"] != "1" {
+		t.Errorf("Expected front matter not to be counted as content, got %v", metadata)
+	}
+}
+
+func TestFrontMatter_NotDetected(t *testing.T) {
+	tests := []string{
+		"# Title\n\n---\ntitle: x\n---",
+		"---\n\nSome paragraph\n---",
+		"---\ntitle: unterminated",
+	}
+
+	for _, markdown := range tests {
+		fm, content := extractFrontMatter([]byte(markdown))
+		if fm != nil || string(content) != markdown {
+			t.Errorf("Expected no front matter for %q, got %+v", markdown, fm)
+		}
+	}
+}
+
+func TestFrontMatter_InvalidConfig(t *testing.T) {
+	config := DefaultConfig()
+	config.FrontMatterKeys = []string{"title", " "}
+	if err := ValidateConfig(config); err == nil {
+		t.Error("Expected error for empty front matter key")
+	}
+}
@@ -0,0 +1,128 @@
+package markdownchunker
+
+import (
+	"html"
+	"regexp"
+	"strings"
+
+	"github.com/yuin/goldmark/ast"
+)
+
+var (
+	// htmlTagNamePattern 匹配 HTML 块开头的标签名
+	htmlTagNamePattern = regexp.MustCompile(`^\s*<\s*/?\s*([A-Za-z][A-Za-z0-9-]*)`)
+
+	// htmlInvisiblePattern 匹配注释以及 script/style 等不可见内容
+	htmlInvisiblePattern = regexp.MustCompile(`(?is)<!--.*?-->|<script\b.*?</script\s*>|<style\b.*?</style\s*>`)
+
+	// htmlTagPattern 匹配任意标签
+	htmlTagPattern = regexp.MustCompile(`(?s)<[^>]*>`)
+
+	// htmlAnchorPattern 匹配带 href 的 <a> 元素
+	htmlAnchorPattern = regexp.MustCompile(`(?is)<a\b([^>]*)>(.*?)</a\s*>`)
+
+	// htmlImagePattern 匹配 <img> 元素
+	htmlImagePattern = regexp.MustCompile(`(?is)<img\b([^>]*)>`)
+
+	// htmlAttributePattern 匹配标签属性，支持双引号、单引号和无引号的值
+	htmlAttributePattern = regexp.MustCompile(`(?s)([A-Za-z_:][-A-Za-z0-9_:.]*)\s*=\s*(?:"([^"]*)"|'([^']*)'|([^\s"'=<>` + "`" + `]+))`)
+)
+
+// htmlBlockTagName 返回 HTML 块开头的标签名（小写），注释等非元素块返回类别名
+func htmlBlockTagName(content string) string {
+	trimmed := strings.TrimSpace(content)
+	switch {
+	case strings.HasPrefix(trimmed, "<!--"):
+		return "comment"
+	case strings.HasPrefix(trimmed, "<![CDATA["):
+		return "cdata"
+	case strings.HasPrefix(trimmed, "<!"):
+		return "declaration"
+	case strings.HasPrefix(trimmed, "<?"):
+		return "processing_instruction"
+	}
+
+	if match := htmlTagNamePattern.FindStringSubmatch(trimmed); match != nil {
+		return strings.ToLower(match[1])
+	}
+	return ""
+}
+
+// htmlVisibleText 去除标签、注释和脚本后返回可见文本
+func htmlVisibleText(content string) string {
+	visible := htmlInvisiblePattern.ReplaceAllString(content, " ")
+	visible = htmlTagPattern.ReplaceAllString(visible, " ")
+	return strings.Join(strings.Fields(html.UnescapeString(visible)), " ")
+}
+
+// htmlAttributes 解析标签属性字符串，属性名统一为小写
+func htmlAttributes(attrs string) map[string]string {
+	result := make(map[string]string)
+	for _, match := range htmlAttributePattern.FindAllStringSubmatch(attrs, -1) {
+		value := match[2]
+		if value == "" {
+			value = match[3]
+		}
+		if value == "" {
+			value = match[4]
+		}
+		result[strings.ToLower(match[1])] = html.UnescapeString(value)
+	}
+	return result
+}
+
+// getHTMLBlockContent 返回 HTML 块的原始内容，包括结束行
+func (c *MarkdownChunker) getHTMLBlockContent(block *ast.HTMLBlock) string {
+	var buf strings.Builder
+	for i := 0; i < block.Lines().Len(); i++ {
+		line := block.Lines().At(i)
+		buf.Write(line.Value(c.source))
+	}
+	if block.HasClosure() {
+		buf.Write(block.ClosureLine.Value(c.source))
+	}
+	return strings.TrimRight(buf.String(), "\r\n")
+}
+
+// extractHTMLLinks 从 href 属性中提取链接
+func (c *MarkdownChunker) extractHTMLLinks(content string) []Link {
+	links := make([]Link, 0)
+	for _, match := range htmlAnchorPattern.FindAllStringSubmatch(content, -1) {
+		href := htmlAttributes(match[1])["href"]
+		if href == "" {
+			continue
+		}
+
+		linkText := htmlVisibleText(match[2])
+		if linkText == "" {
+			linkText = href
+		}
+
+		links = append(links, Link{
+			Text: linkText,
+			URL:  href,
+			Type: c.determineLinkType(href),
+		})
+	}
+	return links
+}
+
+// extractHTMLImages 从 <img> 的 src 属性中提取图片
+func extractHTMLImages(content string) []Image {
+	images := make([]Image, 0)
+	for _, match := range htmlImagePattern.FindAllStringSubmatch(content, -1) {
+		attrs := htmlAttributes(match[1])
+		if attrs["src"] == "" {
+			continue
+		}
+
+		images = append(images, Image{
+			Alt:    attrs["alt"],
+			URL:    attrs["src"],
+			Title:  attrs["title"],
+			Width:  attrs["width"],
+			Height: attrs["height"],
+		})
+	}
+	return images
+}
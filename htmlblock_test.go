@@ -0,0 +1,102 @@
+package markdownchunker
+
+import (
+	"testing"
+)
+
+const htmlBlockMarkdown = `# Page
+
+<div class="note">
+  <p>See the <a href="https://example.com/docs">full &amp; complete docs</a>.</p>
+  <img src="diagram.png" alt="Diagram" width="200">
+  <!-- hidden comment -->
+</div>
+
+<details>
+<summary>More info</summary>
+
+Inner paragraph.
+
+</details>`
+
+func TestHTMLBlock_Chunked(t *testing.T) {
+	chunker := NewMarkdownChunker()
+	chunks, err := chunker.ChunkDocument([]byte(htmlBlockMarkdown))
+	if err != nil {
+		t.Fatalf("ChunkDocument() error = %v", err)
+	}
+
+	blocks := chunksOfType(chunks, "html")
+	if len(blocks) != 2 {
+		t.Fatalf("Expected 2 html chunks with text, got %d: %+v", len(blocks), blocks)
+	}
+
+	div := blocks[0]
+	if div.Metadata["tag_name"] != "div" {
+		t.Errorf("Expected tag_name div, got %q", div.Metadata["tag_name"])
+	}
+	if div.Content[:18] != `<div class="note">` || div.Content[len(div.Content)-6:] != "</div>" {
+		t.Errorf("Expected raw HTML content, got %q", div.Content)
+	}
+	if div.Text != "See the full & complete docs ." {
+		t.Errorf("Expected visible text without tags or comments, got %q", div.Text)
+	}
+	if len(div.Links) != 1 || div.Links[0].URL != "https://example.com/docs" ||
+		div.Links[0].Text != "full & complete docs" || div.Links[0].Type != "external" {
+		t.Errorf("Unexpected links %+v", div.Links)
+	}
+	if len(div.Images) != 1 || div.Images[0].URL != "diagram.png" || div.Images[0].Alt != "Diagram" || div.Images[0].Width != "200" {
+		t.Errorf("Unexpected images %+v", div.Images)
+	}
+	if div.Position.StartLine != 3 || div.Position.EndLine != 7 {
+		t.Errorf("Expected position 3-7, got %+v", div.Position)
+	}
+
+	details := blocks[1]
+	if details.Metadata["tag_name"] != "details" || details.Text != "More info" {
+		t.Errorf("Unexpected details chunk %+v", details)
+	}
+
+	var foundInner bool
+	for _, chunk := range chunks {
+		if chunk.Type == "paragraph" && chunk.Text == "Inner paragraph." {
+			foundInner = true
+		}
+	}
+	if !foundInner {
+		t.Error("Expected markdown inside <details> to be chunked as a paragraph")
+	}
+}
+
+func TestHTMLBlock_EnabledTypes(t *testing.T) {
+	config := DefaultConfig()
+	config.EnabledTypes = map[string]bool{"heading": true, "paragraph": true}
+
+	chunker := NewMarkdownChunkerWithConfig(config)
+	chunks, err := chunker.ChunkDocument([]byte(htmlBlockMarkdown))
+	if err != nil {
+		t.Fatalf("ChunkDocument() error = %v", err)
+	}
+	if len(chunksOfType(chunks, "html")) != 0 {
+		t.Error("Expected html chunks to be disabled")
+	}
+
+	config.EnabledTypes["html"] = true
+	if err := ValidateConfig(config); err != nil {
+		t.Errorf("Expected html to be a valid content type, got %v", err)
+	}
+}
+
+func TestHTMLBlockTagName(t *testing.T) {
+	tests := map[string]string{
+		"<TABLE>\n<tr><td>x</td></tr>\n</TABLE>": "table",
+		"</div>":                                 "div",
+		"<!-- note -->":                          "comment",
+		"<?php echo 1; ?>":                       "processing_instruction",
+	}
+	for content, expected := range tests {
+		if got := htmlBlockTagName(content); got != expected {
+			t.Errorf("htmlBlockTagName(%q) = %q, want %q", content, got, expected)
+		}
+	}
+}
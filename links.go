@@ -0,0 +1,76 @@
+package markdownchunker
+
+// NoChunkID 表示不存在对应的块，如第一个块的 PrevID 或不属于任何章节的块的 ParentID
+const NoChunkID = -1
+
+// linkChunks 按文档顺序填充块之间的导航关系
+// PrevID/NextID 指向相邻的块，ParentID 指向所在章节的标题块（Level > 0 的块），
+// ChildIDs 为以该块为父块的所有块
+func linkChunks(chunks []Chunk) []Chunk {
+	index := make(map[int]int, len(chunks))
+	// openers 为当前打开的章节标题块在 chunks 中的下标，层级由外到内
+	var openers []int
+
+	for i := range chunks {
+		chunk := &chunks[i]
+		chunk.PrevID, chunk.NextID, chunk.ParentID = NoChunkID, NoChunkID, NoChunkID
+		chunk.ChildIDs = nil
+		if i > 0 {
+			chunk.PrevID = chunks[i-1].ID
+			chunks[i-1].NextID = chunk.ID
+		}
+		index[chunk.ID] = i
+
+		if chunk.Level > 0 {
+			// 弹出层级不高于当前标题的章节
+			for len(openers) > 0 && chunks[openers[len(openers)-1]].Level >= chunk.Level {
+				openers = openers[:len(openers)-1]
+			}
+		}
+		if len(openers) > 0 {
+			chunk.ParentID = chunks[openers[len(openers)-1]].ID
+		}
+		if chunk.Level > 0 {
+			openers = append(openers, i)
+		}
+	}
+
+	for i := range chunks {
+		if parent, ok := index[chunks[i].ParentID]; ok && chunks[i].ParentID != NoChunkID {
+			chunks[parent].ChildIDs = append(chunks[parent].ChildIDs, chunks[i].ID)
+		}
+	}
+
+	return chunks
+}
+
+// remapChunkLinks 在块 ID 变化后更新导航关系
+// idMap 为原 ID 到新 ID 的映射，指向已移除块的 ParentID 和 ChildIDs 会被丢弃，
+// PrevID/NextID 按当前顺序重新计算
+func remapChunkLinks(chunks []Chunk, idMap map[int]int) []Chunk {
+	for i := range chunks {
+		chunk := &chunks[i]
+		chunk.PrevID, chunk.NextID = NoChunkID, NoChunkID
+		if i > 0 {
+			chunk.PrevID = chunks[i-1].ID
+		}
+		if i < len(chunks)-1 {
+			chunk.NextID = chunks[i+1].ID
+		}
+
+		if parentID, ok := idMap[chunk.ParentID]; ok && chunk.ParentID != NoChunkID {
+			chunk.ParentID = parentID
+		} else {
+			chunk.ParentID = NoChunkID
+		}
+
+		var childIDs []int
+		for _, childID := range chunk.ChildIDs {
+			if newID, ok := idMap[childID]; ok {
+				childIDs = append(childIDs, newID)
+			}
+		}
+		chunk.ChildIDs = childIDs
+	}
+	return chunks
+}
@@ -0,0 +1,145 @@
+package markdownchunker
+
+import (
+	"reflect"
+	"testing"
+)
+
+const linksMarkdown = `Preamble.
+
+# Install
+
+Intro.
+
+## Linux
+
+Run the script.
+
+## macOS
+
+# Usage
+
+Done.`
+
+func TestChunkLinks_ElementLevel(t *testing.T) {
+	chunker := NewMarkdownChunker()
+	chunks, err := chunker.ChunkDocument([]byte(linksMarkdown))
+	if err != nil {
+		t.Fatalf("ChunkDocument() error = %v", err)
+	}
+	if len(chunks) != 8 {
+		t.Fatalf("Expected 8 chunks, got %d", len(chunks))
+	}
+
+	expectedParents := []int{NoChunkID, NoChunkID, 1, 1, 3, 1, NoChunkID, 6}
+	for i, chunk := range chunks {
+		if chunk.ParentID != expectedParents[i] {
+			t.Errorf("Chunk %d (%q): expected parent %d, got %d", i, chunk.Text, expectedParents[i], chunk.ParentID)
+		}
+
+		prev, next := NoChunkID, NoChunkID
+		if i > 0 {
+			prev = chunks[i-1].ID
+		}
+		if i < len(chunks)-1 {
+			next = chunks[i+1].ID
+		}
+		if chunk.PrevID != prev || chunk.NextID != next {
+			t.Errorf("Chunk %d: expected prev/next %d/%d, got %d/%d", i, prev, next, chunk.PrevID, chunk.NextID)
+		}
+	}
+
+	if !reflect.DeepEqual(chunks[1].ChildIDs, []int{2, 3, 5}) {
+		t.Errorf("Expected Install to have children [2 3 5], got %v", chunks[1].ChildIDs)
+	}
+	if !reflect.DeepEqual(chunks[3].ChildIDs, []int{4}) {
+		t.Errorf("Expected Linux to have child [4], got %v", chunks[3].ChildIDs)
+	}
+	if len(chunks[5].ChildIDs) != 0 {
+		t.Errorf("Expected empty section to have no children, got %v", chunks[5].ChildIDs)
+	}
+}
+
+func TestChunkLinks_HierarchicalStrategy(t *testing.T) {
+	chunker := NewMarkdownChunkerWithStrategy("hierarchical")
+	chunks, err := chunker.ChunkDocument([]byte(linksMarkdown))
+	if err != nil {
+		t.Fatalf("ChunkDocument() error = %v", err)
+	}
+
+	ids := make(map[int]bool)
+	for _, chunk := range chunks {
+		ids[chunk.ID] = true
+	}
+	for i, chunk := range chunks {
+		if chunk.ParentID != NoChunkID && !ids[chunk.ParentID] {
+			t.Errorf("Chunk %d points to missing parent %d", i, chunk.ParentID)
+		}
+		for _, childID := range chunk.ChildIDs {
+			if !ids[childID] {
+				t.Errorf("Chunk %d points to missing child %d", i, childID)
+			}
+		}
+	}
+	if chunks[0].PrevID != NoChunkID || chunks[len(chunks)-1].NextID != NoChunkID {
+		t.Errorf("Expected document ends to have no neighbours, got %+v", chunks)
+	}
+}
+
+func TestChunkLinks_KeptConsistentAfterSplit(t *testing.T) {
+	config := DefaultConfig()
+	config.SplitLists = true
+	config.ListMaxSize = 20
+
+	chunker := NewMarkdownChunkerWithConfig(config)
+	chunks, err := chunker.ChunkDocument([]byte("# Steps\n\n- first step\n- second step\n- third step\n\nAfter."))
+	if err != nil {
+		t.Fatalf("ChunkDocument() error = %v", err)
+	}
+	if len(chunks) < 4 {
+		t.Fatalf("Expected the list to be split, got %d chunks", len(chunks))
+	}
+
+	var children []int
+	for i, chunk := range chunks[1:] {
+		if chunk.ParentID != chunks[0].ID {
+			t.Errorf("Chunk %d: expected parent %d, got %d", i+1, chunks[0].ID, chunk.ParentID)
+		}
+		if chunk.PrevID != chunks[i].ID {
+			t.Errorf("Chunk %d: expected prev %d, got %d", i+1, chunks[i].ID, chunk.PrevID)
+		}
+		children = append(children, chunk.ID)
+	}
+	if !reflect.DeepEqual(chunks[0].ChildIDs, children) {
+		t.Errorf("Expected heading children %v, got %v", children, chunks[0].ChildIDs)
+	}
+}
+
+func TestSanitizeStrategyOutput_RemapsLinks(t *testing.T) {
+	chunker := NewMarkdownChunker()
+	chunks := []Chunk{
+		{ID: 0, Type: "heading", Level: 1, Content: "# A", Text: "A", ParentID: NoChunkID, ChildIDs: []int{1}},
+		{ID: 5, Type: "paragraph", ParentID: 0},
+		{ID: 1, Type: "paragraph", Content: "x", Text: "x", ParentID: 0},
+		{ID: 1, Type: "paragraph", Content: "y", Text: "y", ParentID: 5},
+	}
+
+	// 空块被过滤，重复的 ID 1 被重新编号为 2
+	sanitized := chunker.sanitizeStrategyOutput(chunks)
+	if len(sanitized) != 3 {
+		t.Fatalf("Expected 3 chunks after sanitizing, got %d", len(sanitized))
+	}
+	if sanitized[2].ID != 2 {
+		t.Fatalf("Expected duplicate ID to be renumbered to 2, got %d", sanitized[2].ID)
+	}
+
+	if sanitized[1].ParentID != 0 || sanitized[1].PrevID != 0 || sanitized[1].NextID != 2 {
+		t.Errorf("Unexpected links on chunk 1: %+v", sanitized[1])
+	}
+	if sanitized[2].ParentID != NoChunkID {
+		t.Errorf("Expected link to the filtered chunk to be dropped, got parent %d", sanitized[2].ParentID)
+	}
+	if !reflect.DeepEqual(sanitized[0].ChildIDs, []int{1}) {
+		t.Errorf("Expected children [1], got %v", sanitized[0].ChildIDs)
+	}
+}
@@ -0,0 +1,409 @@
+package markdownchunker
+
+import (
+	"fmt"
+	"maps"
+	"strings"
+
+	"github.com/yuin/goldmark/ast"
+	extast "github.com/yuin/goldmark/extension/ast"
+	"github.com/yuin/goldmark/text"
+)
+
+// listAncestor 被拆分列表项的祖先项
+type listAncestor struct {
+	list  *ast.List
+	index int
+	item  *ast.ListItem
+}
+
+// listGroup 拆分后的一组连续列表项 [start, end)
+type listGroup struct {
+	ancestors []listAncestor
+	list      *ast.List
+	start     int
+	end       int
+	headOnly  bool // 只包含列表项自身内容，嵌套列表单独成组
+}
+
+// listItems 返回列表的直接子项
+func listItems(list *ast.List) []*ast.ListItem {
+	var items []*ast.ListItem
+	for child := list.FirstChild(); child != nil; child = child.NextSibling() {
+		if item, ok := child.(*ast.ListItem); ok {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// listMarker 返回列表第 index 项（从0开始）的标记，有序列表从原始起始编号开始计数
+func listMarker(list *ast.List, index int) string {
+	if !list.IsOrdered() {
+		return "- "
+	}
+	delimiter := list.Marker
+	if delimiter != '.' && delimiter != ')' {
+		delimiter = '.'
+	}
+	return fmt.Sprintf("%d%c ", list.Start+index, delimiter)
+}
+
+// renderList 将列表渲染为 markdown，包括嵌套列表，有序列表保留原始起始编号
+func (c *MarkdownChunker) renderList(list *ast.List) string {
+	var lines []string
+	for i, item := range listItems(list) {
+		lines = append(lines, c.renderListItem(item, listMarker(list, i), true))
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// listItemBlockContent 返回列表项中非列表子块的 markdown 内容
+func (c *MarkdownChunker) listItemBlockContent(node ast.Node) string {
+	switch node.(type) {
+	case *ast.Paragraph, *ast.TextBlock:
+		lines := make([]string, 0, node.Lines().Len())
+		for i := 0; i < node.Lines().Len(); i++ {
+			line := node.Lines().At(i)
+			lines = append(lines, strings.TrimLeft(strings.TrimRight(string(line.Value(c.source)), "\n"), " \t"))
+		}
+		return strings.Join(lines, "\n")
+	default:
+		return c.getNodeRawContent(node)
+	}
+}
+
+// renderListItem 将列表项渲染为 markdown，续行按标记宽度缩进
+// includeNested 为 false 时省略嵌套列表
+func (c *MarkdownChunker) renderListItem(item *ast.ListItem, marker string, includeNested bool) string {
+	var buf strings.Builder
+	previousIsList := true
+
+	for child := item.FirstChild(); child != nil; child = child.NextSibling() {
+		var block string
+		nested, isList := child.(*ast.List)
+		if isList {
+			if !includeNested {
+				continue
+			}
+			block = c.renderList(nested)
+		} else {
+			block = c.listItemBlockContent(child)
+		}
+		if block == "" {
+			continue
+		}
+
+		if buf.Len() > 0 {
+			// 相邻的非列表块之间需要空行，否则会被合并为一个段落
+			if !isList && !previousIsList {
+				buf.WriteString("\n\n")
+			} else {
+				buf.WriteByte('\n')
+			}
+		}
+		buf.WriteString(block)
+		previousIsList = isList
+	}
+
+	return marker + indentListLines(buf.String(), strings.Repeat(" ", len(marker)), false)
+}
+
+// listMarkdown 返回列表块对应列表节点的完整 markdown，包括嵌套列表和续行
+// 默认的列表块内容不保留列表项内的换行；块没有列表节点时返回块内容
+func (c *MarkdownChunker) listMarkdown(chunk Chunk) string {
+	if list, ok := chunk.node.(*ast.List); ok {
+		return c.renderList(list)
+	}
+	return chunk.Content
+}
+
+// indentListLines 为多行内容添加缩进，空行保持为空
+func indentListLines(content, indent string, includeFirst bool) string {
+	lines := strings.Split(content, "\n")
+	for i, line := range lines {
+		if line == "" || (i == 0 && !includeFirst) {
+			continue
+		}
+		lines[i] = indent + line
+	}
+	return strings.Join(lines, "\n")
+}
+
+// listItemHeadText 返回列表项第一个文本块的纯文本，用作祖先上下文
+func (c *MarkdownChunker) listItemHeadText(item *ast.ListItem) string {
+	for child := item.FirstChild(); child != nil; child = child.NextSibling() {
+		switch child.(type) {
+		case *ast.Paragraph, *ast.TextBlock:
+			return c.getNodeText(child)
+		}
+	}
+	return ""
+}
+
+// renderListGroup 渲染一组列表项，并在前面加上祖先项链
+func (c *MarkdownChunker) renderListGroup(group listGroup) string {
+	var lines []string
+	indent := ""
+	for _, ancestor := range group.ancestors {
+		marker := listMarker(ancestor.list, ancestor.index)
+		lines = append(lines, indent+marker+c.listItemHeadText(ancestor.item))
+		indent += strings.Repeat(" ", len(marker))
+	}
+
+	items := listItems(group.list)
+	for i := group.start; i < group.end; i++ {
+		item := c.renderListItem(items[i], listMarker(group.list, i), !group.headOnly)
+		lines = append(lines, indentListLines(item, indent, true))
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// groupListItems 在列表项边界处贪心分组，过大的列表项将其嵌套列表递归拆分
+// 列表项本身不会被拆开，即使它超过大小上限
+func (c *MarkdownChunker) groupListItems(list *ast.List, ancestors []listAncestor, maxSize int) []listGroup {
+	items := listItems(list)
+
+	var groups []listGroup
+	current := listGroup{start: -1}
+	flush := func() {
+		if current.start >= 0 {
+			groups = append(groups, current)
+			current = listGroup{start: -1}
+		}
+	}
+
+	for i, item := range items {
+		single := listGroup{ancestors: ancestors, list: list, start: i, end: i + 1}
+		if c.measureSize(c.renderListGroup(single)) > maxSize {
+			flush()
+
+			var nestedLists []*ast.List
+			for child := item.FirstChild(); child != nil; child = child.NextSibling() {
+				if nested, ok := child.(*ast.List); ok {
+					nestedLists = append(nestedLists, nested)
+				}
+			}
+			if len(nestedLists) == 0 {
+				groups = append(groups, single)
+				continue
+			}
+
+			single.headOnly = true
+			groups = append(groups, single)
+
+			childAncestors := make([]listAncestor, len(ancestors), len(ancestors)+1)
+			copy(childAncestors, ancestors)
+			childAncestors = append(childAncestors, listAncestor{list: list, index: i, item: item})
+			for _, nested := range nestedLists {
+				groups = append(groups, c.groupListItems(nested, childAncestors, maxSize)...)
+			}
+			continue
+		}
+
+		if current.start < 0 {
+			current = single
+			continue
+		}
+
+		candidate := current
+		candidate.end = i + 1
+		if c.measureSize(c.renderListGroup(candidate)) <= maxSize {
+			current = candidate
+		} else {
+			flush()
+			current = single
+		}
+	}
+	flush()
+
+	return groups
+}
+
+// splitListChunk 在列表项边界处拆分长列表，嵌套项拆出时在前面加上祖先项链
+// 每个片段保留原始列表类型和编号。块带有列表节点时按列表项在源内容中的位置计算片段位置，
+// 否则重新解析块内容，片段沿用块的位置
+func (c *MarkdownChunker) splitListChunk(chunk Chunk, maxSize int) []Chunk {
+	if maxSize <= 0 || c.measureSize(chunk.Content) <= maxSize {
+		return []Chunk{chunk}
+	}
+
+	list, fromSource := chunk.node.(*ast.List)
+	source := c.source
+	if !fromSource {
+		source = []byte(chunk.Content)
+		list = firstList(c.md.Parser().Parse(text.NewReader(source)))
+	}
+	if list == nil {
+		return []Chunk{chunk}
+	}
+
+	// 渲染和文本提取依赖 c.source，临时切换到列表所在的内容
+	originalSource := c.source
+	c.source = source
+	groups := c.groupListItems(list, nil, maxSize)
+	contents := make([]string, len(groups))
+	positions := make([]ChunkPosition, len(groups))
+	var lineStarts []int
+	if fromSource {
+		lineStarts = lineStartOffsets(source)
+	}
+	for i, group := range groups {
+		contents[i] = c.renderListGroup(group)
+		positions[i] = chunk.Position
+		if span, ok := listGroupSpan(group, source); ok && fromSource {
+			positions[i] = spanPosition(lineStarts, span)
+		}
+	}
+	c.source = originalSource
+
+	if len(groups) <= 1 {
+		return []Chunk{chunk}
+	}
+
+	pieces := make([]Chunk, 0, len(groups))
+	for i, group := range groups {
+		content := contents[i]
+		pieceText, links, images := c.analyzeListFragment(content)
+
+		listType := "unordered"
+		if group.list.IsOrdered() {
+			listType = "ordered"
+		}
+
+		metadata := make(map[string]string, len(chunk.Metadata)+8)
+		maps.Copy(metadata, chunk.Metadata)
+		metadata["list_type"] = listType
+		metadata["item_count"] = fmt.Sprintf("%d", group.end-group.start)
+		metadata["item_range"] = listItemRange(group)
+		metadata["list_depth"] = fmt.Sprintf("%d", len(group.ancestors))
+		metadata["list_part"] = fmt.Sprintf("%d", i+1)
+		metadata["list_part_total"] = fmt.Sprintf("%d", len(groups))
+		metadata["original_chunk_id"] = fmt.Sprintf("%d", chunk.ID)
+		if group.list.IsOrdered() {
+			metadata["list_start"] = fmt.Sprintf("%d", group.list.Start)
+		}
+		updateSplitMetadata(metadata, positions[i], content, pieceText)
+
+		piece := Chunk{
+			ID:          chunk.ID,
+			Type:        chunk.Type,
+			Content:     content,
+			Text:        pieceText,
+			Level:       chunk.Level,
+			Metadata:    metadata,
+			Position:    positions[i],
+			Links:       links,
+			Images:      images,
+			Hash:        c.calculateContentHash(content),
+			SectionPath: chunk.SectionPath,
+		}
+		c.reattachFootnotes(&piece)
+		pieces = append(pieces, piece)
+	}
+
+	return pieces
+}
+
+// firstList 返回文档的第一个顶层列表
+func firstList(doc ast.Node) *ast.List {
+	if doc == nil {
+		return nil
+	}
+	for child := doc.FirstChild(); child != nil; child = child.NextSibling() {
+		if list, ok := child.(*ast.List); ok {
+			return list
+		}
+	}
+	return nil
+}
+
+// listItemRange 返回一组列表项的范围，嵌套项以祖先项序号为前缀，如 1.2-1.3
+func listItemRange(group listGroup) string {
+	prefix := ""
+	for _, ancestor := range group.ancestors {
+		prefix += fmt.Sprintf("%d.", ancestor.index+1)
+	}
+	return fmt.Sprintf("%s%d-%s%d", prefix, group.start+1, prefix, group.end)
+}
+
+// listGroupSpan 返回一组列表项在列表内容中的范围，不包括前置的祖先项链
+// 只包含列表项自身内容的组不包括其嵌套列表
+func listGroupSpan(group listGroup, source []byte) (textSpan, bool) {
+	items := listItems(group.list)
+	start := nodeLineStart(items[group.start], source)
+	if start < 0 {
+		return textSpan{}, false
+	}
+
+	last := items[group.end-1]
+	end := start
+	ast.Walk(last, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			return ast.WalkContinue, nil
+		}
+		if _, isList := n.(*ast.List); isList && group.headOnly && n.Parent() == last {
+			return ast.WalkSkipChildren, nil
+		}
+		if n.Type() == ast.TypeBlock && n.Lines().Len() > 0 {
+			end = max(end, n.Lines().At(n.Lines().Len()-1).Stop)
+		}
+		return ast.WalkContinue, nil
+	})
+
+	end = min(end, len(source))
+	for end > start && (source[end-1] == '\n' || source[end-1] == ' ') {
+		end--
+	}
+	return textSpan{start: start, end: end}, end > start
+}
+
+// analyzeListFragment 重新解析列表片段，返回片段的纯文本、链接和图片
+func (c *MarkdownChunker) analyzeListFragment(content string) (string, []Link, []Image) {
+	source := c.fragmentSource(content)
+	doc := c.md.Parser().Parse(text.NewReader(source))
+
+	// getListText 等方法依赖 c.source，临时切换到片段内容
+	originalSource := c.source
+	c.source = source
+	defer func() {
+		c.source = originalSource
+	}()
+
+	var parts []string
+	links := make([]Link, 0)
+	images := make([]Image, 0)
+	for child := doc.FirstChild(); child != nil; child = child.NextSibling() {
+		if _, ok := child.(*extast.FootnoteList); ok {
+			continue
+		}
+		if list, ok := child.(*ast.List); ok {
+			parts = append(parts, c.getListText(list))
+		} else {
+			parts = append(parts, c.getNodeText(child))
+		}
+		links = append(links, c.extractLinks(child)...)
+		images = append(images, c.extractImages(child)...)
+	}
+
+	return strings.Join(parts, " "), links, images
+}
+
+// shouldSplitListChunk 判断列表是否需要按列表项拆分
+func (c *MarkdownChunker) shouldSplitListChunk(chunk Chunk) bool {
+	if c.config == nil || !c.config.SplitLists || chunk.Type != "list" {
+		return false
+	}
+	limit := c.listSplitLimit(chunk)
+	return limit > 0 && c.measureSize(chunk.Content) > limit
+}
+
+// listSplitLimit 返回列表拆分的大小上限
+func (c *MarkdownChunker) listSplitLimit(chunk Chunk) int {
+	if c.config == nil {
+		return 0
+	}
+	return c.splitLimit(chunk, c.config.ListMaxSize)
+}
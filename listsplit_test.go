@@ -0,0 +1,186 @@
+package markdownchunker
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+)
+
+const nestedListMarkdown = `3. Install the toolchain
+   - Download the archive from the website
+   - Verify the checksum of the archive
+   - Extract the archive into the target folder
+4. Configure the project
+5. Run the tests`
+
+func TestReconstructList_PreservesNestingAndStart(t *testing.T) {
+	config := DefaultConfig()
+	config.SplitLists = true
+
+	chunker := NewMarkdownChunkerWithConfig(config)
+	chunks, err := chunker.ChunkDocument([]byte(nestedListMarkdown))
+	if err != nil {
+		t.Fatalf("ChunkDocument() error = %v", err)
+	}
+
+	lists := chunksOfType(chunks, "list")
+	if len(lists) != 1 {
+		t.Fatalf("Expected one list chunk, got %d", len(lists))
+	}
+	if lists[0].Content != nestedListMarkdown {
+		t.Errorf("Expected nested list content to be preserved, got %q", lists[0].Content)
+	}
+
+	// 未启用列表拆分时保持原有的列表内容
+	chunks, err = NewMarkdownChunker().ChunkDocument([]byte(nestedListMarkdown))
+	if err != nil {
+		t.Fatalf("ChunkDocument() error = %v", err)
+	}
+	if lists = chunksOfType(chunks, "list"); len(lists) != 1 || !strings.HasPrefix(lists[0].Content, "1. Install the toolchainDownload") {
+		t.Errorf("Expected default list content to be unchanged, got %+v", lists)
+	}
+}
+
+func TestListSplit_AncestorContext(t *testing.T) {
+	config := DefaultConfig()
+	config.SplitLists = true
+	config.ListMaxSize = 110
+
+	chunker := NewMarkdownChunkerWithConfig(config)
+	chunks, err := chunker.ChunkDocument([]byte(nestedListMarkdown))
+	if err != nil {
+		t.Fatalf("ChunkDocument() error = %v", err)
+	}
+
+	parts := chunksOfType(chunks, "list")
+	if len(parts) < 3 {
+		t.Fatalf("Expected list to be split into at least 3 parts, got %d", len(parts))
+	}
+
+	if parts[0].Content != "3. Install the toolchain" || parts[0].Metadata["item_range"] != "1-1" {
+		t.Errorf("Expected first part to hold the parent item, got %q (%s)", parts[0].Content, parts[0].Metadata["item_range"])
+	}
+
+	var nestedParts int
+	for _, part := range parts {
+		if part.Metadata["list_depth"] != "1" {
+			continue
+		}
+		nestedParts++
+		if !strings.HasPrefix(part.Content, "3. Install the toolchain\n   - ") {
+			t.Errorf("Expected nested part to be prefixed with its ancestor item, got %q", part.Content)
+		}
+		if !strings.HasPrefix(part.Text, "Install the toolchain") {
+			t.Errorf("Expected nested part text to include ancestor text, got %q", part.Text)
+		}
+		if part.Metadata["list_type"] != "unordered" {
+			t.Errorf("Expected nested part to keep the nested list type, got %s", part.Metadata["list_type"])
+		}
+	}
+	if nestedParts < 2 {
+		t.Errorf("Expected nested items to be split into several parts, got %d", nestedParts)
+	}
+
+	last := parts[len(parts)-1]
+	if !strings.HasPrefix(last.Content, "4. Configure the project\n5. Run the tests") {
+		t.Errorf("Expected original numbering to be kept, got %q", last.Content)
+	}
+	if last.Metadata["item_range"] != "2-3" || last.Metadata["list_start"] != "3" || last.Metadata["list_type"] != "ordered" {
+		t.Errorf("Unexpected metadata for last part: %v", last.Metadata)
+	}
+
+	for i, part := range parts {
+		if len(part.Content) > 110 {
+			t.Errorf("Expected part %d within size limit, got %d", i, len(part.Content))
+		}
+	}
+
+	// 每个片段的位置只覆盖自己的列表项，不包括前置的祖先项链
+	if parts[0].Position.StartLine != 1 || parts[0].Position.EndLine != 1 {
+		t.Errorf("Unexpected position of the parent item part %+v", parts[0].Position)
+	}
+	if parts[1].Position.StartLine != 2 || last.Position.StartLine != 5 || last.Position.EndLine != 6 {
+		t.Errorf("Expected parts to carry their own line ranges, got %+v and %+v", parts[1].Position, last.Position)
+	}
+	for i := 1; i < len(parts); i++ {
+		if parts[i].Position.StartLine <= parts[i-1].Position.StartLine {
+			t.Errorf("Expected part %d to start after part %d, got %+v", i, i-1, parts[i].Position)
+		}
+	}
+}
+
+func TestListSplit_PositionsFromSource(t *testing.T) {
+	markdown := "# Steps\n\n3. Alpha item\n4. Beta item\n   - nested one\n   - nested two\n   - nested three\n5. Gamma item\n"
+
+	config := DefaultConfig()
+	config.SplitLists = true
+	config.ListMaxSize = 40
+
+	chunks, err := NewMarkdownChunkerWithConfig(config).ChunkDocument([]byte(markdown))
+	if err != nil {
+		t.Fatalf("ChunkDocument() error = %v", err)
+	}
+
+	expected := []struct {
+		itemRange string
+		line      int
+		endCol    int
+	}{
+		{"1-1", 3, 14},
+		{"2-2", 4, 13},
+		{"2.1-2.1", 5, 16},
+		{"2.2-2.2", 6, 16},
+		{"2.3-2.3", 7, 18},
+		{"3-3", 8, 14},
+	}
+	parts := chunksOfType(chunks, "list")
+	if len(parts) != len(expected) {
+		t.Fatalf("Expected %d parts, got %d", len(expected), len(parts))
+	}
+	for i, want := range expected {
+		part := parts[i]
+		if part.Metadata["item_range"] != want.itemRange {
+			t.Errorf("Part %d: item_range = %s, want %s", i, part.Metadata["item_range"], want.itemRange)
+		}
+		position := ChunkPosition{StartLine: want.line, StartCol: 1, EndLine: want.line, EndCol: want.endCol}
+		if part.Position != position {
+			t.Errorf("Part %d: position = %+v, want %+v", i, part.Position, position)
+		}
+		if part.Metadata["line_start"] != strconv.Itoa(want.line) || part.Metadata["line_end"] != strconv.Itoa(want.line) ||
+			part.Metadata["char_start"] != "1" || part.Metadata["char_end"] != strconv.Itoa(want.endCol) {
+			t.Errorf("Part %d: position metadata does not match the position: %v", i, part.Metadata)
+		}
+	}
+}
+
+func TestListSplit_NeverSplitsInsideItem(t *testing.T) {
+	markdown := "- " + strings.Repeat("long item text ", 10) + "\n- short"
+
+	config := DefaultConfig()
+	config.MaxChunkSize = 50
+	config.OversizeHandling = OversizeModeSplit
+
+	chunker := NewMarkdownChunkerWithConfig(config)
+	chunks, err := chunker.ChunkDocument([]byte(markdown))
+	if err != nil {
+		t.Fatalf("ChunkDocument() error = %v", err)
+	}
+
+	var found bool
+	for _, chunk := range chunks {
+		if chunk.Content == "- short" && chunk.Metadata["item_range"] == "2-2" {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("Expected the short item to form its own part, got %+v", chunks)
+	}
+}
+
+func TestListSplit_InvalidConfig(t *testing.T) {
+	config := DefaultConfig()
+	config.ListMaxSize = -1
+	if err := ValidateConfig(config); err == nil {
+		t.Error("Expected error for negative ListMaxSize")
+	}
+}
@@ -0,0 +1,343 @@
+package markdownchunker
+
+import (
+	"bytes"
+	"html"
+	"regexp"
+	"strings"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/renderer"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
+)
+
+var (
+	// kindMathBlock 行间公式块的节点类型
+	kindMathBlock = ast.NewNodeKind("MathBlock")
+	// kindInlineMath 行内公式的节点类型
+	kindInlineMath = ast.NewNodeKind("InlineMath")
+)
+
+// mathBlock 以 $$ 包围的行间公式块，Lines 为 LaTeX 源码
+type mathBlock struct {
+	ast.BaseBlock
+	closed bool
+}
+
+// newMathBlock 创建行间公式块节点
+func newMathBlock() *mathBlock {
+	return &mathBlock{}
+}
+
+// Kind 返回节点类型
+func (n *mathBlock) Kind() ast.NodeKind {
+	return kindMathBlock
+}
+
+// IsRaw 公式内容不进行行内解析
+func (n *mathBlock) IsRaw() bool {
+	return true
+}
+
+// Dump 输出节点调试信息
+func (n *mathBlock) Dump(source []byte, level int) {
+	ast.DumpHelper(n, source, level, nil, nil)
+}
+
+// inlineMath 以 $ 或 $$ 包围的行内公式，Segment 包含分隔符
+type inlineMath struct {
+	ast.BaseInline
+	Segment text.Segment
+}
+
+// newInlineMath 创建行内公式节点
+func newInlineMath(segment text.Segment) *inlineMath {
+	return &inlineMath{Segment: segment}
+}
+
+// Kind 返回节点类型
+func (n *inlineMath) Kind() ast.NodeKind {
+	return kindInlineMath
+}
+
+// Dump 输出节点调试信息
+func (n *inlineMath) Dump(source []byte, level int) {
+	ast.DumpHelper(n, source, level, map[string]string{
+		"Value": string(n.Segment.Value(source)),
+	}, nil)
+}
+
+// mathBlockParser 解析 $$ ... $$ 行间公式块
+type mathBlockParser struct{}
+
+// Trigger 触发字符
+func (b *mathBlockParser) Trigger() []byte {
+	return []byte{'$'}
+}
+
+// Open 识别以 $$ 开始的行，同一行内闭合的公式直接完成
+// 多行公式必须在下一个空行之前闭合，否则该行按普通段落处理，避免以 $$ 开头的文本吞掉后续内容
+func (b *mathBlockParser) Open(parent ast.Node, reader text.Reader, pc parser.Context) (ast.Node, parser.State) {
+	line, segment := reader.PeekLine()
+	pos := pc.BlockOffset()
+	if pos < 0 || !bytes.HasPrefix(line[pos:], []byte("$$")) {
+		return nil, parser.NoChildren
+	}
+
+	node := newMathBlock()
+	start := segment.Start + pos + 2
+	rest := util.TrimRightSpace(line[pos+2:])
+	if idx := bytes.Index(rest, []byte("$$")); idx >= 0 {
+		// 单行公式：$$ ... $$，闭合之后不能有其他内容
+		if len(bytes.TrimSpace(rest[idx+2:])) > 0 {
+			return nil, parser.NoChildren
+		}
+		node.Lines().Append(text.NewSegment(start, start+idx))
+		node.closed = true
+		return node, parser.NoChildren
+	}
+
+	if !hasMathBlockCloser(reader.Source(), segment.Stop) {
+		return nil, parser.NoChildren
+	}
+
+	if len(bytes.TrimSpace(rest)) > 0 {
+		node.Lines().Append(text.NewSegment(start, segment.Stop))
+	}
+	return node, parser.NoChildren
+}
+
+// hasMathBlockCloser 判断从 offset 所在行开始、下一个空行之前是否有以 $$ 结尾的行
+func hasMathBlockCloser(source []byte, offset int) bool {
+	for offset < len(source) {
+		line := source[offset:]
+		if end := bytes.IndexByte(line, '\n'); end >= 0 {
+			line = line[:end+1]
+		}
+		trimmed := bytes.TrimSpace(line)
+		if len(trimmed) == 0 {
+			return false
+		}
+		if bytes.HasSuffix(trimmed, []byte("$$")) {
+			return true
+		}
+		offset += len(line)
+	}
+	return false
+}
+
+// Continue 收集公式行，直到遇到以 $$ 结尾的行或空行
+func (b *mathBlockParser) Continue(node ast.Node, reader text.Reader, pc parser.Context) parser.State {
+	block := node.(*mathBlock)
+	if block.closed {
+		return parser.Close
+	}
+
+	line, segment := reader.PeekLine()
+	if util.IsBlank(line) {
+		return parser.Close
+	}
+	trimmed := util.TrimRightSpace(line)
+	newline := 0
+	if len(line) > 0 && line[len(line)-1] == '\n' {
+		newline = 1
+	}
+
+	if bytes.HasSuffix(trimmed, []byte("$$")) {
+		body := trimmed[:len(trimmed)-2]
+		if len(bytes.TrimSpace(body)) > 0 {
+			node.Lines().Append(text.NewSegment(segment.Start, segment.Start+len(body)))
+		}
+		block.closed = true
+		reader.Advance(segment.Len() - newline)
+		return parser.Close
+	}
+
+	node.Lines().Append(segment)
+	reader.Advance(segment.Len() - newline)
+	return parser.Continue | parser.NoChildren
+}
+
+// Close 关闭节点
+func (b *mathBlockParser) Close(node ast.Node, reader text.Reader, pc parser.Context) {}
+
+// CanInterruptParagraph 公式块可以打断段落
+func (b *mathBlockParser) CanInterruptParagraph() bool {
+	return true
+}
+
+// CanAcceptIndentedLine 缩进行不作为公式块开始
+func (b *mathBlockParser) CanAcceptIndentedLine() bool {
+	return false
+}
+
+// inlineMathParser 解析 $...$ 和 $$...$$ 行内公式
+type inlineMathParser struct{}
+
+// Trigger 触发字符
+func (s *inlineMathParser) Trigger() []byte {
+	return []byte{'$'}
+}
+
+// Parse 解析行内公式
+// 开始分隔符后和结束分隔符前不能是空白，单个 $ 的结束分隔符后不能紧跟数字，以避免误识别金额
+func (s *inlineMathParser) Parse(parent ast.Node, block text.Reader, pc parser.Context) ast.Node {
+	line, segment := block.PeekLine()
+	opener := 1
+	if len(line) > 1 && line[1] == '$' {
+		opener = 2
+	}
+	if len(line) <= opener || isMathSpace(line[opener]) {
+		return nil
+	}
+
+	for i := opener + 1; i+opener <= len(line); i++ {
+		if line[i] != '$' || line[i-1] == '\\' {
+			continue
+		}
+		if opener == 2 && (i+1 >= len(line) || line[i+1] != '$') {
+			continue
+		}
+		if isMathSpace(line[i-1]) {
+			continue
+		}
+		end := i + opener
+		if opener == 1 && end < len(line) && (line[end] == '$' || (line[end] >= '0' && line[end] <= '9')) {
+			continue
+		}
+
+		block.Advance(end)
+		return newInlineMath(segment.WithStop(segment.Start + end))
+	}
+
+	return nil
+}
+
+// isMathSpace 判断字符是否为空白
+func isMathSpace(ch byte) bool {
+	return ch == ' ' || ch == '\t' || ch == '\n' || ch == '\r'
+}
+
+// mathHTMLRenderer 将公式节点渲染为 HTML，保留 LaTeX 源码
+type mathHTMLRenderer struct{}
+
+// RegisterFuncs 注册渲染函数
+func (r *mathHTMLRenderer) RegisterFuncs(reg renderer.NodeRendererFuncRegisterer) {
+	reg.Register(kindMathBlock, r.renderMathBlock)
+	reg.Register(kindInlineMath, r.renderInlineMath)
+}
+
+func (r *mathHTMLRenderer) renderMathBlock(w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	if entering {
+		_, _ = w.WriteString(`<div class="math display">\[`)
+		_, _ = w.WriteString(html.EscapeString(mathBlockLatex(node.(*mathBlock), source)))
+		_, _ = w.WriteString("\\]</div>\n")
+	}
+	return ast.WalkSkipChildren, nil
+}
+
+func (r *mathHTMLRenderer) renderInlineMath(w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	if entering {
+		value := strings.Trim(string(node.(*inlineMath).Segment.Value(source)), "$")
+		_, _ = w.WriteString(`<span class="math inline">\(`)
+		_, _ = w.WriteString(html.EscapeString(value))
+		_, _ = w.WriteString(`\)</span>`)
+	}
+	return ast.WalkSkipChildren, nil
+}
+
+// mathExtension 为 goldmark 添加 $$ 行间公式和 $ 行内公式支持
+type mathExtension struct{}
+
+// Extend 注册公式解析器和渲染器
+func (e *mathExtension) Extend(m goldmark.Markdown) {
+	m.Parser().AddOptions(
+		parser.WithBlockParsers(util.Prioritized(&mathBlockParser{}, 750)),
+		parser.WithInlineParsers(util.Prioritized(&inlineMathParser{}, 150)),
+	)
+	m.Renderer().AddOptions(
+		renderer.WithNodeRenderers(util.Prioritized(&mathHTMLRenderer{}, 500)),
+	)
+}
+
+// mathBlockLatex 返回公式块的 LaTeX 源码
+func mathBlockLatex(node *mathBlock, source []byte) string {
+	var buf bytes.Buffer
+	for i := 0; i < node.Lines().Len(); i++ {
+		line := node.Lines().At(i)
+		buf.Write(line.Value(source))
+	}
+	return strings.TrimSpace(buf.String())
+}
+
+// countInlineMath 统计节点中行内公式的数量
+func countInlineMath(node ast.Node) int {
+	count := 0
+	ast.Walk(node, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if entering && n.Kind() == kindInlineMath {
+			count++
+		}
+		return ast.WalkContinue, nil
+	})
+	return count
+}
+
+var (
+	// latexFracPattern 匹配不含嵌套括号的 \frac{a}{b}
+	latexFracPattern = regexp.MustCompile(`\\[dt]?frac\{([^{}]*)\}\{([^{}]*)\}`)
+	// latexSqrtPattern 匹配不含嵌套括号的 \sqrt{a}
+	latexSqrtPattern = regexp.MustCompile(`\\sqrt\{([^{}]*)\}`)
+	// latexTextPattern 匹配 \text{...} 等只包装文本的命令
+	latexTextPattern = regexp.MustCompile(`\\(?:text|mathrm|mathbf|mathit|mathsf|mathtt|mathcal|mathbb|operatorname|textbf|textit|boldsymbol)\{([^{}]*)\}`)
+	// latexCommandPattern 匹配其余的命令
+	latexCommandPattern = regexp.MustCompile(`\\([A-Za-z]+)`)
+)
+
+// latexSymbols LaTeX 命令到可读符号的映射
+var latexSymbols = map[string]string{
+	"alpha": "α", "beta": "β", "gamma": "γ", "delta": "δ", "epsilon": "ε", "varepsilon": "ε",
+	"zeta": "ζ", "eta": "η", "theta": "θ", "iota": "ι", "kappa": "κ", "lambda": "λ", "mu": "μ",
+	"nu": "ν", "xi": "ξ", "pi": "π", "rho": "ρ", "sigma": "σ", "tau": "τ", "phi": "φ",
+	"varphi": "φ", "chi": "χ", "psi": "ψ", "omega": "ω",
+	"Gamma": "Γ", "Delta": "Δ", "Theta": "Θ", "Lambda": "Λ", "Xi": "Ξ", "Pi": "Π",
+	"Sigma": "Σ", "Phi": "Φ", "Psi": "Ψ", "Omega": "Ω",
+	"cdot": "·", "times": "×", "div": "÷", "pm": "±", "mp": "∓",
+	"leq": "≤", "le": "≤", "geq": "≥", "ge": "≥", "neq": "≠", "ne": "≠", "approx": "≈",
+	"equiv": "≡", "sim": "∼", "propto": "∝", "infty": "∞", "partial": "∂", "nabla": "∇",
+	"sum": "Σ", "prod": "Π", "int": "∫", "oint": "∮",
+	"in": "∈", "notin": "∉", "subset": "⊂", "subseteq": "⊆", "cup": "∪", "cap": "∩",
+	"forall": "∀", "exists": "∃", "neg": "¬", "land": "∧", "lor": "∨",
+	"to": "→", "rightarrow": "→", "leftarrow": "←", "Rightarrow": "⇒", "Leftrightarrow": "⇔",
+	"ldots": "…", "cdots": "⋯", "dots": "…",
+	"left": "", "right": "", "big": "", "Big": "", "displaystyle": "",
+	"quad": " ", "qquad": " ",
+}
+
+// latexToText 将 LaTeX 源码转换为可读的纯文本，用作公式块的 Text
+func latexToText(latex string) string {
+	result := latex
+	for {
+		replaced := latexFracPattern.ReplaceAllString(result, "($1)/($2)")
+		replaced = latexSqrtPattern.ReplaceAllString(replaced, "√($1)")
+		replaced = latexTextPattern.ReplaceAllString(replaced, "$1")
+		if replaced == result {
+			break
+		}
+		result = replaced
+	}
+
+	// 转义的花括号先用占位符保护，最后再还原
+	result = strings.NewReplacer(`\\`, " ", `\,`, " ", `\;`, " ", `\:`, " ", `\!`, "", `\{`, "\x01", `\}`, "\x02", "&", " ").Replace(result)
+	result = latexCommandPattern.ReplaceAllStringFunc(result, func(command string) string {
+		if symbol, ok := latexSymbols[command[1:]]; ok {
+			return symbol
+		}
+		return command[1:]
+	})
+	result = strings.NewReplacer("{", "", "}", "", "\x01", "{", "\x02", "}").Replace(result)
+
+	return strings.Join(strings.Fields(result), " ")
+}
@@ -0,0 +1,142 @@
+package markdownchunker
+
+import (
+	"strings"
+	"testing"
+)
+
+const mathMarkdown = `# Formulas
+
+The mass-energy relation $E = mc^2$ and the ratio $\frac{a}{b}$ appear inline.
+
+$$
+\frac{-b \pm \sqrt{b^2 - 4ac}}{2a}
+$$
+
+$$\alpha + \beta \leq \gamma$$
+
+It costs $5 and $10 today.`
+
+func TestMath_DisplayBlock(t *testing.T) {
+	chunker := NewMarkdownChunker()
+	chunks, err := chunker.ChunkDocument([]byte(mathMarkdown))
+	if err != nil {
+		t.Fatalf("ChunkDocument() error = %v", err)
+	}
+
+	mathChunks := chunksOfType(chunks, "math")
+	if len(mathChunks) != 2 {
+		t.Fatalf("Expected 2 math chunks, got %d: %+v", len(mathChunks), chunks)
+	}
+
+	block := mathChunks[0]
+	if block.Content != `\frac{-b \pm \sqrt{b^2 - 4ac}}{2a}` {
+		t.Errorf("Expected LaTeX source in content, got %q", block.Content)
+	}
+	if block.Text != "(-b ± √(b^2 - 4ac))/(2a)" {
+		t.Errorf("Unexpected readable text %q", block.Text)
+	}
+	if block.Metadata["math_type"] != "display" || block.Metadata["line_count"] != "1" {
+		t.Errorf("Unexpected metadata %+v", block.Metadata)
+	}
+	if block.Position.StartLine != 6 {
+		t.Errorf("Expected math block to start on line 6, got %d", block.Position.StartLine)
+	}
+
+	single := mathChunks[1]
+	if single.Content != `\alpha + \beta \leq \gamma` {
+		t.Errorf("Expected single-line math content, got %q", single.Content)
+	}
+	if single.Text != "α + β ≤ γ" {
+		t.Errorf("Unexpected readable text %q", single.Text)
+	}
+}
+
+func TestMath_UnclosedDisplayBlock(t *testing.T) {
+	markdown := "# Pricing\n\n$$5 off today only\n\n## Next\n\nSome paragraph.\n\n```go\nfunc main() {}\n```\n"
+
+	chunker := NewMarkdownChunker()
+	chunks, err := chunker.ChunkDocument([]byte(markdown))
+	if err != nil {
+		t.Fatalf("ChunkDocument() error = %v", err)
+	}
+
+	var types []string
+	for _, chunk := range chunks {
+		types = append(types, chunk.Type)
+	}
+	// 未闭合的 $$ 按普通段落处理，不会吞掉后续内容
+	if strings.Join(types, ",") != "heading,paragraph,heading,paragraph,code" {
+		t.Fatalf("Unexpected chunk types %v", types)
+	}
+	if chunks[1].Content != "$$5 off today only" {
+		t.Errorf("Expected unclosed $$ line as paragraph, got %q", chunks[1].Content)
+	}
+}
+
+func TestMath_InlineMathInParagraph(t *testing.T) {
+	chunker := NewMarkdownChunker()
+	chunks, err := chunker.ChunkDocument([]byte(mathMarkdown))
+	if err != nil {
+		t.Fatalf("ChunkDocument() error = %v", err)
+	}
+
+	paragraph := chunks[1]
+	if paragraph.Type != "paragraph" {
+		t.Fatalf("Expected paragraph, got %s", paragraph.Type)
+	}
+	if !strings.Contains(paragraph.Text, `$E = mc^2$`) || !strings.Contains(paragraph.Text, `$\frac{a}{b}$`) {
+		t.Errorf("Expected inline math to be kept verbatim, got %q", paragraph.Text)
+	}
+	if paragraph.Metadata["inline_math_count"] != "2" {
+		t.Errorf("Expected inline_math_count 2, got %q", paragraph.Metadata["inline_math_count"])
+	}
+
+	prices := chunks[len(chunks)-1]
+	if prices.Text != "It costs $5 and $10 today." {
+		t.Errorf("Expected currency amounts to stay plain text, got %q", prices.Text)
+	}
+	if _, ok := prices.Metadata["inline_math_count"]; ok {
+		t.Error("Expected currency amounts not to be counted as inline math")
+	}
+}
+
+func TestMath_DisabledThroughEnabledTypes(t *testing.T) {
+	config := DefaultConfig()
+	config.EnabledTypes = map[string]bool{"heading": true, "paragraph": true, "math": false}
+
+	chunker := NewMarkdownChunkerWithConfig(config)
+	chunks, err := chunker.ChunkDocument([]byte(mathMarkdown))
+	if err != nil {
+		t.Fatalf("ChunkDocument() error = %v", err)
+	}
+
+	for _, chunk := range chunks {
+		if chunk.Type == "math" {
+			t.Errorf("Expected math chunks to be disabled, got %+v", chunk)
+		}
+	}
+	if len(chunks) != 3 {
+		t.Errorf("Expected heading and two paragraphs, got %d chunks", len(chunks))
+	}
+}
+
+func TestLatexToText(t *testing.T) {
+	tests := []struct {
+		latex    string
+		expected string
+	}{
+		{`\frac{a}{b}`, "(a)/(b)"},
+		{`\sqrt{x}`, "√(x)"},
+		{`x \in \mathbb{R}`, "x ∈ R"},
+		{`\text{if } x > 0`, "if x > 0"},
+		{`\sum_{i=1}^{n} i`, "Σ_i=1^n i"},
+		{`\{1, 2\}`, "{1, 2}"},
+	}
+
+	for _, tt := range tests {
+		if got := latexToText(tt.latex); got != tt.expected {
+			t.Errorf("latexToText(%q) = %q, want %q", tt.latex, got, tt.expected)
+		}
+	}
+}
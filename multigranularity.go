@@ -0,0 +1,170 @@
+package markdownchunker
+
+import (
+	"fmt"
+	"strings"
+)
+
+// MultiGranularityResult 一次解析得到的章节级块和元素级块
+// 两组块各自从 0 开始编号，通过 SectionElements 和 ElementSection 相互关联
+type MultiGranularityResult struct {
+	Sections []Chunk `json:"sections"` // 章节级块（层级策略）
+	Elements []Chunk `json:"elements"` // 元素级块（元素级策略）
+
+	SectionElements map[int][]int `json:"section_elements"` // 章节块 ID 到其包含的元素块 ID
+	ElementSection  map[int]int   `json:"element_section"`  // 元素块 ID 到所在章节块 ID
+}
+
+// SectionOf 返回元素块所在的章节块
+func (r *MultiGranularityResult) SectionOf(elementID int) (Chunk, bool) {
+	sectionID, ok := r.ElementSection[elementID]
+	if !ok {
+		return Chunk{}, false
+	}
+	for _, section := range r.Sections {
+		if section.ID == sectionID {
+			return section, true
+		}
+	}
+	return Chunk{}, false
+}
+
+// ElementsOf 返回章节块包含的元素块
+func (r *MultiGranularityResult) ElementsOf(sectionID int) []Chunk {
+	ids := make(map[int]bool, len(r.SectionElements[sectionID]))
+	for _, id := range r.SectionElements[sectionID] {
+		ids[id] = true
+	}
+
+	var elements []Chunk
+	for _, element := range r.Elements {
+		if ids[element.ID] {
+			elements = append(elements, element)
+		}
+	}
+	return elements
+}
+
+// ChunkDocumentMultiGranularity 只解析一次文档，同时生成章节级块和元素级块
+// 当前策略为层级或元素级策略时沿用其配置，否则使用对应策略的默认配置。
+// 元素块的元数据 section_chunk_id 记录所在章节块的 ID，便于索引小块、检索时返回所在章节
+func (c *MarkdownChunker) ChunkDocumentMultiGranularity(content []byte) (*MultiGranularityResult, error) {
+	logCtx := NewLogContext("ChunkDocumentMultiGranularity").WithDocumentInfo(len(content), 0)
+	c.logWithContext("info", "开始多粒度分块", logCtx)
+
+	c.performanceMonitor.Start()
+	defer c.performanceMonitor.Stop()
+	c.performanceMonitor.RecordBytes(int64(len(content)))
+
+	c.errorHandler.ClearErrors()
+
+	result := &MultiGranularityResult{
+		Sections:        []Chunk{},
+		Elements:        []Chunk{},
+		SectionElements: make(map[int][]int),
+		ElementSection:  make(map[int]int),
+	}
+
+	if content == nil {
+		err := NewChunkerError(ErrorTypeInvalidInput, "输入内容不能为空", nil).
+			WithContext("function", "ChunkDocumentMultiGranularity")
+		c.errorHandler.HandleError(err)
+		return nil, err
+	}
+	if len(content) == 0 {
+		return result, nil
+	}
+
+	doc, err := c.parseDocument(content)
+	if err != nil {
+		return nil, err
+	}
+	if doc == nil {
+		return result, nil
+	}
+
+	granularities := []struct {
+		strategy ChunkingStrategy
+		target   *[]Chunk
+	}{
+		{c.granularityStrategy(NewHierarchicalStrategy()), &result.Sections},
+		{c.granularityStrategy(NewElementLevelStrategy()), &result.Elements},
+	}
+	for _, granularity := range granularities {
+		strategyChunks, err := granularity.strategy.ChunkDocument(doc, c.source, c)
+		if err != nil {
+			return nil, NewChunkerError(ErrorTypeStrategyExecutionFailed, "多粒度分块策略执行失败", err).
+				WithContext("function", "ChunkDocumentMultiGranularity").
+				WithContext("strategy", granularity.strategy.GetName())
+		}
+
+		chunks, err := c.postProcessChunks(strategyChunks, len(c.source))
+		if err != nil {
+			return nil, err
+		}
+		*granularity.target = chunks
+	}
+
+	linkGranularities(result)
+	c.chunks = result.Elements
+
+	c.logWithContext("info", "完成多粒度分块", logCtx.
+		WithMetadata("section_chunks", len(result.Sections)).
+		WithMetadata("element_chunks", len(result.Elements)))
+
+	return result, nil
+}
+
+// granularityStrategy 当前策略与默认策略同名时使用当前策略的副本，以沿用其配置
+func (c *MarkdownChunker) granularityStrategy(defaultStrategy ChunkingStrategy) ChunkingStrategy {
+	if c.strategy != nil && c.strategy.GetName() == defaultStrategy.GetName() {
+		return c.strategy.Clone()
+	}
+	return defaultStrategy
+}
+
+// linkGranularities 按章节编号将元素块归入章节块
+// 元素归入章节编号最长且为其前缀的章节块；没有章节编号的元素归入文档开头的前言块。
+// 超大章节被拆分成多块时，元素归入第一块
+func linkGranularities(result *MultiGranularityResult) {
+	for i := range result.Elements {
+		element := &result.Elements[i]
+		number := element.Metadata["section_number"]
+
+		sectionID, bestLength := NoChunkID, -1
+		for _, section := range result.Sections {
+			sectionNumber := section.Metadata["section_number"]
+			if !sectionNumberContains(sectionNumber, number) || len(sectionNumber) <= bestLength {
+				continue
+			}
+			sectionID, bestLength = section.ID, len(sectionNumber)
+		}
+		if sectionID == NoChunkID {
+			continue
+		}
+
+		result.ElementSection[element.ID] = sectionID
+		result.SectionElements[sectionID] = append(result.SectionElements[sectionID], element.ID)
+		if element.Metadata == nil {
+			element.Metadata = make(map[string]string)
+		}
+		element.Metadata["section_chunk_id"] = fmt.Sprintf("%d", sectionID)
+	}
+
+	for i := range result.Sections {
+		section := &result.Sections[i]
+		if section.Metadata == nil {
+			section.Metadata = make(map[string]string)
+		}
+		section.Metadata["element_count"] = fmt.Sprintf("%d", len(result.SectionElements[section.ID]))
+	}
+}
+
+// sectionNumberContains 判断编号为 number 的章节是否位于编号为 section 的章节内
+// 空编号表示文档开头不属于任何章节的部分，只包含同样没有编号的元素
+func sectionNumberContains(section, number string) bool {
+	if section == "" || number == "" {
+		return section == number
+	}
+	return number == section || strings.HasPrefix(number, section+".")
+}
@@ -0,0 +1,110 @@
+package markdownchunker
+
+import (
+	"reflect"
+	"testing"
+)
+
+const multiGranularityMarkdown = `Preamble.
+
+# Install
+
+Intro.
+
+- step one
+- step two
+
+## Linux
+
+Run the script.
+
+# Usage
+
+Call the API.`
+
+func TestChunkDocumentMultiGranularity(t *testing.T) {
+	chunker := NewMarkdownChunker()
+	result, err := chunker.ChunkDocumentMultiGranularity([]byte(multiGranularityMarkdown))
+	if err != nil {
+		t.Fatalf("ChunkDocumentMultiGranularity() error = %v", err)
+	}
+
+	if len(result.Sections) != 3 {
+		t.Fatalf("Expected 3 section chunks, got %d: %+v", len(result.Sections), result.Sections)
+	}
+	if len(result.Elements) != 8 {
+		t.Fatalf("Expected 8 element chunks, got %d", len(result.Elements))
+	}
+
+	expected := map[string][]string{
+		"Preamble.": {"Preamble."},
+		"Install":   {"Install", "Intro.", "step one step two", "Linux", "Run the script."},
+		"Usage":     {"Usage", "Call the API."},
+	}
+	for _, section := range result.Sections {
+		key := section.Text
+		if section.Type == "heading" {
+			key = section.SectionPath[0]
+		}
+		var texts []string
+		for _, element := range result.ElementsOf(section.ID) {
+			texts = append(texts, element.Text)
+		}
+		if !reflect.DeepEqual(texts, expected[key]) {
+			t.Errorf("Section %q: expected elements %v, got %v", key, expected[key], texts)
+		}
+	}
+
+	linux := result.Elements[5]
+	if linux.Text != "Run the script." {
+		t.Fatalf("Expected element 5 to be the Linux paragraph, got %q", linux.Text)
+	}
+	section, ok := result.SectionOf(linux.ID)
+	if !ok || section.Metadata["section_number"] != "1" {
+		t.Errorf("Expected Linux paragraph to belong to the Install section, got %+v", section)
+	}
+	if linux.Metadata["section_chunk_id"] != "1" {
+		t.Errorf("Expected section_chunk_id 1, got %q", linux.Metadata["section_chunk_id"])
+	}
+	if section.Metadata["element_count"] != "5" {
+		t.Errorf("Expected element_count 5, got %q", section.Metadata["element_count"])
+	}
+}
+
+func TestChunkDocumentMultiGranularity_UsesCurrentStrategyConfig(t *testing.T) {
+	chunker := NewMarkdownChunkerWithStrategy("hierarchical")
+	config := HierarchicalConfigWithSize(3, 0, 60)
+	config.Adaptive = true
+	if err := chunker.SetStrategy("hierarchical", config); err != nil {
+		t.Fatalf("SetStrategy() error = %v", err)
+	}
+
+	result, err := chunker.ChunkDocumentMultiGranularity([]byte(multiGranularityMarkdown))
+	if err != nil {
+		t.Fatalf("ChunkDocumentMultiGranularity() error = %v", err)
+	}
+
+	// 自适应模式下超出大小限制的章节按子标题拆分，Linux 段落归入 1.1 章节
+	for _, element := range result.Elements {
+		if element.Text != "Run the script." {
+			continue
+		}
+		section, ok := result.SectionOf(element.ID)
+		if !ok || section.Metadata["section_number"] != "1.1" {
+			t.Errorf("Expected Linux paragraph in section 1.1, got %+v", section)
+		}
+	}
+}
+
+func TestChunkDocumentMultiGranularity_EmptyAndNil(t *testing.T) {
+	chunker := NewMarkdownChunker()
+
+	result, err := chunker.ChunkDocumentMultiGranularity([]byte{})
+	if err != nil || len(result.Sections) != 0 || len(result.Elements) != 0 {
+		t.Errorf("Expected empty result for empty document, got %+v, %v", result, err)
+	}
+
+	if _, err := chunker.ChunkDocumentMultiGranularity(nil); err == nil {
+		t.Error("Expected error for nil content")
+	}
+}
@@ -0,0 +1,161 @@
+package markdownchunker
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// OverlapUnit 块重叠的计量单位
+type OverlapUnit string
+
+const (
+	// OverlapUnitChars 按字符计算重叠（默认）
+	OverlapUnitChars OverlapUnit = "chars"
+	// OverlapUnitTokens 按分词器产生的 token 计算重叠
+	OverlapUnitTokens OverlapUnit = "tokens"
+	// OverlapUnitSentences 按句子计算重叠
+	OverlapUnitSentences OverlapUnit = "sentences"
+)
+
+// isValidOverlapUnit 检查重叠计量单位是否有效，空值表示默认的字符单位
+func isValidOverlapUnit(unit OverlapUnit) bool {
+	switch unit {
+	case "", OverlapUnitChars, OverlapUnitTokens, OverlapUnitSentences:
+		return true
+	}
+	return false
+}
+
+// applyChunkOverlap 为相邻块添加重叠上下文
+// 重叠内容只追加到 Text 中，并分别记录在 overlap_prefix 和 overlap_suffix 元数据中，
+// Content、Position 和 Hash 仍然只描述块自身的内容
+func applyChunkOverlap(chunks []Chunk, config *StrategyConfig, chunker *MarkdownChunker) []Chunk {
+	if config == nil || config.OverlapSize <= 0 || len(chunks) < 2 {
+		return chunks
+	}
+
+	// 先基于原始文本计算重叠，避免重叠内容在相邻块之间级联
+	originalTexts := make([]string, len(chunks))
+	for i := range chunks {
+		originalTexts[i] = chunks[i].Text
+	}
+
+	for i := range chunks {
+		var prefix, suffix string
+		if i > 0 {
+			prefix = overlapTail(originalTexts[i-1], config.OverlapSize, config.OverlapUnit, chunker)
+		}
+		if config.IncludeNextOverlap && i < len(chunks)-1 {
+			suffix = overlapHead(originalTexts[i+1], config.OverlapSize, config.OverlapUnit, chunker)
+		}
+
+		if prefix == "" && suffix == "" {
+			continue
+		}
+
+		if chunks[i].Metadata == nil {
+			chunks[i].Metadata = make(map[string]string)
+		}
+
+		parts := make([]string, 0, 3)
+		if prefix != "" {
+			chunks[i].Metadata["overlap_prefix"] = prefix
+			parts = append(parts, prefix)
+		}
+		parts = append(parts, originalTexts[i])
+		if suffix != "" {
+			chunks[i].Metadata["overlap_suffix"] = suffix
+			parts = append(parts, suffix)
+		}
+
+		chunks[i].Text = strings.Join(parts, " ")
+	}
+
+	return chunks
+}
+
+// overlapTail 返回文本末尾指定大小的重叠内容
+func overlapTail(text string, size int, unit OverlapUnit, chunker *MarkdownChunker) string {
+	text = strings.TrimSpace(text)
+	if text == "" || size <= 0 {
+		return ""
+	}
+
+	switch unit {
+	case OverlapUnitTokens:
+		tokens := overlapTokenizer(chunker).Tokenize(text)
+		if len(tokens) <= size {
+			return text
+		}
+		start := tokenPrefixEnd(text, tokens, len(tokens)-size)
+		for start < len(text) && !utf8.RuneStart(text[start]) {
+			start++
+		}
+		return strings.TrimSpace(text[start:])
+	case OverlapUnitSentences:
+		starts := sentenceStarts(text)
+		if len(starts) <= size {
+			return text
+		}
+		return strings.TrimSpace(text[starts[len(starts)-size]:])
+	default:
+		if utf8.RuneCountInString(text) <= size {
+			return text
+		}
+		start := len(text)
+		for range size {
+			_, runeSize := utf8.DecodeLastRuneInString(text[:start])
+			start -= runeSize
+		}
+		return strings.TrimSpace(text[start:])
+	}
+}
+
+// overlapHead 返回文本开头指定大小的重叠内容
+func overlapHead(text string, size int, unit OverlapUnit, chunker *MarkdownChunker) string {
+	text = strings.TrimSpace(text)
+	if text == "" || size <= 0 {
+		return ""
+	}
+
+	switch unit {
+	case OverlapUnitTokens:
+		tokens := overlapTokenizer(chunker).Tokenize(text)
+		if len(tokens) <= size {
+			return text
+		}
+		return strings.TrimSpace(truncateUTF8(text, tokenPrefixEnd(text, tokens, size)))
+	case OverlapUnitSentences:
+		starts := sentenceStarts(text)
+		if len(starts) <= size {
+			return text
+		}
+		return strings.TrimSpace(text[:starts[size]])
+	default:
+		end := 0
+		for i := 0; i < size && end < len(text); i++ {
+			_, runeSize := utf8.DecodeRuneInString(text[end:])
+			end += runeSize
+		}
+		return strings.TrimSpace(text[:end])
+	}
+}
+
+// sentenceStarts 返回文本中每个句子的起始偏移
+func sentenceStarts(text string) []int {
+	starts := []int{0}
+	for _, cut := range sentenceBoundaries(text) {
+		if cut > starts[len(starts)-1] && cut < len(text) {
+			starts = append(starts, cut)
+		}
+	}
+	return starts
+}
+
+// overlapTokenizer 返回计算重叠使用的分词器
+func overlapTokenizer(chunker *MarkdownChunker) Tokenizer {
+	if chunker == nil {
+		return defaultTokenizer
+	}
+	return chunker.getTokenizer()
+}
@@ -0,0 +1,132 @@
+package markdownchunker
+
+import (
+	"strings"
+	"testing"
+)
+
+const overlapTestMarkdown = `# Overlap Test
+
+First sentence here. Second sentence here. Third sentence ends.
+
+Another paragraph starts. It has two sentences.
+
+Final paragraph.`
+
+func TestOverlap_Disabled(t *testing.T) {
+	chunks := chunkWithStrategyConfig(t, ElementLevelConfig(), overlapTestMarkdown)
+
+	for _, chunk := range chunks {
+		if _, ok := chunk.Metadata["overlap_prefix"]; ok {
+			t.Errorf("Expected no overlap metadata without overlap config, chunk %d", chunk.ID)
+		}
+	}
+}
+
+func TestOverlap_Chars(t *testing.T) {
+	baseline := chunkWithStrategyConfig(t, ElementLevelConfig(), overlapTestMarkdown)
+	chunks := chunkWithStrategyConfig(t, ElementLevelConfigWithOverlap(10, OverlapUnitChars), overlapTestMarkdown)
+
+	if len(chunks) != len(baseline) {
+		t.Fatalf("Expected overlap not to change chunk count, got %d vs %d", len(chunks), len(baseline))
+	}
+
+	if _, ok := chunks[0].Metadata["overlap_prefix"]; ok {
+		t.Error("Expected first chunk to have no overlap prefix")
+	}
+
+	for i := 1; i < len(chunks); i++ {
+		prefix := chunks[i].Metadata["overlap_prefix"]
+		if prefix == "" {
+			t.Fatalf("Expected chunk %d to carry overlap prefix", i)
+		}
+		if len([]rune(prefix)) > 10 {
+			t.Errorf("Expected prefix of at most 10 chars, got %q", prefix)
+		}
+		if !strings.HasSuffix(baseline[i-1].Text, prefix) {
+			t.Errorf("Expected prefix %q to be the tail of the previous chunk %q", prefix, baseline[i-1].Text)
+		}
+		if chunks[i].Text != prefix+" "+baseline[i].Text {
+			t.Errorf("Expected text to start with prefix, got %q", chunks[i].Text)
+		}
+		if chunks[i].Content != baseline[i].Content || chunks[i].Hash != baseline[i].Hash ||
+			chunks[i].Position != baseline[i].Position {
+			t.Errorf("Expected content, hash and position to describe only the primary span for chunk %d", i)
+		}
+		if _, ok := chunks[i].Metadata["overlap_suffix"]; ok {
+			t.Errorf("Expected no suffix when IncludeNextOverlap is false, chunk %d", i)
+		}
+	}
+}
+
+func TestOverlap_SentencesWithNext(t *testing.T) {
+	strategyConfig := ElementLevelConfigWithOverlap(1, OverlapUnitSentences)
+	strategyConfig.IncludeNextOverlap = true
+
+	chunks := chunkWithStrategyConfig(t, strategyConfig, overlapTestMarkdown)
+	if len(chunks) != 4 {
+		t.Fatalf("Expected 4 chunks, got %d", len(chunks))
+	}
+
+	if got := chunks[2].Metadata["overlap_prefix"]; got != "Third sentence ends." {
+		t.Errorf("Expected last sentence of previous chunk as prefix, got %q", got)
+	}
+	if got := chunks[1].Metadata["overlap_suffix"]; got != "Another paragraph starts." {
+		t.Errorf("Expected first sentence of next chunk as suffix, got %q", got)
+	}
+	if _, ok := chunks[3].Metadata["overlap_suffix"]; ok {
+		t.Error("Expected last chunk to have no overlap suffix")
+	}
+
+	// 去掉重叠内容后应恢复原始文本
+	text := chunks[1].Text
+	text = strings.TrimPrefix(text, chunks[1].Metadata["overlap_prefix"]+" ")
+	text = strings.TrimSuffix(text, " "+chunks[1].Metadata["overlap_suffix"])
+	if text != "First sentence here. Second sentence here. Third sentence ends." {
+		t.Errorf("Expected stripped text to equal primary text, got %q", text)
+	}
+}
+
+func TestOverlap_Tokens(t *testing.T) {
+	chunks := chunkWithStrategyConfig(t, ElementLevelConfigWithOverlap(2, OverlapUnitTokens), overlapTestMarkdown)
+
+	if got := chunks[2].Metadata["overlap_prefix"]; got != "sentence ends." {
+		t.Errorf("Expected last 2 tokens as prefix, got %q", got)
+	}
+}
+
+func TestOverlap_Hierarchical(t *testing.T) {
+	markdown := `# One
+
+Alpha content.
+
+# Two
+
+Beta content.`
+
+	chunks := chunkWithStrategyConfig(t, HierarchicalConfigWithOverlap(1, 1, OverlapUnitSentences), markdown)
+	if len(chunks) != 2 {
+		t.Fatalf("Expected 2 hierarchical chunks, got %d", len(chunks))
+	}
+
+	if got := chunks[1].Metadata["overlap_prefix"]; got != "One Alpha content." {
+		t.Errorf("Expected previous section tail as prefix, got %q", got)
+	}
+}
+
+func TestOverlap_InvalidConfig(t *testing.T) {
+	config := ElementLevelConfigWithOverlap(-1, OverlapUnitChars)
+	if err := config.ValidateConfig(); err == nil {
+		t.Error("Expected error for negative overlap size")
+	}
+
+	config = ElementLevelConfigWithOverlap(5, OverlapUnit("paragraphs"))
+	if err := config.ValidateConfig(); err == nil {
+		t.Error("Expected error for invalid overlap unit")
+	}
+
+	clone := ElementLevelConfigWithOverlap(5, OverlapUnitTokens).Clone()
+	if clone.OverlapSize != 5 || clone.OverlapUnit != OverlapUnitTokens {
+		t.Errorf("Expected clone to keep overlap settings, got %+v", clone)
+	}
+}
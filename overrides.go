@@ -0,0 +1,383 @@
+package markdownchunker
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/yuin/goldmark/ast"
+)
+
+// sectionPatternSeparator 章节路径模式中标题之间的分隔符，与 section_path 元数据一致
+const sectionPatternSeparator = ">"
+
+// StrategyOverride 文档局部区域的策略配置覆盖
+// 按章节路径模式和/或顶层内容类型选择区域，区域内的内容改用 Config 指定的策略和配置分块，
+// 其余内容仍使用分块器的当前策略
+type StrategyOverride struct {
+	// SectionPattern 章节路径模式，如 "API Reference" 或 "Guide > Install*"
+	// 标题之间以 > 分隔，每一级按 path.Match 的通配符语法匹配标题文本（不区分大小写）。
+	// 模式匹配章节路径中连续的一段标题，匹配某章节时同样作用于其所有子章节
+	SectionPattern string `json:"section_pattern,omitempty"`
+
+	// ChunkTypes 顶层内容类型，如 code、table；与 SectionPattern 同时设置时两者都需匹配
+	ChunkTypes []string `json:"chunk_types,omitempty"`
+
+	// Config 区域使用的策略配置，Name 指定策略，必须已注册
+	Config *StrategyConfig `json:"config"`
+}
+
+// Validate 验证策略覆盖
+func (o StrategyOverride) Validate() error {
+	if o.SectionPattern == "" && len(o.ChunkTypes) == 0 {
+		return NewChunkerError(ErrorTypeConfigInvalid, "策略覆盖必须指定章节路径模式或内容类型", nil).
+			WithContext("function", "Validate")
+	}
+
+	for _, element := range o.patternElements() {
+		if _, err := path.Match(element, ""); err != nil || element == "" {
+			return NewChunkerError(ErrorTypeConfigInvalid, "无效的章节路径模式", err).
+				WithContext("function", "Validate").
+				WithContext("section_pattern", o.SectionPattern)
+		}
+	}
+
+	// 覆盖按顶层节点的类型匹配，只接受元素块类型
+	for _, chunkType := range o.ChunkTypes {
+		if !elementChunkTypes[chunkType] {
+			return NewChunkerError(ErrorTypeConfigInvalid, "策略覆盖的内容类型无效", nil).
+				WithContext("function", "Validate").
+				WithContext("invalid_type", chunkType)
+		}
+	}
+
+	if o.Config == nil {
+		return NewChunkerError(ErrorTypeConfigInvalid, "策略覆盖的配置不能为空", nil).
+			WithContext("function", "Validate").
+			WithContext("section_pattern", o.SectionPattern)
+	}
+
+	if err := o.Config.ValidateConfig(); err != nil {
+		return NewChunkerError(ErrorTypeConfigInvalid, "策略覆盖的配置无效", err).
+			WithContext("function", "Validate").
+			WithContext("section_pattern", o.SectionPattern).
+			WithContext("strategy", o.Config.Name)
+	}
+
+	return nil
+}
+
+// label 返回覆盖的标识，记录在 strategy_override 元数据中
+func (o StrategyOverride) label() string {
+	if o.SectionPattern != "" {
+		return o.SectionPattern
+	}
+	return strings.Join(o.ChunkTypes, ",")
+}
+
+// patternElements 返回章节路径模式中每一级标题的模式
+func (o StrategyOverride) patternElements() []string {
+	if o.SectionPattern == "" {
+		return nil
+	}
+	elements := strings.Split(o.SectionPattern, sectionPatternSeparator)
+	for i, element := range elements {
+		elements[i] = normalizeSectionPatternText(element)
+	}
+	return elements
+}
+
+// matches 判断顶层节点是否属于覆盖的区域
+func (o StrategyOverride) matches(nodeType string, sectionPath []string) bool {
+	if len(o.ChunkTypes) > 0 {
+		matched := false
+		for _, chunkType := range o.ChunkTypes {
+			if chunkType == nodeType {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+
+	elements := o.patternElements()
+	if len(elements) == 0 {
+		return true
+	}
+
+	// 依次以路径上的每个章节为终点，检查以其结尾的一段标题是否匹配模式
+	for end := len(elements); end <= len(sectionPath); end++ {
+		matched := true
+		for i, element := range elements {
+			heading := normalizeSectionPatternText(sectionPath[end-len(elements)+i])
+			if ok, _ := path.Match(element, heading); !ok {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return true
+		}
+	}
+	return false
+}
+
+// normalizeSectionPatternText 规范化模式或标题文本：去除首尾空白并转为小写，
+// 斜杠替换为普通字符，使通配符可以匹配含斜杠的标题
+func normalizeSectionPatternText(text string) string {
+	return strings.ReplaceAll(strings.ToLower(strings.TrimSpace(text)), "/", "\x00")
+}
+
+// overrideSegment 使用同一策略的连续顶层节点
+type overrideSegment struct {
+	override int // 匹配的覆盖下标，-1 表示使用当前策略
+	nodes    []ast.Node
+	start    int // 区域在源内容中的起始偏移
+	end      int // 区域在源内容中的结束偏移
+}
+
+// executeWithOverrides 按配置的策略覆盖对文档分区域分块
+// 每个区域的顶层节点移入独立的文档节点，并传入只保留该区域内容的源内容（其余字节替换为空格，
+// 保留换行），使偏移和位置与原文档一致。各区域的块按文档顺序合并，ID 重新编号
+func (c *MarkdownChunker) executeWithOverrides(doc ast.Node, source []byte) ([]Chunk, error) {
+	segments := c.overrideSegments(doc, source)
+	if len(segments) == 0 || (len(segments) == 1 && segments[0].override < 0) {
+		return c.executeStrategyWithRecovery(doc, source)
+	}
+
+	// 将顶层节点移入各区域的文档节点，完成后按原顺序移回
+	segmentDocs := make([]ast.Node, len(segments))
+	for i, segment := range segments {
+		segmentDoc := ast.NewDocument()
+		for _, node := range segment.nodes {
+			segmentDoc.AppendChild(segmentDoc, node)
+		}
+		segmentDocs[i] = segmentDoc
+	}
+	defer func() {
+		for _, segment := range segments {
+			for _, node := range segment.nodes {
+				doc.AppendChild(doc, node)
+			}
+		}
+	}()
+
+	var result []Chunk
+	for i, segment := range segments {
+		masked := maskSource(source, segment.start, segment.end)
+
+		chunks, err := c.executeSegment(segment, segmentDocs[i], masked)
+		if err != nil {
+			return nil, err
+		}
+
+		for j := range chunks {
+			if chunks[j].Type == "document" {
+				rescopeDocumentChunk(&chunks[j], source, segment.start, segment.end)
+				c.attachSectionInfo(&chunks[j], segment.nodes[0])
+			}
+			if segment.override >= 0 {
+				if chunks[j].Metadata == nil {
+					chunks[j].Metadata = make(map[string]string)
+				}
+				chunks[j].Metadata["strategy_override"] = c.config.StrategyOverrides[segment.override].label()
+			}
+		}
+		result = append(result, chunks...)
+	}
+
+	for i := range result {
+		result[i].ID = i
+	}
+
+	c.logWithContext("debug", "完成分区域策略分块", NewLogContext("executeWithOverrides").
+		WithDocumentInfo(len(source), len(result)).
+		WithMetadata("segment_count", len(segments)))
+
+	return linkChunks(result), nil
+}
+
+// executeSegment 使用区域对应的策略对区域分块
+func (c *MarkdownChunker) executeSegment(segment overrideSegment, doc ast.Node, source []byte) ([]Chunk, error) {
+	if segment.override < 0 {
+		return c.executeStrategyWithRecovery(doc, source)
+	}
+
+	override := c.config.StrategyOverrides[segment.override]
+	registered, err := c.strategyRegistry.Get(override.Config.Name)
+	if err != nil {
+		return nil, NewChunkerError(ErrorTypeStrategyNotFound, "策略覆盖指定的策略未注册", err).
+			WithContext("function", "executeSegment").
+			WithContext("strategy", override.Config.Name).
+			WithContext("strategy_override", override.label())
+	}
+
+	// 使用注册策略的副本，避免影响分块器的当前策略
+	strategy := registered.Clone()
+	if err := applyStrategyConfig(strategy, override.Config, c.logger); err != nil {
+		return nil, NewChunkerError(ErrorTypeStrategyConfigInvalid, "策略覆盖的配置无效", err).
+			WithContext("function", "executeSegment").
+			WithContext("strategy", override.Config.Name).
+			WithContext("strategy_override", override.label())
+	}
+
+	c.logWithContext("debug", "使用策略覆盖分块", NewLogContext("executeSegment").
+		WithMetadata("strategy", override.Config.Name).
+		WithMetadata("strategy_override", override.label()).
+		WithMetadata("node_count", len(segment.nodes)))
+
+	// 临时将覆盖策略设为当前策略，使区域与整篇文档一样经过输出校验、修复和错误恢复。
+	// 覆盖策略可能与当前策略同名，执行前后都移除该名称的缓存实例，避免两者互相沿用配置
+	current := c.strategy
+	c.strategy = strategy
+	c.removeCachedStrategy(override.Config.Name)
+	defer func() {
+		c.removeCachedStrategy(override.Config.Name)
+		// 错误恢复可能已将当前策略切换为默认策略
+		c.invalidateStrategyCache()
+		c.strategy = current
+	}()
+
+	return c.executeStrategyWithRecovery(doc, source)
+}
+
+// overrideSegments 为每个顶层节点匹配策略覆盖，并将使用同一覆盖的连续节点合并为区域
+func (c *MarkdownChunker) overrideSegments(doc ast.Node, source []byte) []overrideSegment {
+	var segments []overrideSegment
+	for node := doc.FirstChild(); node != nil; node = node.NextSibling() {
+		override := -1
+		sectionPath := c.sections[node].path()
+		for i, candidate := range c.config.StrategyOverrides {
+			if candidate.matches(getNodeType(node), sectionPath) {
+				override = i
+				break
+			}
+		}
+
+		if len(segments) > 0 && segments[len(segments)-1].override == override {
+			last := &segments[len(segments)-1]
+			last.nodes = append(last.nodes, node)
+			continue
+		}
+		segments = append(segments, overrideSegment{override: override, nodes: []ast.Node{node}})
+	}
+
+	groups := make([][]ast.Node, len(segments))
+	for i, segment := range segments {
+		groups[i] = segment.nodes
+	}
+	for i, bounds := range nodeGroupBounds(groups, source) {
+		segments[i].start, segments[i].end = bounds[0], bounds[1]
+	}
+
+	return segments
+}
+
+// nodeGroupBounds 返回每组连续顶层节点在源内容中的区域 [start, end)
+// 区域从组内第一个节点所在行开始，到下一组开始为止，第一组包括文档开头的空行、链接定义等内容；
+// 组为空或无法确定起始位置时区域为空
+func nodeGroupBounds(groups [][]ast.Node, source []byte) [][2]int {
+	bounds := make([][2]int, len(groups))
+	for i := len(groups) - 1; i >= 0; i-- {
+		end := len(source)
+		if i+1 < len(groups) {
+			end = bounds[i+1][0]
+		}
+
+		start := -1
+		for _, node := range groups[i] {
+			if start = nodeLineStart(node, source); start >= 0 {
+				break
+			}
+		}
+		if start < 0 || start > end {
+			start = end
+		}
+		bounds[i] = [2]int{start, end}
+	}
+	if len(bounds) > 0 {
+		bounds[0][0] = 0
+	}
+	return bounds
+}
+
+// nodeLineStart 返回节点第一行的起始偏移，代码块和公式块包括起始围栏行，无法确定时返回 -1
+func nodeLineStart(node ast.Node, source []byte) int {
+	start := -1
+	ast.Walk(node, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			return ast.WalkContinue, nil
+		}
+		offset := -1
+		switch v := n.(type) {
+		case *ast.Text:
+			offset = v.Segment.Start
+		case *ast.FencedCodeBlock:
+			if v.Info != nil {
+				offset = v.Info.Segment.Start
+			}
+		}
+		if n.Type() == ast.TypeBlock && n.Lines().Len() > 0 {
+			if lineStart := n.Lines().At(0).Start; offset < 0 || lineStart < offset {
+				offset = lineStart
+			}
+		}
+		if offset >= 0 && (start < 0 || offset < start) {
+			start = offset
+		}
+		return ast.WalkContinue, nil
+	})
+	if start < 0 {
+		return -1
+	}
+
+	start = min(start, len(source))
+	for start > 0 && source[start-1] != '\n' {
+		start--
+	}
+
+	// 代码块和公式块的行不包括起始围栏
+	switch node.(type) {
+	case *ast.FencedCodeBlock, *mathBlock:
+		if start > 0 {
+			previous := start - 1
+			for previous > 0 && source[previous-1] != '\n' {
+				previous--
+			}
+			if fenceMarker(strings.TrimSpace(string(source[previous:start]))) != "" {
+				start = previous
+			}
+		}
+	}
+	return start
+}
+
+// maskSource 返回只保留 [start, end) 区域内容的源内容副本，区域外的字节替换为空格，换行保留
+func maskSource(source []byte, start, end int) []byte {
+	masked := make([]byte, len(source))
+	for i, b := range source {
+		if (i < start || i >= end) && b != '\n' {
+			b = ' '
+		}
+		masked[i] = b
+	}
+	return masked
+}
+
+// rescopeDocumentChunk 将基于整个源内容生成的块（如文档级策略的块）的内容、位置和哈希限定到区域
+func rescopeDocumentChunk(chunk *Chunk, source []byte, start, end int) {
+	region := source[start:end]
+	trimmedStart := start + len(region) - len(strings.TrimLeft(string(region), " \t\r\n"))
+	trimmedEnd := start + len(strings.TrimRight(string(region), " \t\r\n"))
+	if trimmedEnd < trimmedStart {
+		trimmedEnd = trimmedStart
+	}
+
+	content := string(source[trimmedStart:trimmedEnd])
+	chunk.Content = content
+	chunk.Position = spanPosition(lineStartOffsets(source), textSpan{start: trimmedStart, end: trimmedEnd})
+	chunk.Hash = fmt.Sprintf("%x", sha256.Sum256([]byte(content)))
+}
@@ -0,0 +1,292 @@
+package markdownchunker
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/yuin/goldmark/ast"
+)
+
+const overridesMarkdown = "# Guide\n\nIntro text here.\n\n## Install\n\nRun the installer.\n\n" +
+	"## API Reference\n\nThe API.\n\n```go\nfunc A() {}\n```\n\n### Client\n\nClient docs.\n\n" +
+	"## Changelog\n\n### v1.1.0\n\n- Added X\n\n### v1.0.0\n\n- Initial\n"
+
+func overrideChunks(t *testing.T, strategy *StrategyConfig, overrides ...StrategyOverride) []Chunk {
+	t.Helper()
+
+	config := DefaultConfig()
+	config.ChunkingStrategy = strategy
+	config.StrategyOverrides = overrides
+	chunker := NewMarkdownChunkerWithConfig(config)
+	chunks, err := chunker.ChunkDocument([]byte(overridesMarkdown))
+	if err != nil {
+		t.Fatalf("ChunkDocument() error = %v", err)
+	}
+	return chunks
+}
+
+func assertChunkOrder(t *testing.T, chunks []Chunk) {
+	t.Helper()
+
+	offset := 0
+	for i, chunk := range chunks {
+		if chunk.ID != i {
+			t.Errorf("Chunk %d: expected ID %d, got %d", i, i, chunk.ID)
+		}
+		if i > 0 && chunk.PrevID != chunks[i-1].ID {
+			t.Errorf("Chunk %d: expected PrevID %d, got %d", i, chunks[i-1].ID, chunk.PrevID)
+		}
+		if i < len(chunks)-1 && chunk.NextID != chunks[i+1].ID {
+			t.Errorf("Chunk %d: expected NextID %d, got %d", i, chunks[i+1].ID, chunk.NextID)
+		}
+
+		// 块按文档顺序排列
+		firstLine := strings.SplitN(chunk.Content, "\n", 2)[0]
+		index := strings.Index(overridesMarkdown[offset:], firstLine)
+		if index < 0 {
+			t.Errorf("Chunk %d out of document order: %q", i, chunk.Content)
+			continue
+		}
+		offset += index + len(firstLine)
+	}
+}
+
+func TestStrategyOverrides_SectionPatterns(t *testing.T) {
+	chunks := overrideChunks(t, HierarchicalConfig(0),
+		StrategyOverride{SectionPattern: "API Reference", Config: ElementLevelConfig()},
+		StrategyOverride{SectionPattern: "changelog", Config: ElementLevelConfigWithPacking(1000, 3)},
+	)
+	assertChunkOrder(t, chunks)
+
+	var contents []string
+	for _, chunk := range chunks {
+		contents = append(contents, chunk.Content)
+		path := chunk.Metadata["section_path"]
+
+		switch {
+		case strings.HasPrefix(path, "Guide > API Reference"):
+			if chunk.Metadata["strategy"] != "element-level" || chunk.Metadata["strategy_override"] != "API Reference" {
+				t.Errorf("Expected element-level override in API Reference, got %v", chunk.Metadata)
+			}
+		case strings.HasPrefix(path, "Guide > Changelog"):
+			if chunk.Metadata["strategy"] != "element-level" || chunk.Metadata["strategy_override"] != "changelog" {
+				t.Errorf("Expected element-level override in Changelog, got %v", chunk.Metadata)
+			}
+		default:
+			if chunk.Metadata["strategy"] != "hierarchical" || chunk.Metadata["strategy_override"] != "" {
+				t.Errorf("Expected hierarchical strategy for narrative sections, got %v", chunk.Metadata)
+			}
+		}
+	}
+
+	expected := []string{
+		"# Guide\n\nIntro text here.\n\n## Install\n\nRun the installer.",
+		"## API Reference",
+		"The API.",
+		"```go\nfunc A() {}\n```",
+		"### Client",
+		"Client docs.",
+		"## Changelog",
+		"### v1.1.0\n\n- Added X",
+		"### v1.0.0\n\n- Initial",
+	}
+	if strings.Join(contents, "|") != strings.Join(expected, "|") {
+		t.Errorf("Unexpected chunks:\n%q\nexpected:\n%q", contents, expected)
+	}
+}
+
+func TestStrategyOverrides_PatternMatching(t *testing.T) {
+	tests := []struct {
+		pattern string
+		path    []string
+		want    bool
+	}{
+		{"API Reference", []string{"Guide", "API Reference"}, true},
+		{"API Reference", []string{"Guide", "API Reference", "Client"}, true},
+		{"Guide > API*", []string{"Guide", "API Reference", "Client"}, true},
+		{"guide>api reference>client", []string{"Guide", "API Reference", "Client"}, true},
+		{"Client", []string{"Guide", "API Reference"}, false},
+		{"Guide > Client", []string{"Guide", "API Reference", "Client"}, false},
+		{"Input*", []string{"Input/Output"}, true},
+		{"API Reference", nil, false},
+	}
+
+	for _, tt := range tests {
+		override := StrategyOverride{SectionPattern: tt.pattern, Config: ElementLevelConfig()}
+		if got := override.matches("paragraph", tt.path); got != tt.want {
+			t.Errorf("matches(%q, %q) = %v, want %v", tt.pattern, tt.path, got, tt.want)
+		}
+	}
+
+	override := StrategyOverride{SectionPattern: "API*", ChunkTypes: []string{"code"}, Config: ElementLevelConfig()}
+	if !override.matches("code", []string{"API Reference"}) || override.matches("paragraph", []string{"API Reference"}) ||
+		override.matches("code", []string{"Changelog"}) {
+		t.Error("Expected both section pattern and chunk types to be required")
+	}
+}
+
+func TestStrategyOverrides_ChunkTypesAndSourceStrategies(t *testing.T) {
+	chunks := overrideChunks(t, FixedSizeConfig(40, 0),
+		StrategyOverride{SectionPattern: "Guide > Install", Config: DocumentLevelConfig()},
+		StrategyOverride{ChunkTypes: []string{"code", "list"}, Config: ElementLevelConfig()},
+	)
+	assertChunkOrder(t, chunks)
+
+	var foundDocument, foundCode bool
+	for _, chunk := range chunks {
+		switch chunk.Type {
+		case "document":
+			foundDocument = true
+			// 文档级块只包含覆盖区域的内容，位置和哈希随之调整
+			if chunk.Content != "## Install\n\nRun the installer." {
+				t.Errorf("Expected document chunk limited to the region, got %q", chunk.Content)
+			}
+			if chunk.Position != (ChunkPosition{StartLine: 5, StartCol: 1, EndLine: 7, EndCol: 19}) {
+				t.Errorf("Unexpected document chunk position %+v", chunk.Position)
+			}
+			if chunk.Metadata["section_path"] != "Guide > Install" || chunk.Metadata["strategy_override"] != "Guide > Install" {
+				t.Errorf("Unexpected document chunk metadata %v", chunk.Metadata)
+			}
+		case "code", "list":
+			foundCode = foundCode || chunk.Type == "code"
+			if chunk.Metadata["strategy_override"] != "code,list" {
+				t.Errorf("Expected type override for %s chunk, got %v", chunk.Type, chunk.Metadata)
+			}
+		case "window":
+			// 固定大小窗口不会跨入其他策略的区域
+			if strings.Contains(chunk.Content, "```") || strings.Contains(chunk.Content, "- ") ||
+				strings.Contains(chunk.Content, "Run the installer") {
+				t.Errorf("Window crosses into an override region: %q", chunk.Content)
+			}
+		default:
+			t.Errorf("Unexpected chunk type %s", chunk.Type)
+		}
+	}
+	if !foundDocument || !foundCode {
+		t.Errorf("Expected document and code override chunks, document=%v code=%v", foundDocument, foundCode)
+	}
+}
+
+func TestStrategyOverrides_NoMatchAndRepeatedCalls(t *testing.T) {
+	config := DefaultConfig()
+	config.StrategyOverrides = []StrategyOverride{{SectionPattern: "Missing", Config: DocumentLevelConfig()}}
+	chunker := NewMarkdownChunkerWithConfig(config)
+	baseline, err := NewMarkdownChunker().ChunkDocument([]byte(overridesMarkdown))
+	if err != nil {
+		t.Fatalf("ChunkDocument() error = %v", err)
+	}
+
+	chunks, err := chunker.ChunkDocument([]byte(overridesMarkdown))
+	if err != nil {
+		t.Fatalf("ChunkDocument() error = %v", err)
+	}
+	if len(chunks) != len(baseline) {
+		t.Errorf("Expected unmatched overrides to leave chunking unchanged, got %d chunks, want %d", len(chunks), len(baseline))
+	}
+
+	// 同一分块器重复分块时结果一致
+	config.StrategyOverrides = []StrategyOverride{{SectionPattern: "Changelog", Config: DocumentLevelConfig()}}
+	chunker = NewMarkdownChunkerWithConfig(config)
+	first, _ := chunker.ChunkDocument([]byte(overridesMarkdown))
+	second, _ := chunker.ChunkDocument([]byte(overridesMarkdown))
+	if len(first) != len(second) || first[len(first)-1].Content != second[len(second)-1].Content {
+		t.Errorf("Expected repeated calls to produce the same chunks")
+	}
+	if last := first[len(first)-1]; last.Type != "document" || !strings.HasPrefix(last.Content, "## Changelog") {
+		t.Errorf("Expected changelog as a single document chunk, got %+v", last)
+	}
+}
+
+func TestStrategyOverrides_Validation(t *testing.T) {
+	tests := []struct {
+		name     string
+		override StrategyOverride
+	}{
+		{"no selector", StrategyOverride{Config: ElementLevelConfig()}},
+		{"nil config", StrategyOverride{SectionPattern: "API"}},
+		{"bad pattern", StrategyOverride{SectionPattern: "API[", Config: ElementLevelConfig()}},
+		{"empty pattern element", StrategyOverride{SectionPattern: "Guide > ", Config: ElementLevelConfig()}},
+		{"bad type", StrategyOverride{ChunkTypes: []string{"widget"}, Config: ElementLevelConfig()}},
+		{"bad config", StrategyOverride{SectionPattern: "API", Config: &StrategyConfig{Name: "element-level", MinChunkSize: -1}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := DefaultConfig()
+			config.StrategyOverrides = []StrategyOverride{tt.override}
+			if err := ValidateConfig(config); err == nil {
+				t.Error("Expected validation error")
+			}
+		})
+	}
+
+	config := DefaultConfig()
+	config.ErrorHandling = ErrorModeStrict
+	config.StrategyOverrides = []StrategyOverride{{SectionPattern: "API Reference", Config: DefaultStrategyConfig("missing")}}
+	if _, err := NewMarkdownChunkerWithConfig(config).ChunkDocument([]byte(overridesMarkdown)); err == nil {
+		t.Error("Expected error for unregistered override strategy")
+	}
+}
+
+// failingStrategy 总是执行失败的测试策略
+type failingStrategy struct{}
+
+func (s *failingStrategy) GetName() string { return "failing" }
+
+func (s *failingStrategy) GetDescription() string { return "总是执行失败的测试策略" }
+
+func (s *failingStrategy) ChunkDocument(doc ast.Node, source []byte, chunker *MarkdownChunker) ([]Chunk, error) {
+	return nil, NewChunkerError(ErrorTypeStrategyExecutionFailed, "模拟策略执行失败", nil)
+}
+
+func (s *failingStrategy) ValidateConfig(config *StrategyConfig) error { return nil }
+
+func (s *failingStrategy) Clone() ChunkingStrategy { return &failingStrategy{} }
+
+func TestStrategyOverrides_RecoverFromFailure(t *testing.T) {
+	config := DefaultConfig()
+	config.ChunkingStrategy = HierarchicalConfig(2)
+	config.ErrorHandling = ErrorModePermissive
+	config.StrategyOverrides = []StrategyOverride{{SectionPattern: "API Reference", Config: DefaultStrategyConfig("failing")}}
+
+	chunker := NewMarkdownChunkerWithConfig(config)
+	if err := chunker.RegisterStrategy(&failingStrategy{}); err != nil {
+		t.Fatalf("RegisterStrategy() error = %v", err)
+	}
+	chunks, err := chunker.ChunkDocument([]byte(overridesMarkdown))
+	if err != nil {
+		t.Fatalf("Expected the failing override to recover like the current strategy, got %v", err)
+	}
+
+	overridden := 0
+	for _, chunk := range chunks {
+		if chunk.Metadata["strategy_override"] == "" {
+			continue
+		}
+		overridden++
+		if chunk.Metadata["strategy"] == "hierarchical" {
+			t.Errorf("Expected the override region to fall back to the default strategy, got %v", chunk.Metadata)
+		}
+	}
+	if overridden == 0 {
+		t.Fatal("Expected chunks for the override region")
+	}
+	assertChunkOrder(t, chunks)
+
+	if name, _ := chunker.GetCurrentStrategy(); name != "hierarchical" {
+		t.Errorf("Expected the current strategy to be restored, got %s", name)
+	}
+	again, err := chunker.ChunkDocument([]byte(overridesMarkdown))
+	if err != nil || len(again) != len(chunks) {
+		t.Errorf("Expected repeated calls to produce the same chunks, got %d chunks and error %v", len(again), err)
+	}
+
+	config.ErrorHandling = ErrorModeStrict
+	chunker = NewMarkdownChunkerWithConfig(config)
+	if err := chunker.RegisterStrategy(&failingStrategy{}); err != nil {
+		t.Fatalf("RegisterStrategy() error = %v", err)
+	}
+	if _, err := chunker.ChunkDocument([]byte(overridesMarkdown)); err == nil {
+		t.Error("Expected the failing override to return an error in strict mode")
+	}
+}
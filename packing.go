@@ -0,0 +1,148 @@
+package markdownchunker
+
+import (
+	"fmt"
+	"strings"
+)
+
+// packTargetSize 返回打包的目标大小，未配置时使用 MinChunkSize
+func packTargetSize(config *StrategyConfig) int {
+	if config == nil {
+		return 0
+	}
+	if config.PackTargetSize > 0 {
+		return config.PackTargetSize
+	}
+	return config.MinChunkSize
+}
+
+// isPackBoundary 判断块是否为打包边界（达到配置层级的标题）
+func isPackBoundary(chunk *Chunk, boundaryLevel int) bool {
+	if chunk.Type != "heading" {
+		return false
+	}
+	if boundaryLevel <= 0 {
+		return true
+	}
+	return chunk.Level <= boundaryLevel
+}
+
+// packSmallChunks 将相邻的小块贪心合并，直到达到目标大小
+// 边界层级的标题总是开始一个新的打包组，因此打包不会跨越这些标题
+func packSmallChunks(chunks []Chunk, config *StrategyConfig, chunker *MarkdownChunker) []Chunk {
+	target := packTargetSize(config)
+	if config == nil || !config.PackSmallChunks || target <= 0 || len(chunks) < 2 {
+		return chunks
+	}
+
+	var result []Chunk
+	var group []Chunk
+	groupSize := 0
+
+	flush := func() {
+		if len(group) == 0 {
+			return
+		}
+		if len(group) == 1 {
+			result = append(result, group[0])
+		} else {
+			result = append(result, mergePackedChunks(group, chunker))
+		}
+		group = nil
+		groupSize = 0
+	}
+
+	for _, chunk := range chunks {
+		if isPackBoundary(&chunk, config.PackBoundaryLevel) {
+			flush()
+		} else if len(group) > 0 {
+			combinedSize := measureChunkSize(chunker, packedContent(group)+"\n\n"+chunk.Content)
+			if groupSize >= target || combinedSize > target {
+				flush()
+			}
+		}
+
+		group = append(group, chunk)
+		groupSize = measureChunkSize(chunker, packedContent(group))
+	}
+	flush()
+
+	// 重新编号
+	for i := range result {
+		result[i].ID = i
+	}
+
+	return result
+}
+
+// packedContent 返回打包组合并后的 markdown 内容
+func packedContent(group []Chunk) string {
+	parts := make([]string, len(group))
+	for i, chunk := range group {
+		parts[i] = chunk.Content
+	}
+	return strings.Join(parts, "\n\n")
+}
+
+// mergePackedChunks 将打包组合并为一个块
+func mergePackedChunks(group []Chunk, chunker *MarkdownChunker) Chunk {
+	content := packedContent(group)
+
+	var textParts []string
+	var mergedTypes []string
+	links := make([]Link, 0)
+	images := make([]Image, 0)
+	for _, chunk := range group {
+		if strings.TrimSpace(chunk.Text) != "" {
+			textParts = append(textParts, chunk.Text)
+		}
+		mergedTypes = append(mergedTypes, chunk.Type)
+		links = append(links, chunk.Links...)
+		images = append(images, chunk.Images...)
+	}
+	text := strings.Join(textParts, " ")
+
+	first := group[0]
+	last := group[len(group)-1]
+	position := ChunkPosition{
+		StartLine: first.Position.StartLine,
+		StartCol:  first.Position.StartCol,
+		EndLine:   last.Position.EndLine,
+		EndCol:    last.Position.EndCol,
+	}
+
+	metadata := map[string]string{
+		"merged_types": strings.Join(mergedTypes, ","),
+		"merged_count": fmt.Sprintf("%d", len(group)),
+		"line_start":   fmt.Sprintf("%d", position.StartLine),
+		"line_end":     fmt.Sprintf("%d", position.EndLine),
+		"word_count":   fmt.Sprintf("%d", len(strings.Fields(text))),
+	}
+	if strategy, ok := first.Metadata["strategy"]; ok {
+		metadata["strategy"] = strategy
+	}
+
+	level := 0
+	if first.Type == "heading" {
+		level = first.Level
+		metadata["heading_level"] = fmt.Sprintf("%d", first.Level)
+	}
+
+	packed := Chunk{
+		ID:       first.ID,
+		Type:     "packed",
+		Content:  content,
+		Text:     text,
+		Level:    level,
+		Metadata: metadata,
+		Position: position,
+		Links:    links,
+		Images:   images,
+		Hash:     chunker.calculateContentHash(content),
+	}
+
+	// 打包块归属于第一个块所在的章节
+	copySectionInfo(&packed, first)
+
+	return packed
+}
@@ -0,0 +1,151 @@
+package markdownchunker
+
+import (
+	"strings"
+	"testing"
+)
+
+const packingTestMarkdown = `# Title
+
+Short para with [a link](https://example.com).
+
+- one item
+
+Short para two.
+
+## Sub
+
+Tiny ![img](pic.png).
+
+Another tiny.`
+
+func TestPacking_RespectsHeadingBoundary(t *testing.T) {
+	chunks := chunkWithStrategyConfig(t, ElementLevelConfigWithPacking(200, 2), packingTestMarkdown)
+
+	if len(chunks) != 2 {
+		t.Fatalf("Expected 2 packed chunks, got %d: %+v", len(chunks), chunks)
+	}
+
+	first := chunks[0]
+	if first.Type != "packed" {
+		t.Errorf("Expected packed type, got %s", first.Type)
+	}
+	if got := first.Metadata["merged_types"]; got != "heading,paragraph,list,paragraph" {
+		t.Errorf("Unexpected merged_types %q", got)
+	}
+	if first.Position.StartLine != 1 || first.Position.EndLine != 7 {
+		t.Errorf("Expected position to span lines 1-7, got %+v", first.Position)
+	}
+	if len(first.Links) != 1 || first.Links[0].URL != "https://example.com" {
+		t.Errorf("Expected combined links, got %+v", first.Links)
+	}
+	if strings.Contains(first.Content, "## Sub") {
+		t.Error("Expected packing not to cross the level-2 heading")
+	}
+
+	second := chunks[1]
+	if !strings.HasPrefix(second.Content, "## Sub") {
+		t.Errorf("Expected second group to start at the boundary heading, got %q", second.Content)
+	}
+	if len(second.Images) != 1 || second.Images[0].URL != "pic.png" {
+		t.Errorf("Expected combined images, got %+v", second.Images)
+	}
+	if second.ID != 1 {
+		t.Errorf("Expected packed chunks to be renumbered, got ID %d", second.ID)
+	}
+}
+
+func TestPacking_DeeperHeadingsArePacked(t *testing.T) {
+	chunks := chunkWithStrategyConfig(t, ElementLevelConfigWithPacking(500, 1), packingTestMarkdown)
+
+	if len(chunks) != 1 {
+		t.Fatalf("Expected level-2 heading to be packed when boundary is level 1, got %d chunks", len(chunks))
+	}
+	if chunks[0].Metadata["merged_count"] != "7" {
+		t.Errorf("Expected 7 merged chunks, got %s", chunks[0].Metadata["merged_count"])
+	}
+}
+
+func TestPacking_TargetSizeLimitsGroups(t *testing.T) {
+	markdown := strings.Repeat("Small paragraph text.\n\n", 6)
+
+	chunks := chunkWithStrategyConfig(t, ElementLevelConfigWithPacking(50, 0), markdown)
+
+	if len(chunks) != 3 {
+		t.Fatalf("Expected 3 groups of two paragraphs, got %d", len(chunks))
+	}
+	for _, chunk := range chunks {
+		if len(chunk.Content) > 50 {
+			t.Errorf("Expected packed chunk within target size, got %d", len(chunk.Content))
+		}
+	}
+}
+
+func TestPacking_KeepsChunksBelowMinSize(t *testing.T) {
+	strategyConfig := ElementLevelConfigWithSize(40, 0)
+	strategyConfig.PackSmallChunks = true
+
+	withoutPacking := chunkWithStrategyConfig(t, ElementLevelConfigWithSize(40, 0), packingTestMarkdown)
+	withPacking := chunkWithStrategyConfig(t, strategyConfig, packingTestMarkdown)
+
+	countWords := func(chunks []Chunk) int {
+		total := 0
+		for _, chunk := range chunks {
+			total += len(strings.Fields(chunk.Text))
+		}
+		return total
+	}
+
+	if countWords(withPacking) <= countWords(withoutPacking) {
+		t.Error("Expected packing to keep content that MinChunkSize would drop")
+	}
+	if !strings.Contains(withPacking[len(withPacking)-1].Text, "Another tiny.") {
+		t.Error("Expected the last short paragraph to be kept")
+	}
+}
+
+func TestPacking_InvalidConfig(t *testing.T) {
+	config := ElementLevelConfigWithPacking(100, 7)
+	if err := config.ValidateConfig(); err == nil {
+		t.Error("Expected error for invalid boundary level")
+	}
+
+	config = ElementLevelConfigWithPacking(100, 0)
+	config.MaxChunkSize = 50
+	if err := config.ValidateConfig(); err == nil {
+		t.Error("Expected error when target size exceeds max chunk size")
+	}
+}
+
+func TestPacking_EnabledTypes(t *testing.T) {
+	config := DefaultConfig()
+	config.ChunkingStrategy = ElementLevelConfigWithPacking(200, 0)
+	config.EnabledTypes = map[string]bool{"paragraph": true, "heading": true}
+
+	chunks, err := NewMarkdownChunkerWithConfig(config).ChunkDocument([]byte(packingTestMarkdown))
+	if err != nil {
+		t.Fatalf("ChunkDocument() error = %v", err)
+	}
+	if len(chunks) == 0 {
+		t.Fatal("Expected packed chunks to be kept when EnabledTypes does not mention packed")
+	}
+	for _, chunk := range chunks {
+		if chunk.Type != "packed" {
+			t.Errorf("Expected packed chunk, got %s", chunk.Type)
+		}
+	}
+
+	config.EnabledTypes = map[string]bool{"packed": true}
+	if err := ValidateConfig(config); err != nil {
+		t.Fatalf("Expected packed to be a valid enabled type, got %v", err)
+	}
+
+	config.EnabledTypes = map[string]bool{"paragraph": true, "packed": false}
+	chunks, err = NewMarkdownChunkerWithConfig(config).ChunkDocument([]byte(packingTestMarkdown))
+	if err != nil {
+		t.Fatalf("ChunkDocument() error = %v", err)
+	}
+	if len(chunks) != 0 {
+		t.Errorf("Expected explicitly disabled packed chunks to be dropped, got %d", len(chunks))
+	}
+}
@@ -0,0 +1,431 @@
+package markdownchunker
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/text"
+)
+
+// 问题的识别方式，记录在 question_style 元数据中
+const (
+	QuestionStyleHeading = "heading" // 以问号结尾或带 Q: 前缀的标题
+	QuestionStyleBold    = "bold"    // 段落或列表项开头以问号结尾的粗体文本
+	QuestionStylePrefix  = "prefix"  // 以 Q: 或 Question: 开头的段落或列表项
+)
+
+var (
+	// questionPrefixPattern 纯文本中的问题前缀，如 Q:、Question：
+	questionPrefixPattern = regexp.MustCompile(`(?i)^\s*(?:q|question)\s*[:：]\s*`)
+	// answerPrefixPattern markdown 中的回答前缀，如 A:、**A:**、Answer：，前缀可以带粗体标记
+	answerPrefixPattern = regexp.MustCompile(`(?i)^\s*(\*\*|__)?\s*(?:a|answer)\s*[:：]\s*(\*\*|__)?\s*`)
+	// boldLeadPattern 以粗体开头的 markdown
+	boldLeadPattern = regexp.MustCompile(`^(\*\*|__)(.+?)(\*\*|__)`)
+	// listItemMarkerPattern 顶层列表项的标记
+	listItemMarkerPattern = regexp.MustCompile(`^(?:[-*+]|\d+[.)])(?:\s+|$)`)
+)
+
+// qaPair 问题及回答它的内容
+type qaPair struct {
+	question string   // 问题的纯文本
+	style    string   // 问题的识别方式
+	level    int      // 标题问题的层级，其余为 0
+	contents []string // 组成问答对的 markdown 片段
+	answers  []string // 回答的纯文本片段
+	first    Chunk    // 问题所在的元素
+	last     Chunk    // 最后一个回答元素
+	links    []Link
+	images   []Image
+}
+
+// QAStrategy 问答对分块策略
+// 识别以问号结尾或带 Q: 前缀的标题、开头为粗体问题的段落和列表项，以及 Q:/A: 段落，
+// 将每个问题与回答它的内容合并为一个块。块的 Text 以问题开头，便于与用户问题的嵌入匹配；
+// 不属于任何问答对的元素按元素级处理保留
+type QAStrategy struct {
+	config *StrategyConfig
+}
+
+// NewQAStrategy 创建新的问答对分块策略
+func NewQAStrategy() *QAStrategy {
+	return &QAStrategy{
+		config: QAConfig(),
+	}
+}
+
+// NewQAStrategyWithConfig 使用指定配置创建问答对分块策略
+func NewQAStrategyWithConfig(config *StrategyConfig) *QAStrategy {
+	if config == nil {
+		config = QAConfig()
+	}
+	return &QAStrategy{
+		config: config,
+	}
+}
+
+// GetName 返回策略名称
+func (s *QAStrategy) GetName() string {
+	return "qa"
+}
+
+// GetDescription 返回策略描述
+func (s *QAStrategy) GetDescription() string {
+	return "识别 FAQ 中的问题并与回答内容配对，每个问答对作为一个块"
+}
+
+// ChunkDocument 将文档中的问题与回答配对并分块
+func (s *QAStrategy) ChunkDocument(doc ast.Node, source []byte, chunker *MarkdownChunker) ([]Chunk, error) {
+	if doc == nil {
+		return nil, NewChunkerError(ErrorTypeStrategyExecutionFailed, "文档节点不能为空", nil).
+			WithContext("strategy", s.GetName()).
+			WithContext("function", "ChunkDocument")
+	}
+
+	if chunker == nil {
+		return nil, NewChunkerError(ErrorTypeStrategyExecutionFailed, "分块器实例不能为空", nil).
+			WithContext("strategy", s.GetName()).
+			WithContext("function", "ChunkDocument")
+	}
+
+	// 1. 使用元素级处理获取元素
+	elements, err := NewElementLevelStrategy().ChunkDocument(doc, source, chunker)
+	if err != nil {
+		return nil, NewChunkerError(ErrorTypeStrategyExecutionFailed, "获取元素失败", err).
+			WithContext("strategy", s.GetName()).
+			WithContext("function", "ChunkDocument")
+	}
+
+	// 2. 按文档顺序配对问题和回答
+	var chunks []Chunk
+	var current *qaPair
+	flush := func() {
+		if current != nil {
+			chunks = append(chunks, s.createPairChunk(current, len(chunks), chunker))
+			current = nil
+		}
+	}
+	keep := func(element Chunk) {
+		element.ID = len(chunks)
+		element.Metadata["strategy"] = s.GetName()
+		chunks = append(chunks, element)
+	}
+
+	for _, element := range elements {
+		switch element.Type {
+		case "heading":
+			if question, ok := headingQuestion(element.Text); ok {
+				flush()
+				current = newQAPair(question, QuestionStyleHeading, element)
+				current.level = element.Level
+				current.addElement(element, false)
+				continue
+			}
+			// 标题问题的回答包括更深层级的非问题子标题，其余标题结束当前问答对
+			if current != nil && current.level > 0 && element.Level > current.level {
+				current.addElement(element, true)
+				continue
+			}
+			flush()
+			keep(element)
+
+		case "paragraph":
+			if question, answer, style, ok := parseQuestion(element.Content, chunker); ok {
+				flush()
+				current = newQAPair(question, style, element)
+				current.contents = append(current.contents, element.Content)
+				current.links = append(current.links, element.Links...)
+				current.images = append(current.images, element.Images...)
+				if answer != "" {
+					current.answers = append(current.answers, qaPlainText(answer, chunker))
+				}
+				continue
+			}
+			if current == nil {
+				keep(element)
+				continue
+			}
+			if answer, ok := stripAnswerPrefix(element.Content); ok {
+				current.contents = append(current.contents, element.Content)
+				current.answers = append(current.answers, qaPlainText(answer, chunker))
+				current.links = append(current.links, element.Links...)
+				current.images = append(current.images, element.Images...)
+				current.last = element
+				continue
+			}
+			current.addElement(element, true)
+
+		case "list":
+			items := splitListItems(chunker.listMarkdown(element))
+			if len(items) > 0 {
+				if _, _, _, ok := parseQuestion(items[0], chunker); ok {
+					flush()
+					for _, item := range items {
+						question, answer, style, ok := parseQuestion(item, chunker)
+						if ok {
+							flush()
+							current = newQAPair(question, style, element)
+						} else {
+							// 不是问题的列表项补充到上一个问题的回答中
+							answer = item
+						}
+						current.contents = append(current.contents, item)
+						if answer != "" {
+							current.answers = append(current.answers, qaPlainText(answer, chunker))
+						}
+						itemDoc := chunker.md.Parser().Parse(text.NewReader([]byte(item)))
+						links, images := (&DocumentLevelStrategy{}).extractLinksAndImages(itemDoc, []byte(item))
+						current.links = append(current.links, links...)
+						current.images = append(current.images, images...)
+					}
+					flush()
+					continue
+				}
+			}
+			if current == nil {
+				keep(element)
+				continue
+			}
+			current.addElement(element, true)
+
+		default:
+			if current == nil {
+				keep(element)
+				continue
+			}
+			current.addElement(element, true)
+		}
+	}
+	flush()
+
+	return linkChunks(chunks), nil
+}
+
+// newQAPair 创建以 element 中的问题开始的问答对
+func newQAPair(question, style string, element Chunk) *qaPair {
+	return &qaPair{
+		question: question,
+		style:    style,
+		first:    element,
+		last:     element,
+		links:    make([]Link, 0),
+		images:   make([]Image, 0),
+	}
+}
+
+// addElement 将整个元素加入问答对，answer 表示元素属于回答
+func (p *qaPair) addElement(element Chunk, answer bool) {
+	p.contents = append(p.contents, element.Content)
+	if answer && strings.TrimSpace(element.Text) != "" {
+		p.answers = append(p.answers, element.Text)
+	}
+	p.links = append(p.links, element.Links...)
+	p.images = append(p.images, element.Images...)
+	p.last = element
+}
+
+// createPairChunk 创建问答对块
+func (s *QAStrategy) createPairChunk(pair *qaPair, id int, chunker *MarkdownChunker) Chunk {
+	content := strings.Join(pair.contents, "\n\n")
+	answer := strings.Join(pair.answers, "\n")
+
+	// 问题在前，使嵌入与用户的提问方式接近
+	plainText := "Question: " + pair.question
+	if answer != "" {
+		plainText += "\nAnswer: " + answer
+	}
+
+	position := ChunkPosition{
+		StartLine: pair.first.Position.StartLine,
+		StartCol:  pair.first.Position.StartCol,
+		EndLine:   pair.last.Position.EndLine,
+		EndCol:    pair.last.Position.EndCol,
+	}
+
+	metadata := map[string]string{
+		"strategy":       s.GetName(),
+		"question":       pair.question,
+		"answer":         answer,
+		"question_style": pair.style,
+		"line_start":     fmt.Sprintf("%d", position.StartLine),
+		"line_end":       fmt.Sprintf("%d", position.EndLine),
+		"word_count":     fmt.Sprintf("%d", len(strings.Fields(pair.question+" "+answer))),
+	}
+	if pair.level > 0 {
+		metadata["heading_level"] = fmt.Sprintf("%d", pair.level)
+	}
+
+	chunk := Chunk{
+		ID:       id,
+		Type:     "qa",
+		Content:  content,
+		Text:     plainText,
+		Level:    pair.level,
+		Metadata: metadata,
+		Position: position,
+		Links:    pair.links,
+		Images:   pair.images,
+		Hash:     chunker.calculateContentHash(content),
+	}
+
+	// 问答对归属于问题所在的章节
+	copySectionInfo(&chunk, pair.first)
+
+	return chunk
+}
+
+// headingQuestion 判断标题是否为问题，返回去掉 Q: 前缀的问题文本
+func headingQuestion(heading string) (string, bool) {
+	heading = strings.TrimSpace(heading)
+	if loc := questionPrefixPattern.FindStringIndex(heading); loc != nil {
+		return strings.TrimSpace(heading[loc[1]:]), true
+	}
+	return heading, isQuestionText(heading)
+}
+
+// isQuestionText 判断纯文本是否以问号结尾
+func isQuestionText(text string) bool {
+	text = strings.TrimSpace(text)
+	return strings.HasSuffix(text, "?") || strings.HasSuffix(text, "？")
+}
+
+// parseQuestion 识别段落或列表项 markdown 开头的问题
+// 返回问题的纯文本、同一段落中回答部分的 markdown 和问题的识别方式
+func parseQuestion(markdown string, chunker *MarkdownChunker) (string, string, string, bool) {
+	markdown = strings.TrimSpace(markdown)
+
+	// 开头的粗体问题，如 **How do I reset my password?** 或 **Q: ...**
+	if match := boldLeadPattern.FindStringSubmatch(markdown); match != nil && match[1] == match[3] {
+		question := qaPlainText(match[2], chunker)
+		prefixed := questionPrefixPattern.MatchString(question)
+		question = strings.TrimSpace(questionPrefixPattern.ReplaceAllString(question, ""))
+		// 只有前缀是粗体（如 **Q:** ...）时按 Q: 前缀处理
+		if question != "" && (prefixed || isQuestionText(question)) {
+			answer := strings.TrimSpace(markdown[len(match[0]):])
+			if stripped, ok := stripAnswerPrefix(answer); ok {
+				answer = stripped
+			}
+			style := QuestionStyleBold
+			if prefixed {
+				style = QuestionStylePrefix
+			}
+			return question, answer, style, true
+		}
+	}
+
+	// Q: 前缀，回答可以在同一段落中以 A: 开头的行给出
+	lines := strings.Split(markdown, "\n")
+	if !questionPrefixPattern.MatchString(qaPlainText(lines[0], chunker)) {
+		return "", "", "", false
+	}
+	questionLines := len(lines)
+	answer := ""
+	for i := 1; i < len(lines); i++ {
+		if stripped, ok := stripAnswerPrefix(strings.Join(lines[i:], "\n")); ok {
+			questionLines = i
+			answer = stripped
+			break
+		}
+	}
+	question := qaPlainText(strings.Join(lines[:questionLines], "\n"), chunker)
+	question = questionPrefixPattern.ReplaceAllString(question, "")
+	return strings.TrimSpace(question), answer, QuestionStylePrefix, true
+}
+
+// stripAnswerPrefix 去掉 markdown 开头的回答前缀，前缀中未闭合的粗体标记与结尾的标记一起去掉
+func stripAnswerPrefix(markdown string) (string, bool) {
+	match := answerPrefixPattern.FindStringSubmatch(markdown)
+	if match == nil {
+		return markdown, false
+	}
+
+	answer := strings.TrimSpace(markdown[len(match[0]):])
+	if match[1] != "" && match[2] == "" {
+		answer = strings.TrimSpace(strings.TrimSuffix(answer, match[1]))
+	}
+	return answer, true
+}
+
+// splitListItems 将列表的 markdown 拆分为顶层列表项，去掉列表标记和续行的缩进
+func splitListItems(markdown string) []string {
+	var items []string
+	var current []string
+	indent := 0
+	for _, line := range strings.Split(markdown, "\n") {
+		if marker := listItemMarkerPattern.FindString(line); marker != "" {
+			if current != nil {
+				items = append(items, strings.TrimSpace(strings.Join(current, "\n")))
+			}
+			indent = len(marker)
+			current = []string{line[len(marker):]}
+			continue
+		}
+		if current == nil {
+			continue
+		}
+		// 去掉续行中不超过列表标记宽度的缩进
+		trimmed := strings.TrimLeft(line, " ")
+		if len(line)-len(trimmed) > indent {
+			trimmed = line[indent:]
+		}
+		current = append(current, trimmed)
+	}
+	if current != nil {
+		items = append(items, strings.TrimSpace(strings.Join(current, "\n")))
+	}
+	return items
+}
+
+// qaPlainText 返回 markdown 片段的纯文本
+func qaPlainText(markdown string, chunker *MarkdownChunker) string {
+	source := []byte(markdown)
+	doc := chunker.md.Parser().Parse(text.NewReader(source))
+	return strings.TrimSpace(windowPlainText(doc, source))
+}
+
+// ValidateConfig 验证策略特定的配置
+func (s *QAStrategy) ValidateConfig(config *StrategyConfig) error {
+	if config == nil {
+		return NewChunkerError(ErrorTypeStrategyConfigInvalid, "策略配置不能为空", nil).
+			WithContext("strategy", s.GetName()).
+			WithContext("function", "ValidateConfig")
+	}
+
+	return config.ValidateConfig()
+}
+
+// Clone 创建策略的副本（用于并发安全）
+func (s *QAStrategy) Clone() ChunkingStrategy {
+	var configClone *StrategyConfig
+	if s.config != nil {
+		configClone = s.config.Clone()
+	}
+
+	return &QAStrategy{
+		config: configClone,
+	}
+}
+
+// GetConfig 获取策略配置
+func (s *QAStrategy) GetConfig() *StrategyConfig {
+	if s.config == nil {
+		return nil
+	}
+	return s.config.Clone()
+}
+
+// SetConfig 设置策略配置
+func (s *QAStrategy) SetConfig(config *StrategyConfig) error {
+	if config == nil {
+		s.config = QAConfig()
+		return nil
+	}
+
+	if err := s.ValidateConfig(config); err != nil {
+		return err
+	}
+
+	s.config = config.Clone()
+	return nil
+}
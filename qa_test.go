@@ -0,0 +1,170 @@
+package markdownchunker
+
+import (
+	"strings"
+	"testing"
+)
+
+const qaMarkdown = `# Support FAQ
+
+Welcome to the support page.
+
+## Accounts
+
+### How do I reset my password?
+
+Open **Settings** and click [Reset](https://example.com/reset).
+
+#### Details
+
+It takes a minute.
+
+### Billing
+
+**Can I get a refund?** Yes, within 30 days.
+
+More refund details.
+
+Q: Do you ship abroad?
+A: Yes, to most countries.
+
+**Q:** What payment methods are accepted?
+
+**A:** Cards and PayPal.
+
+## Quick answers
+
+- **Is there a free plan?** Yes, up to 3 users.
+- **Can I export data?**
+  Use the export button.
+- Contact sales for more.
+`
+
+func TestQAStrategy_Pairs(t *testing.T) {
+	chunks := chunkWithStrategyConfig(t, QAConfig(), qaMarkdown)
+
+	type pair struct{ question, answer, style string }
+	var pairs []pair
+	for i, chunk := range chunks {
+		if chunk.ID != i || chunk.Metadata["strategy"] != "qa" {
+			t.Errorf("Chunk %d: unexpected ID %d or strategy %q", i, chunk.ID, chunk.Metadata["strategy"])
+		}
+		if chunk.Type == "qa" {
+			pairs = append(pairs, pair{chunk.Metadata["question"], chunk.Metadata["answer"], chunk.Metadata["question_style"]})
+		}
+	}
+
+	expected := []pair{
+		{"How do I reset my password?", "Open Settings and click Reset.\nDetails\nIt takes a minute.", QuestionStyleHeading},
+		{"Can I get a refund?", "Yes, within 30 days.\nMore refund details.", QuestionStyleBold},
+		{"Do you ship abroad?", "Yes, to most countries.", QuestionStylePrefix},
+		{"What payment methods are accepted?", "Cards and PayPal.", QuestionStylePrefix},
+		{"Is there a free plan?", "Yes, up to 3 users.", QuestionStyleBold},
+		{"Can I export data?", "Use the export button.\nContact sales for more.", QuestionStyleBold},
+	}
+	if len(pairs) != len(expected) {
+		t.Fatalf("Expected %d pairs, got %d: %+v", len(expected), len(pairs), pairs)
+	}
+	for i, want := range expected {
+		if pairs[i] != want {
+			t.Errorf("Pair %d = %+v, want %+v", i, pairs[i], want)
+		}
+	}
+}
+
+func TestQAStrategy_ChunkFields(t *testing.T) {
+	chunks := chunkWithStrategyConfig(t, QAConfig(), qaMarkdown)
+
+	var heading *Chunk
+	for i := range chunks {
+		if chunks[i].Metadata["question_style"] == QuestionStyleHeading {
+			heading = &chunks[i]
+		}
+	}
+	if heading == nil {
+		t.Fatal("Expected a heading question pair")
+	}
+
+	// Text 以问题开头，便于与用户的提问匹配
+	if !strings.HasPrefix(heading.Text, "Question: How do I reset my password?\nAnswer: Open Settings") {
+		t.Errorf("Unexpected pair text %q", heading.Text)
+	}
+	if !strings.HasPrefix(heading.Content, "### How do I reset my password?") || !strings.Contains(heading.Content, "#### Details") {
+		t.Errorf("Expected the answer to include deeper subsections, got %q", heading.Content)
+	}
+	if heading.Level != 3 || heading.Metadata["heading_level"] != "3" {
+		t.Errorf("Expected heading level 3, got %d %v", heading.Level, heading.Metadata)
+	}
+	if heading.Position.StartLine != 7 || heading.Position.EndLine != 13 {
+		t.Errorf("Unexpected pair position %+v", heading.Position)
+	}
+	if len(heading.Links) != 1 || heading.Links[0].URL != "https://example.com/reset" {
+		t.Errorf("Expected answer link, got %+v", heading.Links)
+	}
+	if heading.Metadata["section_path"] != "Support FAQ > Accounts > How do I reset my password?" {
+		t.Errorf("Unexpected section path %q", heading.Metadata["section_path"])
+	}
+}
+
+func TestQAStrategy_NonQuestionContent(t *testing.T) {
+	chunks := chunkWithStrategyConfig(t, QAConfig(), qaMarkdown)
+
+	// 问题之前的内容和非问题标题按元素保留
+	if chunks[0].Type != "heading" || chunks[1].Type != "paragraph" || chunks[1].Content != "Welcome to the support page." {
+		t.Errorf("Expected intro elements to be kept, got %q %q", chunks[0].Content, chunks[1].Content)
+	}
+
+	for _, chunk := range chunks {
+		if chunk.Type == "heading" && chunk.Text == "Billing" {
+			return
+		}
+	}
+	t.Error("Expected non-question heading to end the previous pair and be kept")
+}
+
+func TestQAStrategy_NoQuestions(t *testing.T) {
+	chunks := chunkWithStrategyConfig(t, QAConfig(), "# Title\n\nJust text.\n\n- a\n- b\n")
+	if len(chunks) != 3 {
+		t.Fatalf("Expected element chunks, got %d", len(chunks))
+	}
+	for _, chunk := range chunks {
+		if chunk.Type == "qa" || chunk.Metadata["strategy"] != "qa" {
+			t.Errorf("Unexpected chunk %+v", chunk)
+		}
+	}
+}
+
+func TestQAStrategy_QuestionDetection(t *testing.T) {
+	tests := []struct {
+		markdown string
+		question string
+		answer   string
+		ok       bool
+	}{
+		{"**How do I log in?** Use SSO.", "How do I log in?", "Use SSO.", true},
+		{"__Why?__", "Why?", "", true},
+		{"**Q: Where?** Here.", "Where?", "Here.", true},
+		{"Question: How much?\nAnswer: Free.", "How much?", "Free.", true},
+		{"q：多少钱？\nA：免费。", "多少钱？", "免费。", true},
+		{"**Important** read this?", "", "", false},
+		{"Is this a question?", "", "", false},
+	}
+
+	chunker := NewMarkdownChunker()
+	for _, tt := range tests {
+		question, answer, _, ok := parseQuestion(tt.markdown, chunker)
+		if ok != tt.ok || question != tt.question || answer != tt.answer {
+			t.Errorf("parseQuestion(%q) = %q, %q, %v, want %q, %q, %v", tt.markdown, question, answer, ok, tt.question, tt.answer, tt.ok)
+		}
+	}
+
+	for heading, want := range map[string]bool{"How do I X?": true, "Q: Setup": true, "为什么？": true, "Setup": false} {
+		if _, ok := headingQuestion(heading); ok != want {
+			t.Errorf("headingQuestion(%q) = %v, want %v", heading, ok, want)
+		}
+	}
+}
+
+func TestQAStrategy_EnabledTypes(t *testing.T) {
+	assertKeptWithEnabledTypes(t, QAConfig(), qaMarkdown, "qa")
+}
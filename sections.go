@@ -0,0 +1,239 @@
+package markdownchunker
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
+)
+
+// headingIDAttribute 标题锚点属性名
+var headingIDAttribute = []byte("id")
+
+// sectionMetadataKeys 章节相关的元数据键，合并或拆分块时随块一起保留
+var sectionMetadataKeys = []string{"anchor", "section_path", "section_levels", "section_number", "section_depth"}
+
+// sectionHeading 章节路径上的一个标题
+type sectionHeading struct {
+	level   int
+	text    string
+	ordinal int // 在同一上级章节中的序号（从1开始）
+}
+
+// sectionInfo 顶层节点所在章节的信息
+type sectionInfo struct {
+	anchor   string           // 最近的标题锚点，标题节点为自身锚点
+	headings []sectionHeading // 从最外层到最近标题的路径，标题节点包括自身
+}
+
+// path 返回章节路径上的标题文本
+func (s sectionInfo) path() []string {
+	path := make([]string, len(s.headings))
+	for i, heading := range s.headings {
+		path[i] = heading.text
+	}
+	return path
+}
+
+// levels 返回章节路径上的标题层级，以逗号连接
+func (s sectionInfo) levels() string {
+	levels := make([]string, len(s.headings))
+	for i, heading := range s.headings {
+		levels[i] = fmt.Sprintf("%d", heading.level)
+	}
+	return strings.Join(levels, ",")
+}
+
+// number 返回章节编号，如 2.3.1，跳过的标题层级不占编号位
+func (s sectionInfo) number() string {
+	numbers := make([]string, len(s.headings))
+	for i, heading := range s.headings {
+		numbers[i] = fmt.Sprintf("%d", heading.ordinal)
+	}
+	return strings.Join(numbers, ".")
+}
+
+// githubSlug 按 GitHub 的规则生成标题锚点：
+// 转为小写，去除标点和符号（保留 - 和 _），空格替换为 -，保留中日韩等非 ASCII 文字
+func githubSlug(heading string) string {
+	var buf strings.Builder
+	for _, r := range strings.ToLower(strings.TrimSpace(heading)) {
+		switch {
+		case r == ' ':
+			buf.WriteByte('-')
+		case r == '-' || r == '_':
+			buf.WriteRune(r)
+		case unicode.IsLetter(r) || unicode.IsNumber(r) || unicode.IsMark(r):
+			buf.WriteRune(r)
+		}
+	}
+	return buf.String()
+}
+
+// headingPlainText 返回标题的纯文本，链接只保留文字，代码保留内容
+func headingPlainText(heading *ast.Heading, source []byte) string {
+	var buf strings.Builder
+	ast.Walk(heading, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			return ast.WalkContinue, nil
+		}
+		switch node := n.(type) {
+		case *ast.Text:
+			buf.Write(node.Segment.Value(source))
+			if node.SoftLineBreak() || node.HardLineBreak() {
+				buf.WriteByte(' ')
+			}
+		case *ast.String:
+			buf.Write(node.Value)
+		case *ast.AutoLink:
+			buf.Write(node.Label(source))
+		case *inlineMath:
+			buf.Write(node.Segment.Value(source))
+		case *ast.Image:
+			// GitHub 生成锚点时忽略图片
+			return ast.WalkSkipChildren, nil
+		}
+		return ast.WalkContinue, nil
+	})
+	return buf.String()
+}
+
+// headingIDTransformer 按 GitHub 规则为没有显式 {#id} 的标题生成锚点
+// 重复的锚点依次追加 -1、-2，显式锚点预先占用，避免自动锚点与之冲突
+type headingIDTransformer struct{}
+
+// Transform 为文档中的所有标题设置 id 属性
+func (t *headingIDTransformer) Transform(doc *ast.Document, reader text.Reader, pc parser.Context) {
+	source := reader.Source()
+
+	var headings []*ast.Heading
+	used := make(map[string]bool)
+	ast.Walk(doc, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		heading, ok := n.(*ast.Heading)
+		if !entering || !ok {
+			return ast.WalkContinue, nil
+		}
+		if id, ok := heading.AttributeString("id"); ok {
+			if value, ok := id.([]byte); ok {
+				used[string(value)] = true
+			}
+			return ast.WalkSkipChildren, nil
+		}
+		headings = append(headings, heading)
+		return ast.WalkSkipChildren, nil
+	})
+
+	for _, heading := range headings {
+		base := githubSlug(headingPlainText(heading, source))
+		id := base
+		for count := 1; used[id]; count++ {
+			id = fmt.Sprintf("%s-%d", base, count)
+		}
+		used[id] = true
+		heading.SetAttribute(headingIDAttribute, []byte(id))
+	}
+}
+
+// headingIDExtension 为 goldmark 添加 GitHub 风格的标题锚点
+type headingIDExtension struct{}
+
+// Extend 注册标题锚点转换器
+func (e *headingIDExtension) Extend(m goldmark.Markdown) {
+	m.Parser().AddOptions(
+		parser.WithASTTransformers(util.Prioritized(&headingIDTransformer{}, 600)),
+	)
+}
+
+// headingAnchor 返回标题的锚点
+func headingAnchor(heading *ast.Heading) string {
+	if id, ok := heading.AttributeString("id"); ok {
+		if value, ok := id.([]byte); ok {
+			return string(value)
+		}
+	}
+	return ""
+}
+
+// collectSections 按文档顺序记录每个顶层节点所在章节的信息
+func (c *MarkdownChunker) collectSections(doc ast.Node) map[ast.Node]sectionInfo {
+	sections := make(map[ast.Node]sectionInfo)
+	current := sectionInfo{}
+	// counts[i] 为路径上第 i 个标题之下（counts[0] 为文档顶层）已出现的子标题数
+	counts := []int{0}
+
+	for child := doc.FirstChild(); child != nil; child = child.NextSibling() {
+		if heading, ok := child.(*ast.Heading); ok {
+			// 弹出层级不高于当前标题的祖先
+			depth := len(current.headings)
+			for depth > 0 && current.headings[depth-1].level >= heading.Level {
+				depth--
+			}
+			counts = counts[:depth+1]
+			counts[depth]++
+
+			headings := make([]sectionHeading, depth, depth+1)
+			copy(headings, current.headings[:depth])
+			headings = append(headings, sectionHeading{
+				level:   heading.Level,
+				text:    c.getNodeText(heading),
+				ordinal: counts[depth],
+			})
+			counts = append(counts, 0)
+
+			current = sectionInfo{anchor: headingAnchor(heading), headings: headings}
+		}
+		sections[child] = current
+	}
+
+	return sections
+}
+
+// attachSectionInfo 将节点所在章节的锚点、路径和编号写入块
+// 标题块的章节为其自身，其余块为最近的上级标题所在章节
+func (c *MarkdownChunker) attachSectionInfo(chunk *Chunk, node ast.Node) {
+	if len(c.sections) == 0 {
+		return
+	}
+
+	// 非顶层节点使用其所在顶层节点的章节信息
+	for node.Parent() != nil && node.Parent().Kind() != ast.KindDocument {
+		node = node.Parent()
+	}
+	section, ok := c.sections[node]
+	if !ok || len(section.headings) == 0 {
+		return
+	}
+
+	if chunk.Metadata == nil {
+		chunk.Metadata = make(map[string]string)
+	}
+	if section.anchor != "" {
+		chunk.Metadata["anchor"] = section.anchor
+	}
+	chunk.SectionPath = section.path()
+	chunk.Metadata["section_path"] = strings.Join(chunk.SectionPath, " > ")
+	chunk.Metadata["section_levels"] = section.levels()
+	chunk.Metadata["section_number"] = section.number()
+	chunk.Metadata["section_depth"] = fmt.Sprintf("%d", len(section.headings))
+}
+
+// copySectionInfo 将源块的章节信息复制到合并后的块
+func copySectionInfo(dst *Chunk, src Chunk) {
+	if len(src.SectionPath) == 0 && src.Metadata["anchor"] == "" {
+		return
+	}
+	if dst.Metadata == nil {
+		dst.Metadata = make(map[string]string)
+	}
+	for _, key := range sectionMetadataKeys {
+		if value, ok := src.Metadata[key]; ok {
+			dst.Metadata[key] = value
+		}
+	}
+	dst.SectionPath = src.SectionPath
+}
@@ -0,0 +1,239 @@
+package markdownchunker
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGitHubSlug(t *testing.T) {
+	tests := []struct {
+		heading  string
+		expected string
+	}{
+		{"Install Guide", "install-guide"},
+		{"What's new in v2.0?", "whats-new-in-v20"},
+		{"snake_case and kebab-case", "snake_case-and-kebab-case"},
+		{"A  B", "a--b"},
+		{"安装指南（中文）", "安装指南中文"},
+		{"Émoji 🚀 support", "émoji--support"},
+	}
+
+	for _, tt := range tests {
+		if got := githubSlug(tt.heading); got != tt.expected {
+			t.Errorf("githubSlug(%q) = %q, want %q", tt.heading, got, tt.expected)
+		}
+	}
+}
+
+func TestSectionAnchors(t *testing.T) {
+	markdown := `Preamble without a section.
+
+# Getting Started
+
+Intro paragraph.
+
+## Linux
+
+- apt install tool
+
+## Linux
+
+## See [the docs](https://example.com) and ` + "`cli`" + `
+
+## Custom Heading {#setup}
+
+Setup steps.
+
+## setup`
+
+	chunker := NewMarkdownChunker()
+	chunks, err := chunker.ChunkDocument([]byte(markdown))
+	if err != nil {
+		t.Fatalf("ChunkDocument() error = %v", err)
+	}
+
+	expected := []struct {
+		chunkType string
+		text      string
+		anchor    string
+	}{
+		{"paragraph", "Preamble without a section.", ""},
+		{"heading", "Getting Started", "getting-started"},
+		{"paragraph", "Intro paragraph.", "getting-started"},
+		{"heading", "Linux", "linux"},
+		{"list", "apt install tool", "linux"},
+		{"heading", "Linux", "linux-1"},
+		{"heading", "See the docs and cli", "see-the-docs-and-cli"},
+		{"heading", "Custom Heading", "setup"},
+		{"paragraph", "Setup steps.", "setup"},
+		{"heading", "setup", "setup-1"},
+	}
+
+	if len(chunks) != len(expected) {
+		t.Fatalf("Expected %d chunks, got %d: %+v", len(expected), len(chunks), chunks)
+	}
+	for i, want := range expected {
+		chunk := chunks[i]
+		if chunk.Type != want.chunkType || chunk.Text != want.text {
+			t.Errorf("Chunk %d: expected %s %q, got %s %q", i, want.chunkType, want.text, chunk.Type, chunk.Text)
+		}
+		anchor, ok := chunk.Metadata["anchor"]
+		if want.anchor == "" {
+			if ok {
+				t.Errorf("Chunk %d: expected no anchor, got %q", i, anchor)
+			}
+			continue
+		}
+		if anchor != want.anchor {
+			t.Errorf("Chunk %d: expected anchor %q, got %q", i, want.anchor, anchor)
+		}
+	}
+}
+
+func TestSectionAnchors_HierarchicalStrategy(t *testing.T) {
+	markdown := `# Guide
+
+Intro.
+
+## Install
+
+Run the installer.`
+
+	chunker := NewMarkdownChunkerWithStrategy("hierarchical")
+	chunks, err := chunker.ChunkDocument([]byte(markdown))
+	if err != nil {
+		t.Fatalf("ChunkDocument() error = %v", err)
+	}
+
+	if len(chunks) == 0 {
+		t.Fatal("Expected at least one chunk")
+	}
+	if chunks[0].Metadata["anchor"] != "guide" {
+		t.Errorf("Expected merged section chunk to carry its heading anchor, got %q", chunks[0].Metadata["anchor"])
+	}
+}
+
+const sectionPathMarkdown = `Preamble.
+
+# Install
+
+Intro.
+
+## Windows
+
+## Linux
+
+### Troubleshooting
+
+Check the logs.
+
+## macOS
+
+# Usage
+
+### Advanced
+
+Skipped a level.`
+
+func TestSectionPath_ElementLevel(t *testing.T) {
+	chunker := NewMarkdownChunker()
+	chunks, err := chunker.ChunkDocument([]byte(sectionPathMarkdown))
+	if err != nil {
+		t.Fatalf("ChunkDocument() error = %v", err)
+	}
+
+	expected := []struct {
+		text   string
+		path   string
+		levels string
+		number string
+	}{
+		{"Preamble.", "", "", ""},
+		{"Install", "Install", "1", "1"},
+		{"Intro.", "Install", "1", "1"},
+		{"Windows", "Install > Windows", "1,2", "1.1"},
+		{"Linux", "Install > Linux", "1,2", "1.2"},
+		{"Troubleshooting", "Install > Linux > Troubleshooting", "1,2,3", "1.2.1"},
+		{"Check the logs.", "Install > Linux > Troubleshooting", "1,2,3", "1.2.1"},
+		{"macOS", "Install > macOS", "1,2", "1.3"},
+		{"Usage", "Usage", "1", "2"},
+		{"Advanced", "Usage > Advanced", "1,3", "2.1"},
+		{"Skipped a level.", "Usage > Advanced", "1,3", "2.1"},
+	}
+
+	if len(chunks) != len(expected) {
+		t.Fatalf("Expected %d chunks, got %d", len(expected), len(chunks))
+	}
+	for i, want := range expected {
+		chunk := chunks[i]
+		if chunk.Text != want.text {
+			t.Errorf("Chunk %d: expected text %q, got %q", i, want.text, chunk.Text)
+		}
+		if chunk.Metadata["section_path"] != want.path {
+			t.Errorf("Chunk %d: expected section_path %q, got %q", i, want.path, chunk.Metadata["section_path"])
+		}
+		if strings.Join(chunk.SectionPath, " > ") != want.path {
+			t.Errorf("Chunk %d: expected SectionPath %q, got %v", i, want.path, chunk.SectionPath)
+		}
+		if chunk.Metadata["section_levels"] != want.levels {
+			t.Errorf("Chunk %d: expected section_levels %q, got %q", i, want.levels, chunk.Metadata["section_levels"])
+		}
+		if chunk.Metadata["section_number"] != want.number {
+			t.Errorf("Chunk %d: expected section_number %q, got %q", i, want.number, chunk.Metadata["section_number"])
+		}
+	}
+
+	if chunks[6].Metadata["section_depth"] != "3" {
+		t.Errorf("Expected section_depth 3, got %q", chunks[6].Metadata["section_depth"])
+	}
+	if len(chunks[0].SectionPath) != 0 {
+		t.Errorf("Expected preamble to have no section path, got %v", chunks[0].SectionPath)
+	}
+}
+
+func TestSectionPath_HierarchicalStrategy(t *testing.T) {
+	chunker := NewMarkdownChunkerWithStrategy("hierarchical")
+	chunks, err := chunker.ChunkDocument([]byte(sectionPathMarkdown))
+	if err != nil {
+		t.Fatalf("ChunkDocument() error = %v", err)
+	}
+
+	var paths []string
+	for _, chunk := range chunks {
+		if chunk.Type == "heading" {
+			paths = append(paths, chunk.Metadata["section_path"]+"|"+chunk.Metadata["section_number"])
+			if strings.Join(chunk.SectionPath, " > ") != chunk.Metadata["section_path"] {
+				t.Errorf("Expected SectionPath to match metadata, got %v", chunk.SectionPath)
+			}
+		}
+	}
+	if strings.Join(paths, ";") != "Install|1;Usage|2" {
+		t.Errorf("Unexpected merged section paths %v", paths)
+	}
+}
+
+func TestSectionPath_KeptOnSplitPieces(t *testing.T) {
+	config := DefaultConfig()
+	config.SplitLists = true
+	config.ListMaxSize = 20
+
+	chunker := NewMarkdownChunkerWithConfig(config)
+	chunks, err := chunker.ChunkDocument([]byte("# Guide\n\n## Steps\n\n- first step\n- second step\n- third step"))
+	if err != nil {
+		t.Fatalf("ChunkDocument() error = %v", err)
+	}
+
+	pieces := 0
+	for _, chunk := range chunks {
+		if chunk.Type != "list" {
+			continue
+		}
+		pieces++
+		if chunk.Metadata["section_path"] != "Guide > Steps" || len(chunk.SectionPath) != 2 {
+			t.Errorf("Expected list piece to keep its section, got %q %v", chunk.Metadata["section_path"], chunk.SectionPath)
+		}
+	}
+	if pieces < 2 {
+		t.Errorf("Expected the list to be split, got %d pieces", pieces)
+	}
+}
@@ -0,0 +1,388 @@
+package markdownchunker
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/text"
+)
+
+// SemanticUnit 语义分块的基本单元
+type SemanticUnit string
+
+const (
+	// SemanticUnitSentences 段落按句子拆分为单元，其他元素整体作为一个单元（默认）
+	SemanticUnitSentences SemanticUnit = "sentences"
+	// SemanticUnitElements 每个元素作为一个单元
+	SemanticUnitElements SemanticUnit = "elements"
+)
+
+// DefaultBreakpointPercentile 默认断点百分位：相邻单元相似度处于最低 10% 时断开
+const DefaultBreakpointPercentile = 10.0
+
+// isValidSemanticUnit 检查语义单元是否有效，空值表示默认的句子单元
+func isValidSemanticUnit(unit SemanticUnit) bool {
+	switch unit {
+	case "", SemanticUnitSentences, SemanticUnitElements:
+		return true
+	}
+	return false
+}
+
+// semanticUnit 参与相似度计算的单元
+type semanticUnit struct {
+	content string // markdown 内容
+	chunk   Chunk  // 单元所属的元素块，句子单元的 Text、Links 和 Images 只包含该句
+	element int    // 所属元素的序号，同一元素的句子合并时以空格连接
+}
+
+// SemanticStrategy 基于嵌入相似度的语义分块策略
+// 先用元素级处理得到元素，按配置拆分为句子或元素单元，再通过 Embedder 计算相邻单元的相似度，
+// 在相似度低于阈值（或百分位）的位置断开，同时遵守最小和最大块大小
+type SemanticStrategy struct {
+	config *StrategyConfig
+}
+
+// NewSemanticStrategy 创建新的语义分块策略
+func NewSemanticStrategy() *SemanticStrategy {
+	return &SemanticStrategy{
+		config: SemanticConfig(0, 0),
+	}
+}
+
+// NewSemanticStrategyWithConfig 使用指定配置创建语义分块策略
+func NewSemanticStrategyWithConfig(config *StrategyConfig) *SemanticStrategy {
+	if config == nil {
+		config = SemanticConfig(0, 0)
+	}
+	return &SemanticStrategy{
+		config: config,
+	}
+}
+
+// GetName 返回策略名称
+func (s *SemanticStrategy) GetName() string {
+	return "semantic"
+}
+
+// GetDescription 返回策略描述
+func (s *SemanticStrategy) GetDescription() string {
+	return "按相邻句子或元素的嵌入相似度寻找断点的语义分块"
+}
+
+// ChunkDocument 使用语义相似度对文档进行分块
+func (s *SemanticStrategy) ChunkDocument(doc ast.Node, source []byte, chunker *MarkdownChunker) ([]Chunk, error) {
+	if doc == nil {
+		return nil, NewChunkerError(ErrorTypeStrategyExecutionFailed, "文档节点不能为空", nil).
+			WithContext("strategy", s.GetName()).
+			WithContext("function", "ChunkDocument")
+	}
+
+	if chunker == nil {
+		return nil, NewChunkerError(ErrorTypeStrategyExecutionFailed, "分块器实例不能为空", nil).
+			WithContext("strategy", s.GetName()).
+			WithContext("function", "ChunkDocument")
+	}
+
+	// 1. 使用元素级处理获取元素
+	elements, err := NewElementLevelStrategy().ChunkDocument(doc, source, chunker)
+	if err != nil {
+		return nil, NewChunkerError(ErrorTypeStrategyExecutionFailed, "获取语义单元失败", err).
+			WithContext("strategy", s.GetName()).
+			WithContext("function", "ChunkDocument")
+	}
+
+	// 2. 构建单元并计算嵌入
+	units := s.buildUnits(elements, chunker)
+	if len(units) == 0 {
+		return []Chunk{}, nil
+	}
+
+	texts := make([]string, len(units))
+	for i, unit := range units {
+		texts[i] = unit.chunk.Text
+	}
+	embedder := s.embedder()
+	vectors, err := embedder.Embed(texts)
+	if err != nil {
+		return nil, NewChunkerError(ErrorTypeStrategyExecutionFailed, "计算嵌入向量失败", err).
+			WithContext("strategy", s.GetName()).
+			WithContext("function", "ChunkDocument").
+			WithContext("embedder", embedder.Name())
+	}
+	if len(vectors) != len(units) {
+		return nil, NewChunkerError(ErrorTypeStrategyExecutionFailed, "嵌入向量数量与单元数量不一致", nil).
+			WithContext("strategy", s.GetName()).
+			WithContext("function", "ChunkDocument").
+			WithContext("embedder", embedder.Name()).
+			WithContext("unit_count", len(units)).
+			WithContext("vector_count", len(vectors))
+	}
+
+	// 3. 计算相邻单元的相似度，并在可以断开的位置中确定阈值（标题总是与其后的内容放在一起）
+	similarities := make([]float64, len(units)-1)
+	var candidates []float64
+	for i := range similarities {
+		similarities[i] = cosineSimilarity(vectors[i], vectors[i+1])
+		if units[i].chunk.Type != "heading" {
+			candidates = append(candidates, similarities[i])
+		}
+	}
+	threshold := s.breakpointThreshold(candidates)
+
+	// 4. 在断点处分组并合并
+	var chunks []Chunk
+	group := []semanticUnit{units[0]}
+	boundarySimilarity := ""
+	flush := func(nextSimilarity string) {
+		chunk := s.mergeUnits(group, len(chunks), threshold, chunker)
+		if boundarySimilarity != "" {
+			chunk.Metadata["breakpoint_similarity"] = boundarySimilarity
+		}
+		chunks = append(chunks, chunk)
+		group = nil
+		boundarySimilarity = nextSimilarity
+	}
+
+	for i := 1; i < len(units); i++ {
+		similarity := similarities[i-1]
+		combined := append(group[:len(group):len(group)], units[i])
+
+		exceedsMax := s.config.MaxChunkSize > 0 &&
+			measureChunkSize(chunker, semanticContent(combined)) > s.config.MaxChunkSize
+		belowMin := s.config.MinChunkSize > 0 &&
+			measureChunkSize(chunker, semanticContent(group)) < s.config.MinChunkSize
+		afterHeading := group[len(group)-1].chunk.Type == "heading"
+
+		if exceedsMax || (similarity <= threshold && !belowMin && !afterHeading) {
+			flush(fmt.Sprintf("%.4f", similarity))
+		}
+		group = append(group, units[i])
+	}
+	flush("")
+
+	return linkChunks(chunks), nil
+}
+
+// buildUnits 将元素转换为语义单元
+// 句子模式下段落按句子拆分，链接、行内代码等行内结构不会被拆开
+func (s *SemanticStrategy) buildUnits(elements []Chunk, chunker *MarkdownChunker) []semanticUnit {
+	var units []semanticUnit
+	for i, element := range elements {
+		if strings.TrimSpace(element.Text) == "" {
+			continue
+		}
+
+		if s.unit() != SemanticUnitSentences || element.Type != "paragraph" {
+			units = append(units, semanticUnit{content: element.Content, chunk: element, element: i})
+			continue
+		}
+
+		sentences := splitMarkdownSentences(element.Content)
+		if len(sentences) == 1 {
+			units = append(units, semanticUnit{content: element.Content, chunk: element, element: i})
+			continue
+		}
+		for _, sentence := range sentences {
+			unit := semanticUnit{content: sentence, chunk: element, element: i}
+			sentenceDoc := chunker.md.Parser().Parse(text.NewReader([]byte(sentence)))
+			unit.chunk.Text = windowPlainText(sentenceDoc, []byte(sentence))
+			unit.chunk.Links, unit.chunk.Images = (&DocumentLevelStrategy{}).extractLinksAndImages(sentenceDoc, []byte(sentence))
+			units = append(units, unit)
+		}
+	}
+	return units
+}
+
+// splitMarkdownSentences 将段落的 markdown 内容按句子拆分，跳过落在行内结构中的句子边界
+func splitMarkdownSentences(content string) []string {
+	protected := inlineProtectedSpans([]byte(content))
+
+	var sentences []string
+	start := 0
+	for _, cut := range sentenceStarts(content)[1:] {
+		if insideSpans(protected, cut) {
+			continue
+		}
+		if sentence := strings.TrimSpace(content[start:cut]); sentence != "" {
+			sentences = append(sentences, sentence)
+		}
+		start = cut
+	}
+	if sentence := strings.TrimSpace(content[start:]); sentence != "" {
+		sentences = append(sentences, sentence)
+	}
+	return sentences
+}
+
+// breakpointThreshold 返回断点的相似度阈值
+// 配置了 SimilarityThreshold 时直接使用，否则取相邻相似度的 BreakpointPercentile 百分位
+func (s *SemanticStrategy) breakpointThreshold(similarities []float64) float64 {
+	if s.config.SimilarityThreshold != 0 {
+		return s.config.SimilarityThreshold
+	}
+	if len(similarities) == 0 {
+		return 0
+	}
+
+	percentile := s.config.BreakpointPercentile
+	if percentile <= 0 {
+		percentile = DefaultBreakpointPercentile
+	}
+
+	sorted := append([]float64(nil), similarities...)
+	sort.Float64s(sorted)
+
+	// 线性插值计算百分位
+	rank := percentile / 100 * float64(len(sorted)-1)
+	lower := int(rank)
+	if lower >= len(sorted)-1 {
+		return sorted[len(sorted)-1]
+	}
+	return sorted[lower] + (rank-float64(lower))*(sorted[lower+1]-sorted[lower])
+}
+
+// semanticContent 返回单元组合并后的 markdown 内容
+// 同一段落的句子以空格连接，不同元素之间以空行分隔
+func semanticContent(group []semanticUnit) string {
+	var builder strings.Builder
+	for i, unit := range group {
+		if i > 0 {
+			if unit.element == group[i-1].element {
+				builder.WriteString(" ")
+			} else {
+				builder.WriteString("\n\n")
+			}
+		}
+		builder.WriteString(unit.content)
+	}
+	return builder.String()
+}
+
+// mergeUnits 将单元组合并为一个语义块
+func (s *SemanticStrategy) mergeUnits(group []semanticUnit, id int, threshold float64, chunker *MarkdownChunker) Chunk {
+	content := semanticContent(group)
+
+	var textParts []string
+	var elementTypes []string
+	links := make([]Link, 0)
+	images := make([]Image, 0)
+	for i, unit := range group {
+		textParts = append(textParts, unit.chunk.Text)
+		if i == 0 || unit.element != group[i-1].element {
+			elementTypes = append(elementTypes, unit.chunk.Type)
+		}
+		links = append(links, unit.chunk.Links...)
+		images = append(images, unit.chunk.Images...)
+	}
+	plainText := strings.Join(textParts, " ")
+
+	first := group[0].chunk
+	last := group[len(group)-1].chunk
+	position := ChunkPosition{
+		StartLine: first.Position.StartLine,
+		StartCol:  first.Position.StartCol,
+		EndLine:   last.Position.EndLine,
+		EndCol:    last.Position.EndCol,
+	}
+
+	metadata := map[string]string{
+		"strategy":             s.GetName(),
+		"semantic_unit":        string(s.unit()),
+		"embedder":             s.embedder().Name(),
+		"unit_count":           fmt.Sprintf("%d", len(group)),
+		"merged_types":         strings.Join(elementTypes, ","),
+		"similarity_threshold": fmt.Sprintf("%.4f", threshold),
+		"line_start":           fmt.Sprintf("%d", position.StartLine),
+		"line_end":             fmt.Sprintf("%d", position.EndLine),
+		"word_count":           fmt.Sprintf("%d", len(strings.Fields(plainText))),
+	}
+
+	level := 0
+	if first.Type == "heading" {
+		level = first.Level
+		metadata["heading_level"] = fmt.Sprintf("%d", first.Level)
+	}
+
+	chunk := Chunk{
+		ID:       id,
+		Type:     "semantic",
+		Content:  content,
+		Text:     plainText,
+		Level:    level,
+		Metadata: metadata,
+		Position: position,
+		Links:    links,
+		Images:   images,
+		Hash:     chunker.calculateContentHash(content),
+	}
+
+	// 语义块归属于第一个单元所在的章节
+	copySectionInfo(&chunk, first)
+
+	return chunk
+}
+
+// unit 返回配置的语义单元
+func (s *SemanticStrategy) unit() SemanticUnit {
+	if s.config == nil || s.config.SemanticUnit == "" {
+		return SemanticUnitSentences
+	}
+	return s.config.SemanticUnit
+}
+
+// embedder 返回配置的嵌入器，未配置时使用哈希词袋嵌入器
+func (s *SemanticStrategy) embedder() Embedder {
+	if s.config == nil || s.config.Embedder == nil {
+		return defaultEmbedder
+	}
+	return s.config.Embedder
+}
+
+// ValidateConfig 验证策略特定的配置
+func (s *SemanticStrategy) ValidateConfig(config *StrategyConfig) error {
+	if config == nil {
+		return NewChunkerError(ErrorTypeStrategyConfigInvalid, "策略配置不能为空", nil).
+			WithContext("strategy", s.GetName()).
+			WithContext("function", "ValidateConfig")
+	}
+
+	return config.ValidateConfig()
+}
+
+// Clone 创建策略的副本（用于并发安全）
+func (s *SemanticStrategy) Clone() ChunkingStrategy {
+	var configClone *StrategyConfig
+	if s.config != nil {
+		configClone = s.config.Clone()
+	}
+
+	return &SemanticStrategy{
+		config: configClone,
+	}
+}
+
+// GetConfig 获取策略配置
+func (s *SemanticStrategy) GetConfig() *StrategyConfig {
+	if s.config == nil {
+		return nil
+	}
+	return s.config.Clone()
+}
+
+// SetConfig 设置策略配置
+func (s *SemanticStrategy) SetConfig(config *StrategyConfig) error {
+	if config == nil {
+		s.config = SemanticConfig(0, 0)
+		return nil
+	}
+
+	if err := s.ValidateConfig(config); err != nil {
+		return err
+	}
+
+	s.config = config.Clone()
+	return nil
+}
@@ -0,0 +1,151 @@
+package markdownchunker
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+const semanticMarkdown = "# Notes\n\n" +
+	"Pasta needs boiling water and salt. Cook the pasta in salted water for ten minutes. Drain the pasta and add sauce.\n\n" +
+	"Rockets burn fuel to create thrust. The rocket engine pushes exhaust for thrust. A rocket needs great speed to reach orbit.\n"
+
+// staticEmbedder 返回预设向量的测试嵌入器
+type staticEmbedder struct {
+	vectors func(text string) []float64
+	err     error
+}
+
+func (e *staticEmbedder) Name() string { return "static" }
+
+func (e *staticEmbedder) Embed(texts []string) ([][]float64, error) {
+	if e.err != nil {
+		return nil, e.err
+	}
+	vectors := make([][]float64, len(texts))
+	for i, text := range texts {
+		vectors[i] = e.vectors(text)
+	}
+	return vectors, nil
+}
+
+func TestSemanticStrategy_BreaksOnTopicShift(t *testing.T) {
+	chunks := chunkWithStrategyConfig(t, SemanticConfig(0, 0), semanticMarkdown)
+	if len(chunks) != 2 {
+		t.Fatalf("Expected 2 chunks, got %d: %+v", len(chunks), chunks)
+	}
+
+	pasta, rockets := chunks[0], chunks[1]
+	if !strings.HasPrefix(pasta.Content, "# Notes") || !strings.Contains(pasta.Content, "add sauce.") ||
+		strings.Contains(pasta.Content, "Rockets") {
+		t.Errorf("Unexpected first chunk %q", pasta.Content)
+	}
+	if !strings.HasPrefix(rockets.Content, "Rockets burn fuel") || !strings.HasSuffix(rockets.Content, "reach orbit.") {
+		t.Errorf("Unexpected second chunk %q", rockets.Content)
+	}
+
+	if pasta.Type != "semantic" || pasta.Metadata["strategy"] != "semantic" || pasta.Metadata["embedder"] != "hash" {
+		t.Errorf("Unexpected chunk type or metadata: %s %v", pasta.Type, pasta.Metadata)
+	}
+	if pasta.Metadata["unit_count"] != "4" || pasta.Metadata["merged_types"] != "heading,paragraph" {
+		t.Errorf("Unexpected unit metadata %v", pasta.Metadata)
+	}
+	if _, ok := rockets.Metadata["breakpoint_similarity"]; !ok {
+		t.Error("Expected breakpoint_similarity on the second chunk")
+	}
+	if rockets.SectionPath[0] != "Notes" || rockets.PrevID != pasta.ID {
+		t.Errorf("Expected section info and navigation links, got %v prev=%d", rockets.SectionPath, rockets.PrevID)
+	}
+}
+
+func TestSemanticStrategy_RespectsSizes(t *testing.T) {
+	// 最大大小强制断开
+	for _, chunk := range chunkWithStrategyConfig(t, SemanticConfig(0, 130), semanticMarkdown) {
+		if len(chunk.Content) > 130 {
+			t.Errorf("Chunk exceeds max size: %q", chunk.Content)
+		}
+	}
+
+	// 最小大小忽略过早的断点
+	chunks := chunkWithStrategyConfig(t, SemanticConfigWithEmbedder(nil, 0.99, 1000, 0), semanticMarkdown)
+	if len(chunks) != 1 {
+		t.Errorf("Expected min size to suppress all breakpoints, got %d chunks", len(chunks))
+	}
+}
+
+func TestSemanticStrategy_ElementUnits(t *testing.T) {
+	config := SemanticConfig(0, 0)
+	config.SemanticUnit = SemanticUnitElements
+	chunks := chunkWithStrategyConfig(t, config, semanticMarkdown)
+	if len(chunks) != 2 {
+		t.Fatalf("Expected 2 chunks, got %d", len(chunks))
+	}
+	if chunks[0].Metadata["unit_count"] != "2" || chunks[0].Metadata["semantic_unit"] != "elements" {
+		t.Errorf("Unexpected metadata %v", chunks[0].Metadata)
+	}
+}
+
+func TestSemanticStrategy_CustomEmbedder(t *testing.T) {
+	// 提到 rocket 的单元与其他单元正交，唯一的断点在话题切换处
+	embedder := &staticEmbedder{vectors: func(text string) []float64 {
+		if strings.Contains(strings.ToLower(text), "rocket") {
+			return []float64{0, 1}
+		}
+		return []float64{1, 0}
+	}}
+	chunks := chunkWithStrategyConfig(t, SemanticConfigWithEmbedder(embedder, 0.5, 0, 0), semanticMarkdown)
+	if len(chunks) != 2 || chunks[0].Metadata["embedder"] != "static" {
+		t.Fatalf("Expected 2 chunks from the custom embedder, got %+v", chunks)
+	}
+	if chunks[1].Metadata["breakpoint_similarity"] != "0.0000" {
+		t.Errorf("Unexpected breakpoint similarity %q", chunks[1].Metadata["breakpoint_similarity"])
+	}
+
+	// 严格模式下嵌入器的错误直接返回，不回退到默认策略
+	config := DefaultConfig()
+	config.ErrorHandling = ErrorModeStrict
+	config.ChunkingStrategy = SemanticConfigWithEmbedder(&staticEmbedder{err: errors.New("backend unavailable")}, 0, 0, 0)
+	chunker := NewMarkdownChunkerWithConfig(config)
+	if _, err := chunker.ChunkDocument([]byte(semanticMarkdown)); err == nil {
+		t.Error("Expected embedder error to be returned")
+	}
+}
+
+func TestSplitMarkdownSentences(t *testing.T) {
+	sentences := splitMarkdownSentences("See [Dr. Smith](https://example.com/a. b) first. Then `x. y` works. 结束。好")
+	expected := []string{"See [Dr. Smith](https://example.com/a. b) first.", "Then `x. y` works.", "结束。", "好"}
+	if strings.Join(sentences, "|") != strings.Join(expected, "|") {
+		t.Errorf("Expected %q, got %q", expected, sentences)
+	}
+}
+
+func TestSemanticStrategy_Config(t *testing.T) {
+	strategy := NewSemanticStrategy()
+	invalid := []*StrategyConfig{SemanticConfig(0, 0), SemanticConfig(0, 0), SemanticConfig(0, 0)}
+	invalid[0].SemanticUnit = "paragraphs"
+	invalid[1].BreakpointPercentile = 150
+	invalid[2].SimilarityThreshold = 2
+	for i, config := range invalid {
+		if err := strategy.ValidateConfig(config); err == nil {
+			t.Errorf("Expected validation error for config %d", i)
+		}
+	}
+
+	embedder := NewHashEmbedder(32)
+	config, err := CreateConfigFromParameters("semantic", map[string]any{
+		"semantic_unit": "elements", "breakpoint_percentile": 25.0, "embedder": embedder,
+	})
+	if err != nil {
+		t.Fatalf("CreateConfigFromParameters() error = %v", err)
+	}
+	if config.SemanticUnit != SemanticUnitElements || config.BreakpointPercentile != 25 || config.Embedder != embedder {
+		t.Errorf("Unexpected config %+v", config)
+	}
+	if clone := config.Clone(); clone.Embedder != embedder {
+		t.Error("Expected Clone to keep the embedder")
+	}
+}
+
+func TestSemanticStrategy_EnabledTypes(t *testing.T) {
+	assertKeptWithEnabledTypes(t, SemanticConfig(0, 0), semanticMarkdown, "semantic")
+}
@@ -0,0 +1,353 @@
+package markdownchunker
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/text"
+)
+
+// 幻灯片的起始边界，记录在 slide_boundary 元数据中
+const (
+	SlideBoundaryStart         = "start"          // 文档开头的第一张幻灯片
+	SlideBoundaryThematicBreak = "thematic_break" // 分隔线 ---
+	SlideBoundaryHeading       = "heading"        // 不超过 SlideHeadingLevel 的标题
+)
+
+var (
+	// thematicBreakLinePattern 分隔线所在的行
+	thematicBreakLinePattern = regexp.MustCompile(`^ {0,3}(?:(?:-[ \t]*){3,}|(?:\*[ \t]*){3,}|(?:_[ \t]*){3,})$`)
+	// slideDirectivePattern Marp 指令注释中的一行，如 paginate: true、_class: lead
+	slideDirectivePattern = regexp.MustCompile(`^\s*_?(?:theme|style|headingDivider|paginate|header|footer|class|backgroundColor|backgroundImage|backgroundPosition|backgroundRepeat|backgroundSize|color|size|math|title|author|description|image|keywords|url|marp|lang|transition)\s*:`)
+	// slideBlankLinesPattern 连续的空行
+	slideBlankLinesPattern = regexp.MustCompile(`\n[ \t]*\n(?:[ \t]*\n)+`)
+)
+
+// slide 一张幻灯片的顶层节点
+type slide struct {
+	nodes    []ast.Node
+	boundary string
+}
+
+// SlidesStrategy 幻灯片分块策略，适用于 Marp、reveal.js 和 Slidev 等以 --- 分隔的演示文稿
+// 顶层分隔线（以及可选的 SlideHeadingLevel 及更高层级的标题）作为幻灯片边界，每张幻灯片生成一个块。
+// 幻灯片中的 <!-- --> 注释作为演讲者备注移到 speaker_notes 元数据中，Marp 指令注释被忽略
+type SlidesStrategy struct {
+	config *StrategyConfig
+}
+
+// NewSlidesStrategy 创建新的幻灯片分块策略
+func NewSlidesStrategy() *SlidesStrategy {
+	return &SlidesStrategy{
+		config: SlidesConfig(0),
+	}
+}
+
+// NewSlidesStrategyWithConfig 使用指定配置创建幻灯片分块策略
+func NewSlidesStrategyWithConfig(config *StrategyConfig) *SlidesStrategy {
+	if config == nil {
+		config = SlidesConfig(0)
+	}
+	return &SlidesStrategy{
+		config: config,
+	}
+}
+
+// GetName 返回策略名称
+func (s *SlidesStrategy) GetName() string {
+	return "slides"
+}
+
+// GetDescription 返回策略描述
+func (s *SlidesStrategy) GetDescription() string {
+	return "按分隔线（可选按标题）拆分演示文稿，每张幻灯片作为一个块"
+}
+
+// ChunkDocument 将演示文稿按幻灯片分块
+func (s *SlidesStrategy) ChunkDocument(doc ast.Node, source []byte, chunker *MarkdownChunker) ([]Chunk, error) {
+	if doc == nil {
+		return nil, NewChunkerError(ErrorTypeStrategyExecutionFailed, "文档节点不能为空", nil).
+			WithContext("strategy", s.GetName()).
+			WithContext("function", "ChunkDocument")
+	}
+
+	if chunker == nil {
+		return nil, NewChunkerError(ErrorTypeStrategyExecutionFailed, "分块器实例不能为空", nil).
+			WithContext("strategy", s.GetName()).
+			WithContext("function", "ChunkDocument")
+	}
+
+	slides := s.splitSlides(doc)
+	groups := make([][]ast.Node, len(slides))
+	for i, slide := range slides {
+		groups[i] = slide.nodes
+	}
+	bounds := nodeGroupBounds(groups, source)
+
+	// 以分隔线开头的文档，第一条分隔线之前没有幻灯片
+	if len(slides) > 1 && len(slides[0].nodes) == 0 {
+		slides, bounds = slides[1:], bounds[1:]
+	}
+	// slide_count 是演示文稿中的幻灯片数，包括不生成块的空幻灯片，与 slide_number 的编号一致
+	slideCount := len(slides)
+
+	lineStarts := lineStartOffsets(source)
+	var chunks []Chunk
+	for i, slide := range slides {
+		chunk, ok := s.createSlideChunk(slide, bounds[i], i+1, source, lineStarts, chunker)
+		if !ok {
+			continue
+		}
+		chunk.ID = len(chunks)
+		chunk.Metadata["slide_count"] = fmt.Sprintf("%d", slideCount)
+		chunks = append(chunks, chunk)
+	}
+
+	return linkChunks(chunks), nil
+}
+
+// splitSlides 按分隔线和配置的标题层级将顶层节点分为幻灯片，分隔线本身不属于任何幻灯片
+func (s *SlidesStrategy) splitSlides(doc ast.Node) []slide {
+	headingLevel := 0
+	if s.config != nil {
+		headingLevel = s.config.SlideHeadingLevel
+	}
+
+	slides := []slide{{boundary: SlideBoundaryStart}}
+	for node := doc.FirstChild(); node != nil; node = node.NextSibling() {
+		if _, ok := node.(*ast.ThematicBreak); ok {
+			slides = append(slides, slide{boundary: SlideBoundaryThematicBreak})
+			continue
+		}
+
+		// 标题只在当前幻灯片已有可见内容时开始新的幻灯片，避免分隔线之后的标题产生空幻灯片
+		if heading, ok := node.(*ast.Heading); ok && headingLevel > 0 && heading.Level <= headingLevel &&
+			hasVisibleSlideContent(slides[len(slides)-1].nodes) {
+			slides = append(slides, slide{boundary: SlideBoundaryHeading})
+		}
+
+		current := &slides[len(slides)-1]
+		current.nodes = append(current.nodes, node)
+	}
+	return slides
+}
+
+// hasVisibleSlideContent 判断节点中是否有注释以外的内容
+func hasVisibleSlideContent(nodes []ast.Node) bool {
+	for _, node := range nodes {
+		if block, ok := node.(*ast.HTMLBlock); !ok || block.HTMLBlockType != ast.HTMLBlockType2 {
+			return true
+		}
+	}
+	return false
+}
+
+// createSlideChunk 创建幻灯片块，幻灯片没有内容和备注时返回 false
+// 只有备注的幻灯片生成 Content 为空、Text 为备注的块
+func (s *SlidesStrategy) createSlideChunk(slide slide, bounds [2]int, number int, source []byte, lineStarts []int, chunker *MarkdownChunker) (Chunk, bool) {
+	start := min(skipWhitespace(source, bounds[0]), bounds[1])
+	end := trimSlideEnd(source, start, bounds[1])
+
+	// 注释作为备注移出内容
+	var notes []string
+	var content strings.Builder
+	offset := start
+	for _, comment := range slideComments(slide.nodes, source) {
+		if comment[0] < offset || comment[1] > end {
+			continue
+		}
+		content.Write(source[offset:comment[0]])
+		offset = comment[1]
+
+		note := strings.TrimSpace(string(source[comment[0]:comment[1]]))
+		note = strings.TrimSpace(strings.TrimSuffix(strings.TrimPrefix(note, "<!--"), "-->"))
+		if note != "" && !isSlideDirective(note) {
+			notes = append(notes, note)
+		}
+	}
+	content.Write(source[offset:end])
+
+	markdown := strings.TrimSpace(slideBlankLinesPattern.ReplaceAllString(content.String(), "\n\n"))
+	speakerNotes := strings.Join(notes, "\n\n")
+	if markdown == "" && speakerNotes == "" {
+		return Chunk{}, false
+	}
+
+	slideDoc := chunker.md.Parser().Parse(text.NewReader([]byte(markdown)))
+	plainText := strings.TrimSpace(windowPlainText(slideDoc, []byte(markdown)))
+	links, images := (&DocumentLevelStrategy{}).extractLinksAndImages(slideDoc, []byte(markdown))
+
+	position := spanPosition(lineStarts, textSpan{start: start, end: end})
+
+	metadata := map[string]string{
+		"strategy":       s.GetName(),
+		"slide_number":   fmt.Sprintf("%d", number),
+		"slide_boundary": slide.boundary,
+		"line_start":     fmt.Sprintf("%d", position.StartLine),
+		"line_end":       fmt.Sprintf("%d", position.EndLine),
+		"word_count":     fmt.Sprintf("%d", len(strings.Fields(plainText))),
+	}
+	if speakerNotes != "" {
+		metadata["speaker_notes"] = speakerNotes
+	}
+
+	// 只有备注的幻灯片以备注作为文本，避免在后处理中被当作空块过滤
+	if plainText == "" {
+		plainText = speakerNotes
+	}
+
+	// 幻灯片标题取第一个标题，幻灯片归属于标题所在的章节，没有标题时归属于第一个节点所在的章节
+	level := 0
+	var sectionNode ast.Node
+	for _, node := range slide.nodes {
+		if heading, ok := node.(*ast.Heading); ok {
+			level = heading.Level
+			metadata["slide_title"] = chunker.getNodeText(heading)
+			metadata["heading_level"] = fmt.Sprintf("%d", heading.Level)
+			sectionNode = heading
+			break
+		}
+	}
+	if sectionNode == nil && len(slide.nodes) > 0 {
+		sectionNode = slide.nodes[0]
+	}
+
+	chunk := Chunk{
+		Type:     "slide",
+		Content:  markdown,
+		Text:     plainText,
+		Level:    level,
+		Metadata: metadata,
+		Position: position,
+		Links:    links,
+		Images:   images,
+		Hash:     chunker.calculateContentHash(markdown),
+	}
+
+	if sectionNode != nil {
+		chunker.attachSectionInfo(&chunk, sectionNode)
+	}
+
+	return chunk, true
+}
+
+// trimSlideEnd 去掉幻灯片区域末尾的空白和结束幻灯片的分隔线，返回内容的结束偏移
+// 紧跟在文本行之后的 --- 是 setext 标题的下划线，保留不动
+func trimSlideEnd(source []byte, start, end int) int {
+	for end > start {
+		for end > start && strings.ContainsRune(" \t\r\n", rune(source[end-1])) {
+			end--
+		}
+		if end == start {
+			return start
+		}
+
+		lineStart := start + bytes.LastIndexByte(source[start:end], '\n') + 1
+		if !thematicBreakLinePattern.Match(source[lineStart:end]) {
+			return end
+		}
+		if lineStart > start {
+			previousStart := start + bytes.LastIndexByte(source[start:lineStart-1], '\n') + 1
+			if strings.TrimSpace(string(source[previousStart:lineStart-1])) != "" {
+				return end
+			}
+		}
+		end = lineStart
+	}
+	return end
+}
+
+// slideComments 返回节点中 HTML 注释在源内容中的区域，按文档顺序排列
+func slideComments(nodes []ast.Node, source []byte) [][2]int {
+	var comments [][2]int
+	for _, node := range nodes {
+		ast.Walk(node, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+			if !entering {
+				return ast.WalkContinue, nil
+			}
+			switch v := n.(type) {
+			case *ast.HTMLBlock:
+				if v.HTMLBlockType == ast.HTMLBlockType2 && v.Lines().Len() > 0 {
+					end := v.Lines().At(v.Lines().Len() - 1).Stop
+					if v.HasClosure() {
+						end = v.ClosureLine.Stop
+					}
+					// 注释块的行包括结尾的换行
+					for end > 0 && strings.ContainsRune(" \t\r\n", rune(source[end-1])) {
+						end--
+					}
+					comments = append(comments, [2]int{v.Lines().At(0).Start, end})
+				}
+				return ast.WalkSkipChildren, nil
+			case *ast.RawHTML:
+				if v.Segments.Len() > 0 {
+					first, last := v.Segments.At(0), v.Segments.At(v.Segments.Len()-1)
+					if bytes.HasPrefix(source[first.Start:first.Stop], []byte("<!--")) {
+						comments = append(comments, [2]int{first.Start, last.Stop})
+					}
+				}
+			}
+			return ast.WalkContinue, nil
+		})
+	}
+	return comments
+}
+
+// isSlideDirective 判断注释是否为 Marp 指令（每一行都是已知的 key: value 指令）
+func isSlideDirective(comment string) bool {
+	for _, line := range strings.Split(comment, "\n") {
+		if strings.TrimSpace(line) != "" && !slideDirectivePattern.MatchString(line) {
+			return false
+		}
+	}
+	return true
+}
+
+// ValidateConfig 验证策略特定的配置
+func (s *SlidesStrategy) ValidateConfig(config *StrategyConfig) error {
+	if config == nil {
+		return NewChunkerError(ErrorTypeStrategyConfigInvalid, "策略配置不能为空", nil).
+			WithContext("strategy", s.GetName()).
+			WithContext("function", "ValidateConfig")
+	}
+
+	return config.ValidateConfig()
+}
+
+// Clone 创建策略的副本（用于并发安全）
+func (s *SlidesStrategy) Clone() ChunkingStrategy {
+	var configClone *StrategyConfig
+	if s.config != nil {
+		configClone = s.config.Clone()
+	}
+
+	return &SlidesStrategy{
+		config: configClone,
+	}
+}
+
+// GetConfig 获取策略配置
+func (s *SlidesStrategy) GetConfig() *StrategyConfig {
+	if s.config == nil {
+		return nil
+	}
+	return s.config.Clone()
+}
+
+// SetConfig 设置策略配置
+func (s *SlidesStrategy) SetConfig(config *StrategyConfig) error {
+	if config == nil {
+		s.config = SlidesConfig(0)
+		return nil
+	}
+
+	if err := s.ValidateConfig(config); err != nil {
+		return err
+	}
+
+	s.config = config.Clone()
+	return nil
+}
@@ -0,0 +1,168 @@
+package markdownchunker
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/yuin/goldmark/text"
+)
+
+const slidesMarkdown = "---\nmarp: true\n---\n\n" +
+	"# Welcome\n\nIntro slide with [link](https://example.com).\n\n<!-- Greet the audience.\nMention the agenda. -->\n\n" +
+	"---\n\n<!-- _class: lead -->\n\n## Agenda\n\n- One\n- Two\n\n" +
+	"---\n\n---\n\n" +
+	"No heading here. <!-- inline note -->\n\n```md\n<!-- not a note -->\n---\n```\n\n" +
+	"---\n\n# Part 2\n\nText.\n\n## Details\n\nMore.\n"
+
+func TestSlidesStrategy_ThematicBreaks(t *testing.T) {
+	chunks := chunkWithStrategyConfig(t, SlidesConfig(0), slidesMarkdown)
+
+	expected := []struct {
+		number, title, notes, content string
+	}{
+		{"1", "Welcome", "Greet the audience.\nMention the agenda.", "# Welcome\n\nIntro slide with [link](https://example.com)."},
+		{"2", "Agenda", "", "## Agenda\n\n- One\n- Two"},
+		// 第 3 张幻灯片为空，不生成块但占用编号
+		{"4", "", "inline note", "No heading here. \n\n```md\n<!-- not a note -->\n---\n```"},
+		{"5", "Part 2", "", "# Part 2\n\nText.\n\n## Details\n\nMore."},
+	}
+	if len(chunks) != len(expected) {
+		t.Fatalf("Expected %d slides, got %d", len(expected), len(chunks))
+	}
+
+	for i, want := range expected {
+		chunk := chunks[i]
+		if chunk.ID != i || chunk.Type != "slide" || chunk.Metadata["strategy"] != "slides" {
+			t.Errorf("Slide %d: unexpected ID %d, type %s or strategy %s", i, chunk.ID, chunk.Type, chunk.Metadata["strategy"])
+		}
+		if chunk.Metadata["slide_number"] != want.number || chunk.Metadata["slide_title"] != want.title ||
+			chunk.Metadata["speaker_notes"] != want.notes || chunk.Metadata["slide_count"] != "5" {
+			t.Errorf("Slide %d: unexpected metadata %v", i, chunk.Metadata)
+		}
+		if chunk.Content != want.content {
+			t.Errorf("Slide %d: content = %q, want %q", i, chunk.Content, want.content)
+		}
+		if strings.Contains(chunk.Text, "Greet the audience") || strings.Contains(chunk.Content, "_class") {
+			t.Errorf("Slide %d: notes or directives left in content: %q", i, chunk.Content)
+		}
+	}
+
+	// 结束列不包含在范围内，指向 "Mention the agenda. -->" 之后
+	if chunks[0].Position != (ChunkPosition{StartLine: 5, StartCol: 1, EndLine: 10, EndCol: 24}) {
+		t.Errorf("Unexpected position of the first slide %+v", chunks[0].Position)
+	}
+	if len(chunks[0].Links) != 1 || chunks[0].Links[0].URL != "https://example.com" {
+		t.Errorf("Expected slide link, got %+v", chunks[0].Links)
+	}
+	if chunks[1].Metadata["section_path"] != "Welcome > Agenda" || chunks[1].Level != 2 {
+		t.Errorf("Expected slide section from its title, got %v level %d", chunks[1].Metadata, chunks[1].Level)
+	}
+}
+
+func TestSlidesStrategy_HeadingBoundaries(t *testing.T) {
+	chunks := chunkWithStrategyConfig(t, SlidesConfig(2), slidesMarkdown)
+	if len(chunks) != 5 {
+		t.Fatalf("Expected 5 slides, got %d", len(chunks))
+	}
+
+	// 分隔线之后的标题不会再产生空幻灯片
+	if chunks[1].Metadata["slide_boundary"] != SlideBoundaryThematicBreak {
+		t.Errorf("Expected thematic break boundary, got %v", chunks[1].Metadata)
+	}
+	last := chunks[len(chunks)-1]
+	if last.Content != "## Details\n\nMore." || last.Metadata["slide_boundary"] != SlideBoundaryHeading ||
+		last.Metadata["slide_number"] != "6" {
+		t.Errorf("Expected H2 to start a new slide, got %q %v", last.Content, last.Metadata)
+	}
+	if chunks[3].Content != "# Part 2\n\nText." {
+		t.Errorf("Unexpected slide before heading boundary %q", chunks[3].Content)
+	}
+}
+
+func TestSlidesStrategy_SetextHeadingAndPlainDocument(t *testing.T) {
+	chunks := chunkWithStrategyConfig(t, SlidesConfig(0), "Title\n---\n\nBody text.\n\n---\n\nNext slide.\n")
+	if len(chunks) != 2 {
+		t.Fatalf("Expected 2 slides, got %d", len(chunks))
+	}
+	if chunks[0].Content != "Title\n---\n\nBody text." || chunks[0].Metadata["slide_title"] != "Title" {
+		t.Errorf("Setext underline treated as a slide break: %q %v", chunks[0].Content, chunks[0].Metadata)
+	}
+
+	chunks = chunkWithStrategyConfig(t, SlidesConfig(0), "# Only\n\nOne slide.")
+	if len(chunks) != 1 || chunks[0].Metadata["slide_boundary"] != SlideBoundaryStart || chunks[0].Metadata["slide_count"] != "1" {
+		t.Errorf("Expected a single slide, got %+v", chunks)
+	}
+}
+
+func TestSlidesStrategy_NotesOnlySlide(t *testing.T) {
+	markdown := "# One\n\nText.\n\n---\n\n<!-- Only notes here. -->\n\n---\n\n---\n\n# Three\n"
+
+	chunker := NewMarkdownChunkerWithStrategy("slides")
+	chunks, err := chunker.ChunkDocument([]byte(markdown))
+	if err != nil {
+		t.Fatalf("ChunkDocument() error = %v", err)
+	}
+
+	doc := chunker.md.Parser().Parse(text.NewReader([]byte(markdown)))
+	raw, err := NewSlidesStrategy().ChunkDocument(doc, []byte(markdown), chunker)
+	if err != nil {
+		t.Fatalf("ChunkDocument() error = %v", err)
+	}
+	if len(chunks) != 3 || len(raw) != len(chunks) {
+		t.Fatalf("Expected 3 slides from both the strategy and the chunker, got %d and %d", len(raw), len(chunks))
+	}
+
+	notes := chunks[1]
+	if notes.Content != "" || notes.Text != "Only notes here." || notes.Metadata["speaker_notes"] != "Only notes here." ||
+		notes.Metadata["slide_number"] != "2" {
+		t.Errorf("Unexpected notes-only slide %q %q %v", notes.Content, notes.Text, notes.Metadata)
+	}
+	// 空的第 3 张幻灯片计入 slide_count，但不生成块
+	for _, chunk := range chunks {
+		if chunk.Metadata["slide_count"] != "4" {
+			t.Errorf("Expected slide_count to count every slide in the deck, got %v", chunk.Metadata)
+		}
+	}
+	if chunks[2].Metadata["slide_number"] != "4" {
+		t.Errorf("Expected the last slide to keep its deck position, got %v", chunks[2].Metadata)
+	}
+}
+
+func TestSlidesStrategy_Config(t *testing.T) {
+	strategy := NewSlidesStrategy()
+	if err := strategy.ValidateConfig(SlidesConfig(7)); err == nil {
+		t.Error("Expected error for heading level above 6")
+	}
+	if err := strategy.SetConfig(SlidesConfig(2)); err != nil {
+		t.Fatalf("SetConfig() error = %v", err)
+	}
+	if strategy.Clone().(*SlidesStrategy).GetConfig().SlideHeadingLevel != 2 {
+		t.Error("Clone lost slide heading level")
+	}
+
+	config, err := CreateConfigFromParameters("slides", map[string]any{"slide_heading_level": 1})
+	if err != nil {
+		t.Fatalf("CreateConfigFromParameters() error = %v", err)
+	}
+	if config.SlideHeadingLevel != 1 {
+		t.Errorf("Unexpected config from parameters %+v", config)
+	}
+}
+
+func TestIsSlideDirective(t *testing.T) {
+	tests := map[string]bool{
+		"paginate: true":                      true,
+		"_class: lead\nbackgroundColor: #fff": true,
+		"Remember to pause here":              false,
+		"Note: explain the chart":             false,
+	}
+	for comment, want := range tests {
+		if got := isSlideDirective(comment); got != want {
+			t.Errorf("isSlideDirective(%q) = %v, want %v", comment, got, want)
+		}
+	}
+}
+
+func TestSlidesStrategy_EnabledTypes(t *testing.T) {
+	assertKeptWithEnabledTypes(t, SlidesConfig(0), slidesMarkdown, "slide")
+}
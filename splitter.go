@@ -0,0 +1,450 @@
+package markdownchunker
+
+import (
+	"bytes"
+	"fmt"
+	"maps"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/yuin/goldmark/ast"
+	extast "github.com/yuin/goldmark/extension/ast"
+	"github.com/yuin/goldmark/text"
+)
+
+// 递归拆分的边界层级，按优先级从粗到细排列
+const (
+	splitLevelParagraph = iota // 段落边界（空行）
+	splitLevelLine             // 行边界
+	splitLevelSentence         // 句子边界
+	splitLevelWord             // 单词边界（空白字符）
+	splitLevelRune             // 字符边界（最后的兜底）
+)
+
+var (
+	// paragraphBoundaryPattern 匹配段落之间的空行
+	paragraphBoundaryPattern = regexp.MustCompile(`\n[ \t]*\n\s*`)
+	// wordBoundaryPattern 匹配单词之间的空白字符
+	wordBoundaryPattern = regexp.MustCompile(`\s+`)
+)
+
+// textSpan 表示内容中的一个字节区间 [start, end)
+type textSpan struct {
+	start int
+	end   int
+}
+
+// truncateUTF8 按字节数截断字符串，保证不会截断多字节字符
+func truncateUTF8(s string, maxBytes int) string {
+	if maxBytes <= 0 {
+		return ""
+	}
+	if len(s) <= maxBytes {
+		return s
+	}
+
+	cut := maxBytes
+	for cut > 0 && !utf8.RuneStart(s[cut]) {
+		cut--
+	}
+	return s[:cut]
+}
+
+// splitOversizedChunk 将超过大小限制的块在自然边界处递归拆分
+// 依次尝试段落、行、句子、单词边界，最后按字符拆分，保证不丢失任何内容
+func (c *MarkdownChunker) splitOversizedChunk(chunk Chunk, maxSize int) []Chunk {
+	if maxSize <= 0 || c.measureSize(chunk.Content) <= maxSize {
+		return []Chunk{chunk}
+	}
+
+	// 围栏代码块按声明边界拆分，并保留围栏
+	if chunk.Type == "code" {
+		if pieces := c.splitCodeChunk(chunk, maxSize); len(pieces) > 1 {
+			return pieces
+		}
+	}
+
+	// 表格按数据行拆分，并在每个片段中重复表头
+	if chunk.Type == "table" {
+		if pieces := c.splitTableChunk(chunk, maxSize); len(pieces) > 1 {
+			return pieces
+		}
+	}
+
+	// 列表按列表项拆分，嵌套项带上祖先项链
+	if chunk.Type == "list" {
+		if pieces := c.splitListChunk(chunk, maxSize); len(pieces) > 1 {
+			return pieces
+		}
+	}
+
+	spans := c.splitSpanRecursively(chunk.Content, textSpan{0, len(chunk.Content)}, maxSize, splitLevelParagraph)
+	spans = trimSpans(chunk.Content, spans)
+	if len(spans) <= 1 {
+		return []Chunk{chunk}
+	}
+
+	return c.buildSplitChunks(chunk, spans)
+}
+
+// splitSpanRecursively 在指定层级的边界处拆分区间，过大的片段继续在更细的层级拆分
+func (c *MarkdownChunker) splitSpanRecursively(content string, span textSpan, maxSize, level int) []textSpan {
+	if c.measureSize(content[span.start:span.end]) <= maxSize || level > splitLevelRune {
+		return []textSpan{span}
+	}
+
+	segments := splitSegments(content, span, level)
+	if len(segments) <= 1 {
+		return c.splitSpanRecursively(content, span, maxSize, level+1)
+	}
+
+	var result []textSpan
+	current := textSpan{start: -1}
+	flush := func() {
+		if current.start >= 0 {
+			result = append(result, current)
+			current = textSpan{start: -1}
+		}
+	}
+
+	// 贪心合并相邻片段，直到达到大小上限
+	for _, segment := range segments {
+		if c.measureSize(content[segment.start:segment.end]) > maxSize {
+			flush()
+			result = append(result, c.splitSpanRecursively(content, segment, maxSize, level+1)...)
+			continue
+		}
+
+		if current.start < 0 {
+			current = segment
+			continue
+		}
+
+		if c.measureSize(content[current.start:segment.end]) <= maxSize {
+			current.end = segment.end
+		} else {
+			flush()
+			current = segment
+		}
+	}
+	flush()
+
+	return result
+}
+
+// splitSegments 按指定层级的边界将区间切分为连续片段，分隔符归属前一个片段
+func splitSegments(content string, span textSpan, level int) []textSpan {
+	segmentText := content[span.start:span.end]
+
+	var cuts []int
+	switch level {
+	case splitLevelParagraph:
+		for _, match := range paragraphBoundaryPattern.FindAllStringIndex(segmentText, -1) {
+			cuts = append(cuts, match[1])
+		}
+	case splitLevelLine:
+		for i := 0; i < len(segmentText); i++ {
+			if segmentText[i] == '\n' {
+				cuts = append(cuts, i+1)
+			}
+		}
+	case splitLevelSentence:
+		cuts = sentenceBoundaries(segmentText)
+	case splitLevelWord:
+		for _, match := range wordBoundaryPattern.FindAllStringIndex(segmentText, -1) {
+			cuts = append(cuts, match[1])
+		}
+	default:
+		for i := range segmentText {
+			if i > 0 {
+				cuts = append(cuts, i)
+			}
+		}
+	}
+
+	var segments []textSpan
+	start := 0
+	for _, cut := range cuts {
+		if cut <= start || cut >= len(segmentText) {
+			continue
+		}
+		segments = append(segments, textSpan{span.start + start, span.start + cut})
+		start = cut
+	}
+	segments = append(segments, textSpan{span.start + start, span.end})
+
+	return segments
+}
+
+// sentenceBoundaries 查找句子边界，同时支持中英文标点
+// 英文句末标点后必须跟空白字符，中文句末标点后直接断句
+func sentenceBoundaries(s string) []int {
+	var cuts []int
+
+	for i := 0; i < len(s); {
+		r, size := utf8.DecodeRuneInString(s[i:])
+		i += size
+
+		if !isSentenceTerminator(r) {
+			continue
+		}
+
+		// 吸收连续的句末标点和右引号、右括号
+		for i < len(s) {
+			next, nextSize := utf8.DecodeRuneInString(s[i:])
+			if !isSentenceTerminator(next) && !isClosingPunctuation(next) {
+				break
+			}
+			i += nextSize
+		}
+
+		if r < utf8.RuneSelf {
+			// 英文标点：要求后面是空白字符或文本结尾，避免拆开 3.14、e.g. 之类的内容
+			if i < len(s) {
+				next, _ := utf8.DecodeRuneInString(s[i:])
+				if !unicode.IsSpace(next) {
+					continue
+				}
+			}
+		}
+
+		// 将句子后的空白字符归属当前句子
+		for i < len(s) {
+			next, nextSize := utf8.DecodeRuneInString(s[i:])
+			if !unicode.IsSpace(next) {
+				break
+			}
+			i += nextSize
+		}
+
+		cuts = append(cuts, i)
+	}
+
+	return cuts
+}
+
+// isSentenceTerminator 判断是否为句末标点
+func isSentenceTerminator(r rune) bool {
+	switch r {
+	case '.', '!', '?', ';', '。', '！', '？', '；', '…':
+		return true
+	}
+	return false
+}
+
+// isClosingPunctuation 判断是否为句末可能出现的右引号或右括号
+func isClosingPunctuation(r rune) bool {
+	switch r {
+	case '"', '\'', ')', ']', '”', '’', '）', '」', '』', '》':
+		return true
+	}
+	return false
+}
+
+// trimSpans 去除每个区间首尾的空白字符，并丢弃空区间
+func trimSpans(content string, spans []textSpan) []textSpan {
+	var trimmed []textSpan
+	for _, span := range spans {
+		segment := content[span.start:span.end]
+		leading := len(segment) - len(strings.TrimLeftFunc(segment, unicode.IsSpace))
+		trailing := len(segment) - len(strings.TrimRightFunc(segment, unicode.IsSpace))
+		if leading+trailing >= len(segment) {
+			continue
+		}
+		trimmed = append(trimmed, textSpan{span.start + leading, span.end - trailing})
+	}
+	return trimmed
+}
+
+// buildSplitChunks 根据拆分区间构建新的块，每个块拥有独立的位置、哈希和元数据
+func (c *MarkdownChunker) buildSplitChunks(chunk Chunk, spans []textSpan) []Chunk {
+	pieces := make([]Chunk, 0, len(spans))
+	isFenced := strings.HasPrefix(strings.TrimSpace(chunk.Content), "```") ||
+		strings.HasPrefix(strings.TrimSpace(chunk.Content), "~~~")
+
+	for i, span := range spans {
+		pieceContent := chunk.Content[span.start:span.end]
+		pieceText := c.fragmentText(chunk.Type, pieceContent, isFenced)
+		position := splitPiecePosition(chunk.Position, chunk.Content, span)
+
+		metadata := make(map[string]string, len(chunk.Metadata)+4)
+		maps.Copy(metadata, chunk.Metadata)
+		metadata["split_part"] = fmt.Sprintf("%d", i+1)
+		metadata["split_total"] = fmt.Sprintf("%d", len(spans))
+		metadata["original_chunk_id"] = fmt.Sprintf("%d", chunk.ID)
+		updateSplitMetadata(metadata, position, pieceContent, pieceText)
+
+		piece := Chunk{
+			ID:          chunk.ID,
+			Type:        chunk.Type,
+			Content:     pieceContent,
+			Text:        pieceText,
+			Level:       chunk.Level,
+			Metadata:    metadata,
+			Position:    position,
+			Links:       filterLinksInContent(chunk.Links, pieceContent),
+			Images:      filterImagesInContent(chunk.Images, pieceContent),
+			Hash:        c.calculateContentHash(pieceContent),
+			SectionPath: chunk.SectionPath,
+		}
+		c.reattachFootnotes(&piece)
+
+		// 重叠上下文只保留在首尾片段上
+		if prefix, ok := metadata["overlap_prefix"]; ok {
+			if i == 0 {
+				piece.Text = prefix + " " + piece.Text
+			} else {
+				delete(metadata, "overlap_prefix")
+			}
+		}
+		if suffix, ok := metadata["overlap_suffix"]; ok {
+			if i == len(spans)-1 {
+				piece.Text = piece.Text + " " + suffix
+			} else {
+				delete(metadata, "overlap_suffix")
+			}
+		}
+
+		pieces = append(pieces, piece)
+	}
+
+	return pieces
+}
+
+// updateSplitMetadata 更新拆分块中与位置和长度相关的元数据
+func updateSplitMetadata(metadata map[string]string, position ChunkPosition, content, text string) {
+	if _, ok := metadata["line_start"]; ok {
+		metadata["line_start"] = fmt.Sprintf("%d", position.StartLine)
+		metadata["line_end"] = fmt.Sprintf("%d", position.EndLine)
+		metadata["char_start"] = fmt.Sprintf("%d", position.StartCol)
+		metadata["char_end"] = fmt.Sprintf("%d", position.EndCol)
+	}
+	if _, ok := metadata["word_count"]; ok {
+		metadata["word_count"] = fmt.Sprintf("%d", len(strings.Fields(text)))
+	}
+	if _, ok := metadata["char_count"]; ok {
+		metadata["char_count"] = fmt.Sprintf("%d", len(text))
+	}
+	if _, ok := metadata["content_length"]; ok {
+		metadata["content_length"] = fmt.Sprintf("%d", len(content))
+	}
+	if _, ok := metadata["text_length"]; ok {
+		metadata["text_length"] = fmt.Sprintf("%d", len(text))
+	}
+}
+
+// spanPosition 将源内容中的字节范围转换为块位置，结束列不包含在范围内
+func spanPosition(lineStarts []int, span textSpan) ChunkPosition {
+	startLine, startCol := offsetLineCol(lineStarts, span.start)
+	endLine, endCol := offsetLineCol(lineStarts, span.end)
+	return ChunkPosition{StartLine: startLine, StartCol: startCol, EndLine: endLine, EndCol: endCol}
+}
+
+// splitPiecePosition 根据片段在原始内容中的偏移计算其在文档中的位置
+func splitPiecePosition(base ChunkPosition, content string, span textSpan) ChunkPosition {
+	startLine := base.StartLine + strings.Count(content[:span.start], "\n")
+	endLine := base.StartLine + strings.Count(content[:span.end], "\n")
+
+	startCol := span.start - (strings.LastIndex(content[:span.start], "\n") + 1) + 1
+	if startLine == base.StartLine {
+		startCol += base.StartCol - 1
+	}
+
+	endCol := span.end - (strings.LastIndex(content[:span.end], "\n") + 1) + 1
+	if endLine == base.StartLine {
+		endCol += base.StartCol - 1
+	}
+
+	return ChunkPosition{
+		StartLine: startLine,
+		EndLine:   endLine,
+		StartCol:  startCol,
+		EndCol:    endCol,
+	}
+}
+
+// fragmentText 提取 markdown 片段的纯文本内容
+func (c *MarkdownChunker) fragmentText(chunkType, fragment string, isFenced bool) string {
+	if chunkType == "code" {
+		return codeFragmentText(fragment, isFenced)
+	}
+
+	source := c.fragmentSource(fragment)
+	doc := c.md.Parser().Parse(text.NewReader(source))
+	if doc == nil {
+		return strings.Join(strings.Fields(fragment), " ")
+	}
+
+	// getNodeText 依赖 c.source，临时切换到片段内容
+	originalSource := c.source
+	c.source = source
+	defer func() {
+		c.source = originalSource
+	}()
+
+	var parts []string
+	for child := doc.FirstChild(); child != nil; child = child.NextSibling() {
+		var part string
+		switch n := child.(type) {
+		case *ast.FencedCodeBlock, *ast.CodeBlock:
+			var buf bytes.Buffer
+			for i := 0; i < n.Lines().Len(); i++ {
+				line := n.Lines().At(i)
+				buf.Write(line.Value(source))
+			}
+			part = strings.TrimSpace(buf.String())
+		case *ast.List:
+			part = c.getListText(n)
+		case *extast.FootnoteList:
+			// 追加的脚注定义由 reattachFootnotes 处理
+			continue
+		default:
+			part = c.getNodeText(child)
+		}
+		if part != "" {
+			parts = append(parts, part)
+		}
+	}
+
+	return strings.Join(parts, " ")
+}
+
+// codeFragmentText 提取代码片段的纯文本，去除围栏和缩进标记
+func codeFragmentText(fragment string, isFenced bool) string {
+	var lines []string
+	for _, line := range strings.Split(fragment, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if isFenced && (strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~")) {
+			continue
+		}
+		if !isFenced {
+			line = strings.TrimPrefix(line, "    ")
+		}
+		lines = append(lines, line)
+	}
+	return strings.Trim(strings.Join(lines, "\n"), "\n")
+}
+
+// filterLinksInContent 保留出现在指定内容中的链接
+func filterLinksInContent(links []Link, content string) []Link {
+	filtered := make([]Link, 0)
+	for _, link := range links {
+		if link.URL != "" && strings.Contains(content, link.URL) {
+			filtered = append(filtered, link)
+		}
+	}
+	return filtered
+}
+
+// filterImagesInContent 保留出现在指定内容中的图片
+func filterImagesInContent(images []Image, content string) []Image {
+	filtered := make([]Image, 0)
+	for _, image := range images {
+		if image.URL != "" && strings.Contains(content, image.URL) {
+			filtered = append(filtered, image)
+		}
+	}
+	return filtered
+}
@@ -0,0 +1,188 @@
+package markdownchunker
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestOversizeSplitMode_SplitsAtParagraphAndSentenceBoundaries(t *testing.T) {
+	paragraph := strings.Repeat("This is a sentence about chunking. ", 20)
+	markdown := "# Title\n\n" + paragraph + "\n\nShort paragraph."
+
+	config := DefaultConfig()
+	config.MaxChunkSize = 120
+	config.OversizeHandling = OversizeModeSplit
+	config.ErrorHandling = ErrorModePermissive
+
+	chunker := NewMarkdownChunkerWithConfig(config)
+	chunks, err := chunker.ChunkDocument([]byte(markdown))
+	if err != nil {
+		t.Fatalf("ChunkDocument() error = %v", err)
+	}
+
+	if len(chunker.GetErrorsByType(ErrorTypeChunkTooLarge)) != 0 {
+		t.Error("Expected no ChunkTooLarge errors in split mode")
+	}
+
+	var parts []Chunk
+	for _, chunk := range chunks {
+		if len(chunk.Content) > config.MaxChunkSize {
+			t.Errorf("Chunk %d exceeds max size: %d", chunk.ID, len(chunk.Content))
+		}
+		if chunk.Metadata["split_part"] != "" {
+			parts = append(parts, chunk)
+		}
+	}
+
+	if len(parts) < 2 {
+		t.Fatalf("Expected oversized paragraph to be split into multiple chunks, got %d", len(parts))
+	}
+
+	var rebuilt []string
+	for i, part := range parts {
+		if !strings.HasSuffix(part.Content, ".") {
+			t.Errorf("Expected part %d to end at a sentence boundary, got %q", i, part.Content)
+		}
+		if part.Metadata["split_total"] != fmt.Sprintf("%d", len(parts)) {
+			t.Errorf("Expected split_total %d, got %s", len(parts), part.Metadata["split_total"])
+		}
+		if part.Metadata["split_part"] != fmt.Sprintf("%d", i+1) {
+			t.Errorf("Expected split_part %d, got %s", i+1, part.Metadata["split_part"])
+		}
+		if part.Type != "paragraph" {
+			t.Errorf("Expected split part to keep type paragraph, got %s", part.Type)
+		}
+		rebuilt = append(rebuilt, part.Content)
+	}
+
+	if strings.Join(strings.Fields(strings.Join(rebuilt, " ")), " ") != strings.TrimSpace(paragraph) {
+		t.Error("Expected split parts to cover the whole paragraph without losing content")
+	}
+
+	for i, chunk := range chunks {
+		if chunk.ID != i {
+			t.Errorf("Expected chunk IDs to be renumbered sequentially, chunk %d has ID %d", i, chunk.ID)
+		}
+	}
+}
+
+func TestOversizeSplitMode_ChineseText(t *testing.T) {
+	markdown := strings.Repeat("这是一个用于测试中文分块的句子。", 30)
+
+	config := DefaultConfig()
+	config.MaxChunkSize = 100
+	config.OversizeHandling = OversizeModeSplit
+
+	chunker := NewMarkdownChunkerWithConfig(config)
+	chunks, err := chunker.ChunkDocument([]byte(markdown))
+	if err != nil {
+		t.Fatalf("ChunkDocument() error = %v", err)
+	}
+
+	if len(chunks) < 2 {
+		t.Fatalf("Expected Chinese paragraph to be split, got %d chunks", len(chunks))
+	}
+
+	for _, chunk := range chunks {
+		if len(chunk.Content) > config.MaxChunkSize {
+			t.Errorf("Chunk %d exceeds max size: %d", chunk.ID, len(chunk.Content))
+		}
+		if !utf8.ValidString(chunk.Content) || !utf8.ValidString(chunk.Text) {
+			t.Errorf("Chunk %d contains invalid UTF-8", chunk.ID)
+		}
+		if !strings.HasSuffix(chunk.Content, "。") {
+			t.Errorf("Expected chunk to end at a Chinese sentence boundary, got %q", chunk.Content)
+		}
+	}
+}
+
+func TestOversizeSplitMode_CodeBlockKeepsLines(t *testing.T) {
+	var lines []string
+	for i := 0; i < 30; i++ {
+		lines = append(lines, "fmt.Println(\"line\")")
+	}
+	markdown := "```go\n" + strings.Join(lines, "\n") + "\n```"
+
+	config := DefaultConfig()
+	config.MaxChunkSize = 150
+	config.OversizeHandling = OversizeModeSplit
+
+	chunker := NewMarkdownChunkerWithConfig(config)
+	chunks, err := chunker.ChunkDocument([]byte(markdown))
+	if err != nil {
+		t.Fatalf("ChunkDocument() error = %v", err)
+	}
+
+	if len(chunks) < 2 {
+		t.Fatalf("Expected code block to be split, got %d chunks", len(chunks))
+	}
+
+	previousLine := 0
+	for _, chunk := range chunks {
+		if chunk.Type != "code" {
+			t.Errorf("Expected code type, got %s", chunk.Type)
+		}
+		if strings.Contains(chunk.Text, "```") {
+			t.Errorf("Expected code text without fences, got %q", chunk.Text)
+		}
+		if chunk.Position.StartLine <= previousLine {
+			t.Errorf("Expected increasing start lines, got %d after %d", chunk.Position.StartLine, previousLine)
+		}
+		previousLine = chunk.Position.StartLine
+	}
+}
+
+func TestOversizeTruncateMode_KeepsValidUTF8(t *testing.T) {
+	markdown := strings.Repeat("中文内容", 20)
+
+	config := DefaultConfig()
+	config.MaxChunkSize = 10
+	config.ErrorHandling = ErrorModePermissive
+
+	chunker := NewMarkdownChunkerWithConfig(config)
+	chunks, err := chunker.ChunkDocument([]byte(markdown))
+	if err != nil {
+		t.Fatalf("ChunkDocument() error = %v", err)
+	}
+
+	if len(chunks) != 1 {
+		t.Fatalf("Expected 1 chunk, got %d", len(chunks))
+	}
+	if len(chunks[0].Content) > config.MaxChunkSize {
+		t.Errorf("Expected content to be truncated, got %d bytes", len(chunks[0].Content))
+	}
+	if !utf8.ValidString(chunks[0].Content) {
+		t.Error("Expected truncated content to be valid UTF-8")
+	}
+}
+
+func TestTruncateUTF8(t *testing.T) {
+	tests := []struct {
+		input    string
+		maxBytes int
+		expected string
+	}{
+		{"hello", 10, "hello"},
+		{"hello", 3, "hel"},
+		{"中文", 4, "中"},
+		{"中文", 2, ""},
+		{"abc", 0, ""},
+	}
+
+	for _, test := range tests {
+		if result := truncateUTF8(test.input, test.maxBytes); result != test.expected {
+			t.Errorf("truncateUTF8(%q, %d) = %q, expected %q", test.input, test.maxBytes, result, test.expected)
+		}
+	}
+}
+
+func TestValidateConfig_OversizeHandling(t *testing.T) {
+	config := DefaultConfig()
+	config.OversizeHandling = OversizeHandlingMode(99)
+
+	if err := ValidateConfig(config); err == nil {
+		t.Error("Expected error for invalid oversize handling mode")
+	}
+}
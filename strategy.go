@@ -42,14 +42,41 @@ type StrategyConfig struct {
 	MaxDepth   int  `json:"max_depth,omitempty"`   // 最大层级深度
 	MinDepth   int  `json:"min_depth,omitempty"`   // 最小层级深度
 	MergeEmpty bool `json:"merge_empty,omitempty"` // 是否合并空章节
+	Adaptive   bool `json:"adaptive,omitempty"`    // 是否按大小自适应：超大章节下钻到子章节，过小的相邻章节在父章节下合并
 
 	// 大小限制配置
-	MinChunkSize int `json:"min_chunk_size,omitempty"` // 最小块大小
-	MaxChunkSize int `json:"max_chunk_size,omitempty"` // 最大块大小
+	MinChunkSize int `json:"min_chunk_size,omitempty"` // 最小块大小（按分块器的 SizeUnit 计量）
+	MaxChunkSize int `json:"max_chunk_size,omitempty"` // 最大块大小（按分块器的 SizeUnit 计量）
 
 	// 内容过滤配置
 	IncludeTypes []string `json:"include_types,omitempty"` // 包含的内容类型
 	ExcludeTypes []string `json:"exclude_types,omitempty"` // 排除的内容类型
+
+	// 重叠配置（元素级和层级策略）
+	OverlapSize        int         `json:"overlap_size,omitempty"`         // 与相邻块共享的上下文大小，0表示不重叠
+	OverlapUnit        OverlapUnit `json:"overlap_unit,omitempty"`         // 重叠计量单位：chars、tokens、sentences
+	IncludeNextOverlap bool        `json:"include_next_overlap,omitempty"` // 是否同时包含下一个块的开头
+
+	// 打包配置（元素级策略）
+	PackSmallChunks   bool `json:"pack_small_chunks,omitempty"`   // 是否将相邻小块合并，而不是丢弃小于 MinChunkSize 的块
+	PackTargetSize    int  `json:"pack_target_size,omitempty"`    // 打包目标大小，0表示使用 MinChunkSize
+	PackBoundaryLevel int  `json:"pack_boundary_level,omitempty"` // 打包不跨越的标题层级（该层级及更高层级），0表示任何标题
+
+	// 窗口配置（固定大小策略）
+	WindowSize   int `json:"window_size,omitempty"`   // 窗口大小（按分块器的 SizeUnit 计量），0表示使用默认值
+	WindowStride int `json:"window_stride,omitempty"` // 窗口步长，小于窗口大小时相邻窗口重叠，0表示等于窗口大小
+
+	// 语义配置（语义策略）
+	SemanticUnit         SemanticUnit `json:"semantic_unit,omitempty"`         // 语义单元：sentences（默认）或 elements
+	BreakpointPercentile float64      `json:"breakpoint_percentile,omitempty"` // 相邻相似度处于该百分位及以下时断开，0表示使用默认值
+	SimilarityThreshold  float64      `json:"similarity_threshold,omitempty"`  // 相邻相似度不高于该值时断开，非 0 时优先于百分位
+	Embedder             Embedder     `json:"-"`                               // 嵌入器，nil 时使用 HashEmbedder
+
+	// 幻灯片配置（幻灯片策略）
+	SlideHeadingLevel int `json:"slide_heading_level,omitempty"` // 该层级及更高层级的标题也作为幻灯片边界，0表示只按分隔线拆分
+
+	// 选择规则（自动策略）
+	SelectionRules []StrategySelectionRule `json:"-"` // 优先于内置规则按顺序匹配的选择规则
 }
 
 // StrategyRegistry 策略注册器
@@ -214,15 +241,97 @@ func (sc *StrategyConfig) ValidateConfig() error {
 			WithContext("max_chunk_size", sc.MaxChunkSize)
 	}
 
-	// 验证内容类型配置
-	validTypes := map[string]bool{
-		"heading": true, "paragraph": true, "code": true,
-		"table": true, "list": true, "blockquote": true,
-		"thematic_break": true,
+	// 验证重叠配置
+	if sc.OverlapSize < 0 {
+		return NewChunkerError(ErrorTypeStrategyConfigInvalid, "重叠大小不能为负数", nil).
+			WithContext("function", "ValidateConfig").
+			WithContext("field", "OverlapSize").
+			WithContext("value", sc.OverlapSize)
+	}
+
+	if !isValidOverlapUnit(sc.OverlapUnit) {
+		return NewChunkerError(ErrorTypeStrategyConfigInvalid, "无效的重叠计量单位", nil).
+			WithContext("function", "ValidateConfig").
+			WithContext("field", "OverlapUnit").
+			WithContext("value", sc.OverlapUnit)
+	}
+
+	// 验证打包配置
+	if sc.PackTargetSize < 0 {
+		return NewChunkerError(ErrorTypeStrategyConfigInvalid, "打包目标大小不能为负数", nil).
+			WithContext("function", "ValidateConfig").
+			WithContext("field", "PackTargetSize").
+			WithContext("value", sc.PackTargetSize)
+	}
+
+	if sc.MaxChunkSize > 0 && sc.PackTargetSize > sc.MaxChunkSize {
+		return NewChunkerError(ErrorTypeStrategyConfigInvalid, "打包目标大小不能大于最大块大小", nil).
+			WithContext("function", "ValidateConfig").
+			WithContext("pack_target_size", sc.PackTargetSize).
+			WithContext("max_chunk_size", sc.MaxChunkSize)
+	}
+
+	if sc.PackBoundaryLevel < 0 || sc.PackBoundaryLevel > 6 {
+		return NewChunkerError(ErrorTypeStrategyConfigInvalid, "打包边界标题层级必须在0-6之间", nil).
+			WithContext("function", "ValidateConfig").
+			WithContext("field", "PackBoundaryLevel").
+			WithContext("value", sc.PackBoundaryLevel)
+	}
+
+	// 验证语义配置
+	if !isValidSemanticUnit(sc.SemanticUnit) {
+		return NewChunkerError(ErrorTypeStrategyConfigInvalid, "无效的语义单元", nil).
+			WithContext("function", "ValidateConfig").
+			WithContext("field", "SemanticUnit").
+			WithContext("value", sc.SemanticUnit)
 	}
 
+	if sc.BreakpointPercentile < 0 || sc.BreakpointPercentile > 100 {
+		return NewChunkerError(ErrorTypeStrategyConfigInvalid, "断点百分位必须在0-100之间", nil).
+			WithContext("function", "ValidateConfig").
+			WithContext("field", "BreakpointPercentile").
+			WithContext("value", sc.BreakpointPercentile)
+	}
+
+	if sc.SimilarityThreshold < -1 || sc.SimilarityThreshold > 1 {
+		return NewChunkerError(ErrorTypeStrategyConfigInvalid, "相似度阈值必须在-1到1之间", nil).
+			WithContext("function", "ValidateConfig").
+			WithContext("field", "SimilarityThreshold").
+			WithContext("value", sc.SimilarityThreshold)
+	}
+
+	if sc.SlideHeadingLevel < 0 || sc.SlideHeadingLevel > 6 {
+		return NewChunkerError(ErrorTypeStrategyConfigInvalid, "幻灯片边界标题层级必须在0-6之间", nil).
+			WithContext("function", "ValidateConfig").
+			WithContext("field", "SlideHeadingLevel").
+			WithContext("value", sc.SlideHeadingLevel)
+	}
+
+	// 验证选择规则
+	for _, rule := range sc.SelectionRules {
+		if err := rule.Validate(); err != nil {
+			return err
+		}
+	}
+
+	// 验证窗口配置
+	if sc.WindowSize < 0 {
+		return NewChunkerError(ErrorTypeStrategyConfigInvalid, "窗口大小不能为负数", nil).
+			WithContext("function", "ValidateConfig").
+			WithContext("field", "WindowSize").
+			WithContext("value", sc.WindowSize)
+	}
+
+	if sc.WindowStride < 0 {
+		return NewChunkerError(ErrorTypeStrategyConfigInvalid, "窗口步长不能为负数", nil).
+			WithContext("function", "ValidateConfig").
+			WithContext("field", "WindowStride").
+			WithContext("value", sc.WindowStride)
+	}
+
+	// 验证内容类型配置
 	for _, includeType := range sc.IncludeTypes {
-		if !validTypes[includeType] {
+		if !isValidChunkType(includeType) {
 			return NewChunkerError(ErrorTypeStrategyConfigInvalid, "无效的包含内容类型", nil).
 				WithContext("function", "ValidateConfig").
 				WithContext("field", "IncludeTypes").
@@ -231,7 +340,7 @@ func (sc *StrategyConfig) ValidateConfig() error {
 	}
 
 	for _, excludeType := range sc.ExcludeTypes {
-		if !validTypes[excludeType] {
+		if !isValidChunkType(excludeType) {
 			return NewChunkerError(ErrorTypeStrategyConfigInvalid, "无效的排除内容类型", nil).
 				WithContext("function", "ValidateConfig").
 				WithContext("field", "ExcludeTypes").
@@ -253,8 +362,32 @@ func (sc *StrategyConfig) Clone() *StrategyConfig {
 		MaxDepth:     sc.MaxDepth,
 		MinDepth:     sc.MinDepth,
 		MergeEmpty:   sc.MergeEmpty,
+		Adaptive:     sc.Adaptive,
 		MinChunkSize: sc.MinChunkSize,
 		MaxChunkSize: sc.MaxChunkSize,
+
+		OverlapSize:        sc.OverlapSize,
+		OverlapUnit:        sc.OverlapUnit,
+		IncludeNextOverlap: sc.IncludeNextOverlap,
+
+		PackSmallChunks:   sc.PackSmallChunks,
+		PackTargetSize:    sc.PackTargetSize,
+		PackBoundaryLevel: sc.PackBoundaryLevel,
+
+		WindowSize:   sc.WindowSize,
+		WindowStride: sc.WindowStride,
+
+		SemanticUnit:         sc.SemanticUnit,
+		BreakpointPercentile: sc.BreakpointPercentile,
+		SimilarityThreshold:  sc.SimilarityThreshold,
+		Embedder:             sc.Embedder,
+
+		SlideHeadingLevel: sc.SlideHeadingLevel,
+	}
+
+	if sc.SelectionRules != nil {
+		clone.SelectionRules = make([]StrategySelectionRule, len(sc.SelectionRules))
+		copy(clone.SelectionRules, sc.SelectionRules)
 	}
 
 	// 深拷贝参数映射
@@ -365,6 +498,22 @@ func HierarchicalConfigAdvanced(maxDepth, minDepth int, mergeEmpty bool) *Strate
 	return config
 }
 
+// HierarchicalConfigAdaptive 创建按大小自适应的层级策略配置
+// 超过 maxSize 的章节下钻到子章节，小于 minSize 的相邻章节在父章节下合并
+func HierarchicalConfigAdaptive(minSize, maxSize int) *StrategyConfig {
+	config := HierarchicalConfig(0)
+	config.Adaptive = true
+	config.MinChunkSize = minSize
+	config.MaxChunkSize = maxSize
+
+	// 添加到参数映射中
+	config.Parameters["adaptive"] = true
+	config.Parameters["min_chunk_size"] = minSize
+	config.Parameters["max_chunk_size"] = maxSize
+
+	return config
+}
+
 // HierarchicalConfigWithSize 创建带大小限制的层级策略配置
 func HierarchicalConfigWithSize(maxDepth, minSize, maxSize int) *StrategyConfig {
 	config := HierarchicalConfig(maxDepth)
@@ -378,6 +527,47 @@ func HierarchicalConfigWithSize(maxDepth, minSize, maxSize int) *StrategyConfig
 	return config
 }
 
+// ElementLevelConfigWithOverlap 创建带相邻块重叠的元素级策略配置
+func ElementLevelConfigWithOverlap(overlapSize int, unit OverlapUnit) *StrategyConfig {
+	config := DefaultStrategyConfig("element-level")
+	config.OverlapSize = overlapSize
+	config.OverlapUnit = unit
+
+	// 添加到参数映射中
+	config.Parameters["overlap_size"] = overlapSize
+	config.Parameters["overlap_unit"] = string(unit)
+
+	return config
+}
+
+// ElementLevelConfigWithPacking 创建将相邻小块打包到目标大小的元素级策略配置
+func ElementLevelConfigWithPacking(targetSize, boundaryLevel int) *StrategyConfig {
+	config := DefaultStrategyConfig("element-level")
+	config.PackSmallChunks = true
+	config.PackTargetSize = targetSize
+	config.PackBoundaryLevel = boundaryLevel
+
+	// 添加到参数映射中
+	config.Parameters["pack_small_chunks"] = true
+	config.Parameters["pack_target_size"] = targetSize
+	config.Parameters["pack_boundary_level"] = boundaryLevel
+
+	return config
+}
+
+// HierarchicalConfigWithOverlap 创建带相邻块重叠的层级策略配置
+func HierarchicalConfigWithOverlap(maxDepth, overlapSize int, unit OverlapUnit) *StrategyConfig {
+	config := HierarchicalConfig(maxDepth)
+	config.OverlapSize = overlapSize
+	config.OverlapUnit = unit
+
+	// 添加到参数映射中
+	config.Parameters["overlap_size"] = overlapSize
+	config.Parameters["overlap_unit"] = string(unit)
+
+	return config
+}
+
 // DocumentLevelConfigWithSize 创建带大小限制的文档级策略配置
 func DocumentLevelConfigWithSize(minSize, maxSize int) *StrategyConfig {
 	config := DefaultStrategyConfig("document-level")
@@ -391,6 +581,74 @@ func DocumentLevelConfigWithSize(minSize, maxSize int) *StrategyConfig {
 	return config
 }
 
+// FixedSizeConfig 创建固定大小策略配置
+// stride 小于 windowSize 时相邻窗口重叠，为 0 时等于 windowSize
+func FixedSizeConfig(windowSize, stride int) *StrategyConfig {
+	config := DefaultStrategyConfig("fixed-size")
+	config.WindowSize = windowSize
+	config.WindowStride = stride
+
+	// 添加到参数映射中
+	config.Parameters["window_size"] = windowSize
+	config.Parameters["window_stride"] = stride
+
+	return config
+}
+
+// SemanticConfig 创建语义策略配置，使用句子单元、默认断点百分位和哈希词袋嵌入器
+func SemanticConfig(minSize, maxSize int) *StrategyConfig {
+	config := DefaultStrategyConfig("semantic")
+	config.MinChunkSize = minSize
+	config.MaxChunkSize = maxSize
+	config.SemanticUnit = SemanticUnitSentences
+	config.BreakpointPercentile = DefaultBreakpointPercentile
+
+	// 添加到参数映射中
+	config.Parameters["min_chunk_size"] = minSize
+	config.Parameters["max_chunk_size"] = maxSize
+	config.Parameters["semantic_unit"] = string(SemanticUnitSentences)
+	config.Parameters["breakpoint_percentile"] = DefaultBreakpointPercentile
+
+	return config
+}
+
+// SemanticConfigWithEmbedder 创建使用指定嵌入器和相似度阈值的语义策略配置
+// threshold 为 0 时按默认百分位确定断点
+func SemanticConfigWithEmbedder(embedder Embedder, threshold float64, minSize, maxSize int) *StrategyConfig {
+	config := SemanticConfig(minSize, maxSize)
+	config.Embedder = embedder
+	config.SimilarityThreshold = threshold
+
+	config.Parameters["similarity_threshold"] = threshold
+
+	return config
+}
+
+// AutoConfig 创建自动策略配置，rules 按顺序优先于内置选择规则匹配
+func AutoConfig(rules ...StrategySelectionRule) *StrategyConfig {
+	config := DefaultStrategyConfig("auto")
+	if len(rules) > 0 {
+		config.SelectionRules = rules
+	}
+	return config
+}
+
+// QAConfig 创建问答对策略配置
+func QAConfig() *StrategyConfig {
+	return DefaultStrategyConfig("qa")
+}
+
+// SlidesConfig 创建幻灯片策略配置，headingLevel 大于 0 时该层级及更高层级的标题也作为幻灯片边界
+func SlidesConfig(headingLevel int) *StrategyConfig {
+	config := DefaultStrategyConfig("slides")
+	config.SlideHeadingLevel = headingLevel
+
+	// 添加到参数映射中
+	config.Parameters["slide_heading_level"] = headingLevel
+
+	return config
+}
+
 // ValidateAndFillDefaults 验证策略配置并填充默认值
 func ValidateAndFillDefaults(config *StrategyConfig) error {
 	if config == nil {
@@ -417,6 +675,10 @@ func ValidateAndFillDefaults(config *StrategyConfig) error {
 		return validateAndFillElementLevelDefaults(config)
 	case "document-level":
 		return validateAndFillDocumentLevelDefaults(config)
+	case "fixed-size":
+		return validateAndFillFixedSizeDefaults(config)
+	case "semantic":
+		return validateAndFillSemanticDefaults(config)
 	default:
 		// 对于自定义策略，只进行基本验证
 		return config.ValidateConfig()
@@ -501,6 +763,55 @@ func validateAndFillDocumentLevelDefaults(config *StrategyConfig) error {
 	return config.ValidateConfig()
 }
 
+// validateAndFillFixedSizeDefaults 验证并填充固定大小策略的默认值
+func validateAndFillFixedSizeDefaults(config *StrategyConfig) error {
+	// 固定大小策略按窗口切分，不使用层级配置
+	if config.MaxDepth > 0 || config.MinDepth > 0 {
+		return NewChunkerError(ErrorTypeStrategyConfigInvalid, "固定大小策略不支持层级深度配置", nil).
+			WithContext("function", "validateAndFillFixedSizeDefaults").
+			WithContext("max_depth", config.MaxDepth).
+			WithContext("min_depth", config.MinDepth)
+	}
+
+	if config.WindowSize > 0 && config.WindowStride > config.WindowSize {
+		return NewChunkerError(ErrorTypeStrategyConfigInvalid, "窗口步长不能大于窗口大小", nil).
+			WithContext("function", "validateAndFillFixedSizeDefaults").
+			WithContext("window_size", config.WindowSize).
+			WithContext("window_stride", config.WindowStride)
+	}
+
+	// 填充参数映射
+	config.Parameters["window_size"] = config.WindowSize
+	config.Parameters["window_stride"] = config.WindowStride
+
+	return config.ValidateConfig()
+}
+
+// validateAndFillSemanticDefaults 验证并填充语义策略的默认值
+func validateAndFillSemanticDefaults(config *StrategyConfig) error {
+	// 语义策略按相似度断开，不使用层级配置
+	if config.MaxDepth > 0 || config.MinDepth > 0 {
+		return NewChunkerError(ErrorTypeStrategyConfigInvalid, "语义策略不支持层级深度配置", nil).
+			WithContext("function", "validateAndFillSemanticDefaults").
+			WithContext("max_depth", config.MaxDepth).
+			WithContext("min_depth", config.MinDepth)
+	}
+
+	if config.SemanticUnit == "" {
+		config.SemanticUnit = SemanticUnitSentences
+	}
+	if config.BreakpointPercentile == 0 {
+		config.BreakpointPercentile = DefaultBreakpointPercentile
+	}
+
+	// 填充参数映射
+	config.Parameters["semantic_unit"] = string(config.SemanticUnit)
+	config.Parameters["breakpoint_percentile"] = config.BreakpointPercentile
+	config.Parameters["similarity_threshold"] = config.SimilarityThreshold
+
+	return config.ValidateConfig()
+}
+
 // CreateConfigFromParameters 从参数映射创建策略配置
 func CreateConfigFromParameters(strategyName string, params map[string]any) (*StrategyConfig, error) {
 	if strategyName == "" {
@@ -529,6 +840,12 @@ func CreateConfigFromParameters(strategyName string, params map[string]any) (*St
 		}
 	}
 
+	if adaptive, ok := params["adaptive"]; ok {
+		if enabled, ok := adaptive.(bool); ok {
+			config.Adaptive = enabled
+		}
+	}
+
 	if minSize, ok := params["min_chunk_size"]; ok {
 		if size, ok := minSize.(int); ok {
 			config.MinChunkSize = size
@@ -541,6 +858,54 @@ func CreateConfigFromParameters(strategyName string, params map[string]any) (*St
 		}
 	}
 
+	if windowSize, ok := params["window_size"]; ok {
+		if size, ok := windowSize.(int); ok {
+			config.WindowSize = size
+		}
+	}
+
+	if windowStride, ok := params["window_stride"]; ok {
+		if stride, ok := windowStride.(int); ok {
+			config.WindowStride = stride
+		}
+	}
+
+	if slideHeadingLevel, ok := params["slide_heading_level"]; ok {
+		if level, ok := slideHeadingLevel.(int); ok {
+			config.SlideHeadingLevel = level
+		}
+	}
+
+	if semanticUnit, ok := params["semantic_unit"]; ok {
+		if unit, ok := semanticUnit.(string); ok {
+			config.SemanticUnit = SemanticUnit(unit)
+		}
+	}
+
+	if breakpointPercentile, ok := params["breakpoint_percentile"]; ok {
+		if percentile, ok := breakpointPercentile.(float64); ok {
+			config.BreakpointPercentile = percentile
+		}
+	}
+
+	if similarityThreshold, ok := params["similarity_threshold"]; ok {
+		if threshold, ok := similarityThreshold.(float64); ok {
+			config.SimilarityThreshold = threshold
+		}
+	}
+
+	if embedderParam, ok := params["embedder"]; ok {
+		if embedder, ok := embedderParam.(Embedder); ok {
+			config.Embedder = embedder
+		}
+	}
+
+	if selectionRules, ok := params["selection_rules"]; ok {
+		if rules, ok := selectionRules.([]StrategySelectionRule); ok {
+			config.SelectionRules = rules
+		}
+	}
+
 	if includeTypes, ok := params["include_types"]; ok {
 		if types, ok := includeTypes.([]string); ok {
 			config.IncludeTypes = types
@@ -600,12 +965,68 @@ func MergeConfigs(base, override *StrategyConfig) (*StrategyConfig, error) {
 	if override.MaxChunkSize != 0 {
 		merged.MaxChunkSize = override.MaxChunkSize
 	}
+	if override.WindowSize != 0 {
+		merged.WindowSize = override.WindowSize
+	}
+	if override.WindowStride != 0 {
+		merged.WindowStride = override.WindowStride
+	}
+	if override.SlideHeadingLevel != 0 {
+		merged.SlideHeadingLevel = override.SlideHeadingLevel
+	}
+	if override.SemanticUnit != "" {
+		merged.SemanticUnit = override.SemanticUnit
+	}
+	if override.BreakpointPercentile != 0 {
+		merged.BreakpointPercentile = override.BreakpointPercentile
+	}
+	if override.SimilarityThreshold != 0 {
+		merged.SimilarityThreshold = override.SimilarityThreshold
+	}
+	if override.Embedder != nil {
+		merged.Embedder = override.Embedder
+	}
+	if override.OverlapSize != 0 {
+		merged.OverlapSize = override.OverlapSize
+	}
+	if override.OverlapUnit != "" {
+		merged.OverlapUnit = override.OverlapUnit
+	}
+	if override.PackTargetSize != 0 {
+		merged.PackTargetSize = override.PackTargetSize
+	}
+	if override.PackBoundaryLevel != 0 {
+		merged.PackBoundaryLevel = override.PackBoundaryLevel
+	}
+	if override.SelectionRules != nil {
+		merged.SelectionRules = make([]StrategySelectionRule, len(override.SelectionRules))
+		copy(merged.SelectionRules, override.SelectionRules)
+	}
 	// 对于布尔值，我们需要检查参数映射来确定是否应该覆盖
 	if mergeEmptyParam, exists := override.Parameters["merge_empty"]; exists {
 		if mergeEmpty, ok := mergeEmptyParam.(bool); ok {
 			merged.MergeEmpty = mergeEmpty
 		}
 	}
+	if adaptiveParam, exists := override.Parameters["adaptive"]; exists {
+		if adaptive, ok := adaptiveParam.(bool); ok {
+			merged.Adaptive = adaptive
+		}
+	}
+	if includeNextParam, exists := override.Parameters["include_next_overlap"]; exists {
+		if includeNext, ok := includeNextParam.(bool); ok {
+			merged.IncludeNextOverlap = includeNext
+		}
+	} else if override.IncludeNextOverlap {
+		merged.IncludeNextOverlap = true
+	}
+	if packParam, exists := override.Parameters["pack_small_chunks"]; exists {
+		if pack, ok := packParam.(bool); ok {
+			merged.PackSmallChunks = pack
+		}
+	} else if override.PackSmallChunks {
+		merged.PackSmallChunks = true
+	}
 	if override.IncludeTypes != nil {
 		merged.IncludeTypes = make([]string, len(override.IncludeTypes))
 		copy(merged.IncludeTypes, override.IncludeTypes)
@@ -882,7 +1303,7 @@ func (s *ElementLevelStrategy) ChunkDocument(doc ast.Node, source []byte, chunke
 		// 这保持了与当前分块行为完全一致的逻辑
 		if chunk := chunker.processNode(child, chunkID); chunk != nil {
 			// 应用策略特定的过滤和处理
-			if s.shouldIncludeChunk(chunk) {
+			if s.shouldIncludeChunkWithChunker(chunk, chunker) {
 				// 添加策略标识到元数据
 				if chunk.Metadata == nil {
 					chunk.Metadata = make(map[string]string)
@@ -894,7 +1315,14 @@ func (s *ElementLevelStrategy) ChunkDocument(doc ast.Node, source []byte, chunke
 		}
 	}
 
-	return chunks, nil
+	// 合并相邻的小块
+	chunks = packSmallChunks(chunks, s.config, chunker)
+
+	// 为相邻块添加重叠上下文
+	chunks = applyChunkOverlap(chunks, s.config, chunker)
+
+	// 填充父子和前后导航关系
+	return linkChunks(chunks), nil
 }
 
 // ValidateConfig 验证策略特定的配置
@@ -921,8 +1349,13 @@ func (s *ElementLevelStrategy) Clone() ChunkingStrategy {
 	}
 }
 
-// shouldIncludeChunk 判断是否应该包含指定的块
+// shouldIncludeChunk 判断是否应该包含指定的块（按字节计算大小）
 func (s *ElementLevelStrategy) shouldIncludeChunk(chunk *Chunk) bool {
+	return s.shouldIncludeChunkWithChunker(chunk, nil)
+}
+
+// shouldIncludeChunkWithChunker 判断是否应该包含指定的块，大小按分块器配置的计量单位计算
+func (s *ElementLevelStrategy) shouldIncludeChunkWithChunker(chunk *Chunk, chunker *MarkdownChunker) bool {
 	if chunk == nil {
 		return false
 	}
@@ -944,10 +1377,10 @@ func (s *ElementLevelStrategy) shouldIncludeChunk(chunk *Chunk) bool {
 
 	// 检查块大小限制
 	if s.config != nil {
-		contentLength := len(chunk.Content)
+		contentLength := measureChunkSize(chunker, chunk.Content)
 
-		// 检查最小块大小
-		if s.config.MinChunkSize > 0 && contentLength < s.config.MinChunkSize {
+		// 检查最小块大小（打包模式下小块会被合并而不是丢弃）
+		if !s.config.PackSmallChunks && s.config.MinChunkSize > 0 && contentLength < s.config.MinChunkSize {
 			return false
 		}
 
@@ -989,6 +1422,7 @@ type HierarchicalChunk struct {
 	Children []*HierarchicalChunk `json:"children"` // 子块列表
 	Parent   *HierarchicalChunk   `json:"-"`        // 父块引用（不序列化）
 	Level    int                  `json:"level"`    // 层级深度
+	Virtual  bool                 `json:"virtual"`  // 是否为补齐跳跃层级或包含前言内容的虚拟节点
 }
 
 // HierarchicalStrategy 层级分块策略
@@ -1050,8 +1484,19 @@ func (s *HierarchicalStrategy) ChunkDocument(doc ast.Node, source []byte, chunke
 	// 2. 构建层级结构
 	hierarchicalChunks := s.buildHierarchy(baseChunks)
 
-	// 3. 根据配置扁平化为目标层级的块
-	return s.flattenToTargetLevel(hierarchicalChunks), nil
+	// 3. 根据配置扁平化为目标层级的块，自适应模式按大小窗口选择每个章节的层级
+	var chunks []Chunk
+	if s.config.Adaptive {
+		chunks = s.flattenAdaptive(hierarchicalChunks, chunker)
+	} else {
+		chunks = s.flattenToTargetLevel(hierarchicalChunks)
+	}
+
+	// 4. 为相邻块添加重叠上下文
+	chunks = applyChunkOverlap(chunks, s.config, chunker)
+
+	// 5. 填充父子和前后导航关系
+	return linkChunks(chunks), nil
 }
 
 // ValidateConfig 验证策略特定的配置
@@ -1144,6 +1589,7 @@ func (s *HierarchicalStrategy) buildHierarchy(chunks []Chunk) []*HierarchicalChu
 							},
 							Children: []*HierarchicalChunk{},
 							Level:    len(stack),
+							Virtual:  true,
 						}
 
 						// 添加到父节点
@@ -1202,6 +1648,7 @@ func (s *HierarchicalStrategy) buildHierarchy(chunks []Chunk) []*HierarchicalChu
 						},
 						Children: []*HierarchicalChunk{},
 						Level:    0,
+						Virtual:  true,
 					}
 					root = append(root, virtualRoot)
 				}
@@ -1484,17 +1931,24 @@ func (s *HierarchicalStrategy) createMergedChunk(hChunk *HierarchicalChunk, id i
 		metadata["child_types"] = strings.Join(typeList, ",")
 	}
 
+	// 非虚拟块的章节信息来自其标题，元数据已在上面复制
+	var sectionPath []string
+	if !s.isVirtualChunk(hChunk) {
+		sectionPath = hChunk.Chunk.SectionPath
+	}
+
 	return &Chunk{
-		ID:       id,
-		Type:     chunkType,
-		Content:  mergedContent,
-		Text:     mergedText,
-		Level:    level,
-		Metadata: metadata,
-		Position: position,
-		Links:    allLinks,
-		Images:   allImages,
-		Hash:     hash,
+		ID:          id,
+		Type:        chunkType,
+		Content:     mergedContent,
+		Text:        mergedText,
+		Level:       level,
+		Metadata:    metadata,
+		Position:    position,
+		Links:       allLinks,
+		Images:      allImages,
+		Hash:        hash,
+		SectionPath: sectionPath,
 	}
 }
 
@@ -1876,7 +2330,8 @@ func (c *ContentTypeCondition) Validate() error {
 	validTypes := map[string]bool{
 		"heading": true, "paragraph": true, "code": true,
 		"table": true, "list": true, "blockquote": true,
-		"thematic_break": true, "text": true, "emphasis": true,
+		"thematic_break": true, "html": true, "footnote": true, "math": true,
+		"admonition": true, "text": true, "emphasis": true,
 		"link": true, "image": true,
 	}
 
@@ -2364,6 +2819,14 @@ func getNodeType(node ast.Node) string {
 		return "blockquote"
 	case *ast.ThematicBreak:
 		return "thematic_break"
+	case *ast.HTMLBlock:
+		return "html"
+	case *extast.FootnoteList:
+		return "footnote"
+	case *mathBlock:
+		return "math"
+	case *Admonition:
+		return "admonition"
 	case *ast.Text:
 		return "text"
 	case *ast.Emphasis:
@@ -2942,7 +3405,8 @@ func (s *CustomStrategy) ChunkDocument(doc ast.Node, source []byte, chunker *Mar
 		}
 	}
 
-	return chunks, nil
+	// 填充父子和前后导航关系
+	return linkChunks(chunks), nil
 }
 
 // processNodeWithRules 使用规则处理节点
@@ -3332,7 +3796,7 @@ func (s *DocumentLevelStrategy) ChunkDocument(doc ast.Node, source []byte, chunk
 		Hash:     hash,
 	}
 
-	return []Chunk{chunk}, nil
+	return linkChunks([]Chunk{chunk}), nil
 }
 
 // ValidateConfig 验证策略特定的配置
@@ -3451,9 +3915,22 @@ func (s *DocumentLevelStrategy) extractDocumentMetadata(doc ast.Node, source []b
 	complexity := s.calculateDocumentComplexity(headingCount, codeBlockCount, tableCount, listCount)
 	metadata["document_complexity"] = complexity
 
+	// 前置元数据
+	s.addFrontMatterMetadata(metadata, chunker)
+
 	return metadata
 }
 
+// addFrontMatterMetadata 将文档的全部前置元数据添加到元数据中
+func (s *DocumentLevelStrategy) addFrontMatterMetadata(metadata map[string]string, chunker *MarkdownChunker) {
+	if chunker == nil || chunker.frontMatter == nil {
+		return
+	}
+
+	metadata["front_matter_format"] = string(chunker.frontMatter.Format)
+	maps.Copy(metadata, frontMatterMetadata(chunker.frontMatter, nil))
+}
+
 // extractLinksAndImages 提取文档中的所有链接和图片
 func (s *DocumentLevelStrategy) extractLinksAndImages(doc ast.Node, source []byte) ([]Link, []Image) {
 	var links []Link
@@ -3666,7 +4143,7 @@ func (s *DocumentLevelStrategy) chunkLargeDocument(doc ast.Node, source []byte,
 	// 报告处理完成
 	progressCallback(100, "大文档处理完成")
 
-	return []Chunk{chunk}, nil
+	return linkChunks([]Chunk{chunk}), nil
 }
 
 // createProgressCallback 创建进度回调函数
@@ -3808,6 +4285,9 @@ func (s *DocumentLevelStrategy) extractMetadataStreamingly(doc ast.Node, source
 	complexity := s.calculateDocumentComplexity(headingCount, codeBlockCount, tableCount, listCount)
 	metadata["document_complexity"] = complexity
 
+	// 前置元数据
+	s.addFrontMatterMetadata(metadata, chunker)
+
 	return metadata, nil
 }
 
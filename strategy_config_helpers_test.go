@@ -440,6 +440,78 @@ func TestMergeConfigs(t *testing.T) {
 				}
 			},
 		},
+		{
+			name: "merge overlap settings",
+			base: ElementLevelConfigWithOverlap(10, OverlapUnitChars),
+			override: &StrategyConfig{
+				Name:               "element-level",
+				OverlapSize:        2,
+				OverlapUnit:        OverlapUnitSentences,
+				IncludeNextOverlap: true,
+				Parameters:         map[string]interface{}{},
+			},
+			expectError: false,
+			validate: func(t *testing.T, config *StrategyConfig) {
+				if config.OverlapSize != 2 {
+					t.Errorf("期望重叠大小为 2，实际为 %d", config.OverlapSize)
+				}
+				if config.OverlapUnit != OverlapUnitSentences {
+					t.Errorf("期望重叠单位为 sentences，实际为 %s", config.OverlapUnit)
+				}
+				if !config.IncludeNextOverlap {
+					t.Error("期望包含下一个块的重叠")
+				}
+			},
+		},
+		{
+			name: "keep base overlap when override omits it",
+			base: ElementLevelConfigWithOverlap(10, OverlapUnitTokens),
+			override: &StrategyConfig{
+				Name:         "element-level",
+				MinChunkSize: 5,
+				Parameters:   map[string]interface{}{},
+			},
+			expectError: false,
+			validate: func(t *testing.T, config *StrategyConfig) {
+				if config.OverlapSize != 10 || config.OverlapUnit != OverlapUnitTokens {
+					t.Errorf("期望保留基础重叠配置，实际为 %d %s", config.OverlapSize, config.OverlapUnit)
+				}
+			},
+		},
+		{
+			name:        "merge packing settings",
+			base:        ElementLevelConfig(),
+			override:    ElementLevelConfigWithPacking(300, 2),
+			expectError: false,
+			validate: func(t *testing.T, config *StrategyConfig) {
+				if !config.PackSmallChunks {
+					t.Error("期望启用小块打包")
+				}
+				if config.PackTargetSize != 300 {
+					t.Errorf("期望打包目标大小为 300，实际为 %d", config.PackTargetSize)
+				}
+				if config.PackBoundaryLevel != 2 {
+					t.Errorf("期望打包边界层级为 2，实际为 %d", config.PackBoundaryLevel)
+				}
+			},
+		},
+		{
+			name: "disable packing through parameters",
+			base: ElementLevelConfigWithPacking(300, 2),
+			override: &StrategyConfig{
+				Name:       "element-level",
+				Parameters: map[string]interface{}{"pack_small_chunks": false},
+			},
+			expectError: false,
+			validate: func(t *testing.T, config *StrategyConfig) {
+				if config.PackSmallChunks {
+					t.Error("期望通过参数关闭小块打包")
+				}
+				if config.PackTargetSize != 300 {
+					t.Errorf("期望保留打包目标大小 300，实际为 %d", config.PackTargetSize)
+				}
+			},
+		},
 	}
 
 	for _, tt := range tests {
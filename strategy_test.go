@@ -2,6 +2,7 @@ package markdownchunker
 
 import (
 	"fmt"
+	"slices"
 	"testing"
 
 	"github.com/yuin/goldmark/ast"
@@ -274,6 +275,84 @@ func TestStrategyRegistry(t *testing.T) {
 	})
 }
 
+// chunkWithStrategyConfig 使用指定的策略配置对文档分块
+func chunkWithStrategyConfig(t *testing.T, strategyConfig *StrategyConfig, markdown string) []Chunk {
+	t.Helper()
+
+	config := DefaultConfig()
+	config.ChunkingStrategy = strategyConfig
+
+	chunker := NewMarkdownChunkerWithConfig(config)
+	chunks, err := chunker.ChunkDocument([]byte(markdown))
+	if err != nil {
+		t.Fatalf("ChunkDocument() error = %v", err)
+	}
+	return chunks
+}
+
+// chunksOfType 返回指定类型的块
+func chunksOfType(chunks []Chunk, typ string) []Chunk {
+	var result []Chunk
+	for _, chunk := range chunks {
+		if chunk.Type == typ {
+			result = append(result, chunk)
+		}
+	}
+	return result
+}
+
+// assertKeptWithEnabledTypes 检查 EnabledTypes 未列出策略合成的块类型时仍保留这些块，且该类型可以显式配置
+func assertKeptWithEnabledTypes(t *testing.T, strategyConfig *StrategyConfig, markdown, chunkType string) {
+	t.Helper()
+
+	config := DefaultConfig()
+	config.ChunkingStrategy = strategyConfig
+	config.EnabledTypes = map[string]bool{"heading": true, "paragraph": true}
+
+	chunks, err := NewMarkdownChunkerWithConfig(config).ChunkDocument([]byte(markdown))
+	if err != nil {
+		t.Fatalf("ChunkDocument() error = %v", err)
+	}
+	kept := 0
+	for _, chunk := range chunks {
+		if chunk.Type == chunkType {
+			kept++
+		}
+	}
+	if kept == 0 {
+		t.Errorf("Expected %s chunks to be kept when EnabledTypes does not mention them, got %d chunks", chunkType, len(chunks))
+	}
+
+	config.EnabledTypes = map[string]bool{chunkType: true}
+	if err := ValidateConfig(config); err != nil {
+		t.Errorf("Expected %s to be a valid enabled type, got %v", chunkType, err)
+	}
+}
+
+// TestRegisteredStrategies 测试内置策略均已注册并可作为当前策略
+func TestRegisteredStrategies(t *testing.T) {
+	builtin := []string{
+		"element-level", "hierarchical", "document-level", "fixed-size",
+		"semantic", "auto", "qa", "slides",
+	}
+	available := NewMarkdownChunker().GetAvailableStrategies()
+	for _, name := range builtin {
+		if !slices.Contains(available, name) {
+			t.Errorf("Expected %s strategy to be registered, got %v", name, available)
+		}
+	}
+
+	for _, name := range available {
+		t.Run(name, func(t *testing.T) {
+			chunker := NewMarkdownChunkerWithStrategy(name)
+			current, _ := chunker.GetCurrentStrategy()
+			if current != name {
+				t.Errorf("Expected current strategy %s, got %s", name, current)
+			}
+		})
+	}
+}
+
 // TestStrategyConfig 测试策略配置
 func TestStrategyConfig(t *testing.T) {
 	t.Run("验证有效配置", func(t *testing.T) {
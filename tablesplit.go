@@ -0,0 +1,245 @@
+package markdownchunker
+
+import (
+	"fmt"
+	"maps"
+	"regexp"
+	"strings"
+
+	extast "github.com/yuin/goldmark/extension/ast"
+	"github.com/yuin/goldmark/text"
+)
+
+// tableDelimiterPattern 匹配表格的对齐分隔行，如 | --- | :---: | ---: |
+var tableDelimiterPattern = regexp.MustCompile(`^\s*\|?\s*:?-+:?\s*(\|\s*:?-+:?\s*)*\|?\s*$`)
+
+// markdownTable 解析后的 GFM 表格
+type markdownTable struct {
+	header    string   // 表头行
+	delimiter string   // 对齐分隔行
+	rows      []string // 数据行
+}
+
+// tableFragment 重新解析后的表格片段信息
+type tableFragment struct {
+	info   *TableInfo
+	text   string
+	links  []Link
+	images []Image
+}
+
+// parseMarkdownTable 解析表格的原始内容
+func parseMarkdownTable(content string) (*markdownTable, bool) {
+	lines := strings.Split(strings.TrimRight(content, "\n"), "\n")
+	if len(lines) < 2 || !strings.Contains(lines[0], "|") || !tableDelimiterPattern.MatchString(lines[1]) {
+		return nil, false
+	}
+
+	table := &markdownTable{
+		header:    lines[0],
+		delimiter: lines[1],
+	}
+	for _, line := range lines[2:] {
+		if strings.TrimSpace(line) != "" {
+			table.rows = append(table.rows, line)
+		}
+	}
+
+	return table, true
+}
+
+// render 将指定数据行区间与表头、分隔行组合为完整的表格
+func (t *markdownTable) render(r lineRange) string {
+	lines := make([]string, 0, r.end-r.start+2)
+	lines = append(lines, t.header, t.delimiter)
+	lines = append(lines, t.rows[r.start:r.end]...)
+	return strings.Join(lines, "\n")
+}
+
+// shouldSplitTableChunk 判断表格是否需要按行拆分
+func (c *MarkdownChunker) shouldSplitTableChunk(chunk Chunk) bool {
+	if c.config == nil || !c.config.SplitTables || chunk.Type != "table" {
+		return false
+	}
+	limit := c.tableSplitLimit(chunk)
+	return limit > 0 && c.measureSize(chunk.Content) > limit
+}
+
+// tableSplitLimit 返回表格拆分的大小上限
+func (c *MarkdownChunker) tableSplitLimit(chunk Chunk) int {
+	if c.config == nil {
+		return 0
+	}
+	return c.splitLimit(chunk, c.config.TableMaxSize)
+}
+
+// splitTableChunk 将大表格按数据行分组拆分，每组都重复表头和对齐分隔行
+// 单个数据行不会被拆开，即使它本身超过大小上限
+func (c *MarkdownChunker) splitTableChunk(chunk Chunk, maxSize int) []Chunk {
+	table, ok := parseMarkdownTable(chunk.Content)
+	if !ok || maxSize <= 0 || len(table.rows) < 2 || c.measureSize(chunk.Content) <= maxSize {
+		return []Chunk{chunk}
+	}
+
+	// 贪心合并相邻数据行，直到达到大小上限
+	var ranges []lineRange
+	current := lineRange{0, 1}
+	for i := 1; i < len(table.rows); i++ {
+		if c.measureSize(table.render(lineRange{current.start, i + 1})) <= maxSize {
+			current.end = i + 1
+			continue
+		}
+		ranges = append(ranges, current)
+		current = lineRange{i, i + 1}
+	}
+	ranges = append(ranges, current)
+
+	if len(ranges) <= 1 {
+		return []Chunk{chunk}
+	}
+
+	tableStart, rowSpans, hasPositions := tableSourceSpans(chunk, len(table.rows), c.source)
+	var lineStarts []int
+	if hasPositions {
+		lineStarts = lineStartOffsets(c.source)
+	}
+
+	pieces := make([]Chunk, 0, len(ranges))
+	for i, r := range ranges {
+		content := table.render(r)
+		fragment := c.analyzeTableFragment(content)
+
+		// 第一个片段从表头开始，其余片段只覆盖自己的数据行
+		position := chunk.Position
+		if hasPositions {
+			start := rowSpans[r.start].start
+			if i == 0 {
+				start = tableStart
+			}
+			position = spanPosition(lineStarts, textSpan{start: start, end: rowSpans[r.end-1].end})
+		}
+
+		metadata := make(map[string]string, len(chunk.Metadata)+8)
+		maps.Copy(metadata, chunk.Metadata)
+		if fragment.info != nil {
+			// 原表格的错误信息不再适用于片段
+			delete(metadata, "errors")
+			delete(metadata, "alignments")
+			delete(metadata, "cell_types")
+			maps.Copy(metadata, fragment.info.GetTableMetadata())
+		}
+		metadata["row_start"] = fmt.Sprintf("%d", r.start+1)
+		metadata["row_end"] = fmt.Sprintf("%d", r.end)
+		metadata["table_part"] = fmt.Sprintf("%d", i+1)
+		metadata["table_part_total"] = fmt.Sprintf("%d", len(ranges))
+		metadata["original_chunk_id"] = fmt.Sprintf("%d", chunk.ID)
+		if hasPositions {
+			metadata["line_start"] = fmt.Sprintf("%d", position.StartLine)
+			metadata["line_end"] = fmt.Sprintf("%d", position.EndLine)
+			metadata["char_start"] = fmt.Sprintf("%d", position.StartCol)
+			metadata["char_end"] = fmt.Sprintf("%d", position.EndCol)
+		} else {
+			// 无法确定片段在源文档中的行号时不提供位置元数据
+			delete(metadata, "line_start")
+			delete(metadata, "line_end")
+			delete(metadata, "char_start")
+			delete(metadata, "char_end")
+		}
+
+		if fragment.info == nil {
+			fragment.text = strings.Join(strings.Fields(content), " ")
+			fragment.links = filterLinksInContent(chunk.Links, content)
+			fragment.images = filterImagesInContent(chunk.Images, content)
+		}
+
+		piece := Chunk{
+			ID:          chunk.ID,
+			Type:        chunk.Type,
+			Content:     content,
+			Text:        fragment.text,
+			Level:       chunk.Level,
+			Metadata:    metadata,
+			Position:    position,
+			Links:       fragment.links,
+			Images:      fragment.images,
+			Hash:        c.calculateContentHash(content),
+			SectionPath: chunk.SectionPath,
+		}
+		c.reattachFootnotes(&piece)
+		pieces = append(pieces, piece)
+	}
+
+	return pieces
+}
+
+// tableSourceSpans 返回表格在源内容中的起始偏移和每个数据行所在行的范围
+// 块没有表格节点或数据行与解析结果不一致时返回 false
+func tableSourceSpans(chunk Chunk, rowCount int, source []byte) (int, []textSpan, bool) {
+	table, ok := chunk.node.(*extast.Table)
+	if !ok {
+		return 0, nil, false
+	}
+	tableStart := nodeLineStart(table, source)
+	if tableStart < 0 {
+		return 0, nil, false
+	}
+
+	var spans []textSpan
+	for child := table.FirstChild(); child != nil; child = child.NextSibling() {
+		row, ok := child.(*extast.TableRow)
+		if !ok {
+			continue
+		}
+		start := nodeLineStart(row, source)
+		if start < 0 {
+			return 0, nil, false
+		}
+		end := start
+		for end < len(source) && source[end] != '\n' {
+			end++
+		}
+		for end > start && (source[end-1] == ' ' || source[end-1] == '\t' || source[end-1] == '\r') {
+			end--
+		}
+		spans = append(spans, textSpan{start: start, end: end})
+	}
+	if len(spans) != rowCount {
+		return 0, nil, false
+	}
+	return tableStart, spans, true
+}
+
+// analyzeTableFragment 重新解析表格片段，返回片段的表格信息、纯文本、链接和图片
+// 解析失败时返回的 info 为 nil
+func (c *MarkdownChunker) analyzeTableFragment(content string) tableFragment {
+	source := c.fragmentSource(content)
+	doc := c.md.Parser().Parse(text.NewReader(source))
+	if doc == nil {
+		return tableFragment{}
+	}
+
+	var table *extast.Table
+	for child := doc.FirstChild(); child != nil; child = child.NextSibling() {
+		if t, ok := child.(*extast.Table); ok {
+			table = t
+			break
+		}
+	}
+	if table == nil {
+		return tableFragment{}
+	}
+
+	// getNodeText 等方法依赖 c.source，临时切换到片段内容
+	originalSource := c.source
+	c.source = source
+	defer func() {
+		c.source = originalSource
+	}()
+
+	return tableFragment{
+		info:   NewAdvancedTableProcessor(source).ProcessTable(table),
+		text:   c.getNodeText(table),
+		links:  c.extractLinks(table),
+		images: c.extractImages(table),
+	}
+}
@@ -0,0 +1,167 @@
+package markdownchunker
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func buildTableMarkdown(rows int) string {
+	var buf strings.Builder
+	buf.WriteString("| Name | Score | Link |\n")
+	buf.WriteString("|:-----|------:|:----:|\n")
+	for i := 1; i <= rows; i++ {
+		buf.WriteString(fmt.Sprintf("| item%02d | %d | [l%d](https://example.com/%d) |\n", i, i*10, i, i))
+	}
+	return buf.String()
+}
+
+func TestTableSplit_DisabledByDefault(t *testing.T) {
+	chunker := NewMarkdownChunker()
+	chunks, err := chunker.ChunkDocument([]byte(buildTableMarkdown(10)))
+	if err != nil {
+		t.Fatalf("ChunkDocument() error = %v", err)
+	}
+
+	if len(chunksOfType(chunks, "table")) != 1 {
+		t.Errorf("Expected table to stay whole by default")
+	}
+}
+
+func TestTableSplit_RepeatsHeader(t *testing.T) {
+	config := DefaultConfig()
+	config.SplitTables = true
+	config.TableMaxSize = 200
+
+	chunker := NewMarkdownChunkerWithConfig(config)
+	chunks, err := chunker.ChunkDocument([]byte(buildTableMarkdown(10)))
+	if err != nil {
+		t.Fatalf("ChunkDocument() error = %v", err)
+	}
+
+	parts := chunksOfType(chunks, "table")
+	if len(parts) < 2 {
+		t.Fatalf("Expected table to be split, got %d parts", len(parts))
+	}
+
+	expectedStart := 1
+	for i, part := range parts {
+		lines := strings.Split(part.Content, "\n")
+		if lines[0] != "| Name | Score | Link |" || lines[1] != "|:-----|------:|:----:|" {
+			t.Errorf("Expected part %d to repeat header and alignment rows, got %q", i, part.Content)
+		}
+		if len(part.Content) > 200 {
+			t.Errorf("Expected part %d within size limit, got %d", i, len(part.Content))
+		}
+
+		rowCount := len(lines) - 2
+		if part.Metadata["row_start"] != fmt.Sprintf("%d", expectedStart) ||
+			part.Metadata["row_end"] != fmt.Sprintf("%d", expectedStart+rowCount-1) {
+			t.Errorf("Unexpected row range for part %d: %s-%s", i, part.Metadata["row_start"], part.Metadata["row_end"])
+		}
+		if part.Metadata["rows"] != fmt.Sprintf("%d", rowCount+1) {
+			t.Errorf("Expected recomputed rows metadata %d, got %s", rowCount+1, part.Metadata["rows"])
+		}
+		if part.Metadata["is_well_formed"] != "true" || part.Metadata["alignments"] != "left,right,center" {
+			t.Errorf("Expected each part to be a well-formed table, got %v", part.Metadata)
+		}
+		if len(part.Links) != rowCount {
+			t.Errorf("Expected links filtered to part %d, got %d", i, len(part.Links))
+		}
+		if !strings.Contains(part.Text, "Name") {
+			t.Errorf("Expected part text to include header, got %q", part.Text)
+		}
+		expectedStart += rowCount
+	}
+
+	if expectedStart != 11 {
+		t.Errorf("Expected all 10 rows to be covered, got %d", expectedStart-1)
+	}
+}
+
+func TestTableSplit_OversizeSplitMode(t *testing.T) {
+	config := DefaultConfig()
+	config.MaxChunkSize = 150
+	config.OversizeHandling = OversizeModeSplit
+
+	chunker := NewMarkdownChunkerWithConfig(config)
+	chunks, err := chunker.ChunkDocument([]byte(buildTableMarkdown(8)))
+	if err != nil {
+		t.Fatalf("ChunkDocument() error = %v", err)
+	}
+
+	parts := chunksOfType(chunks, "table")
+	if len(parts) < 2 {
+		t.Fatalf("Expected oversized table to be split, got %d parts", len(parts))
+	}
+	for i, part := range parts {
+		if !strings.HasPrefix(part.Content, "| Name | Score | Link |\n|:-----|") {
+			t.Errorf("Expected split-mode part %d to repeat header, got %q", i, part.Content)
+		}
+		if part.Metadata["table_part_total"] != fmt.Sprintf("%d", len(parts)) {
+			t.Errorf("Unexpected table_part_total for part %d: %v", i, part.Metadata)
+		}
+	}
+}
+
+func TestTableSplit_PositionsFromSource(t *testing.T) {
+	// 表格从第 5 行开始：表头、分隔行，数据行从第 7 行开始
+	markdown := "# Scores\n\nIntro text.\n\n" + buildTableMarkdown(6)
+
+	config := DefaultConfig()
+	config.SplitTables = true
+	config.TableMaxSize = 160
+
+	chunks, err := NewMarkdownChunkerWithConfig(config).ChunkDocument([]byte(markdown))
+	if err != nil {
+		t.Fatalf("ChunkDocument() error = %v", err)
+	}
+
+	parts := chunksOfType(chunks, "table")
+	if len(parts) < 2 {
+		t.Fatalf("Expected table to be split, got %d parts", len(parts))
+	}
+	for i, part := range parts {
+		rowStart, _ := strconv.Atoi(part.Metadata["row_start"])
+		rowEnd, _ := strconv.Atoi(part.Metadata["row_end"])
+		startLine := 6 + rowStart
+		if i == 0 {
+			startLine = 5
+		}
+		endLine := 6 + rowEnd
+		if part.Position.StartLine != startLine || part.Position.EndLine != endLine || part.Position.StartCol != 1 {
+			t.Errorf("Part %d: expected lines %d-%d, got %+v", i, startLine, endLine, part.Position)
+		}
+		lines := strings.Split(part.Content, "\n")
+		lastRow := lines[len(lines)-1]
+		if part.Position.EndCol != len(lastRow)+1 {
+			t.Errorf("Part %d: expected end column %d, got %d", i, len(lastRow)+1, part.Position.EndCol)
+		}
+		if part.Metadata["line_start"] != strconv.Itoa(startLine) || part.Metadata["line_end"] != strconv.Itoa(endLine) {
+			t.Errorf("Part %d: position metadata does not match the position: %v", i, part.Metadata)
+		}
+	}
+}
+
+func TestParseMarkdownTable(t *testing.T) {
+	table, ok := parseMarkdownTable("| a | b |\n| --- | :-: |\n| 1 | 2 |\n\n| 3 | 4 |")
+	if !ok {
+		t.Fatal("Expected table to be parsed")
+	}
+	if len(table.rows) != 2 || table.render(lineRange{1, 2}) != "| a | b |\n| --- | :-: |\n| 3 | 4 |" {
+		t.Errorf("Unexpected table %+v", table)
+	}
+
+	if _, ok := parseMarkdownTable("just text\nmore text"); ok {
+		t.Error("Expected plain text not to be parsed as a table")
+	}
+}
+
+func TestTableSplit_InvalidConfig(t *testing.T) {
+	config := DefaultConfig()
+	config.TableMaxSize = -1
+	if err := ValidateConfig(config); err == nil {
+		t.Error("Expected error for negative TableMaxSize")
+	}
+}
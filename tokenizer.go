@@ -0,0 +1,388 @@
+package markdownchunker
+
+import (
+	"bufio"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+)
+
+// SizeUnit 块大小的计量单位
+type SizeUnit int
+
+const (
+	// SizeUnitBytes 按字节计算大小（默认）
+	SizeUnitBytes SizeUnit = iota
+	// SizeUnitRunes 按 Unicode 字符计算大小
+	SizeUnitRunes
+	// SizeUnitTokens 按分词器产生的 token 数计算大小
+	SizeUnitTokens
+)
+
+// String 返回计量单位的字符串表示
+func (u SizeUnit) String() string {
+	switch u {
+	case SizeUnitBytes:
+		return "bytes"
+	case SizeUnitRunes:
+		return "runes"
+	case SizeUnitTokens:
+		return "tokens"
+	default:
+		return "unknown"
+	}
+}
+
+// Tokenizer 分词器接口
+type Tokenizer interface {
+	// Name 返回分词器名称
+	Name() string
+
+	// CountTokens 返回文本的 token 数
+	CountTokens(text string) int
+
+	// Tokenize 将文本切分为 token，每个 token 都是原文中按顺序出现的子串
+	Tokenize(text string) []string
+}
+
+// defaultTokenizer 未配置分词器时使用的默认分词器
+var defaultTokenizer Tokenizer = NewWhitespaceTokenizer()
+
+// WhitespaceTokenizer 空白字符与 CJK 字符分词器
+// 以空白字符分隔单词，每个 CJK 字符单独计为一个 token
+type WhitespaceTokenizer struct{}
+
+// NewWhitespaceTokenizer 创建空白字符与 CJK 字符分词器
+func NewWhitespaceTokenizer() *WhitespaceTokenizer {
+	return &WhitespaceTokenizer{}
+}
+
+// Name 返回分词器名称
+func (t *WhitespaceTokenizer) Name() string {
+	return "whitespace"
+}
+
+// CountTokens 返回文本的 token 数
+func (t *WhitespaceTokenizer) CountTokens(text string) int {
+	count := 0
+	inWord := false
+	for _, r := range text {
+		switch {
+		case unicode.IsSpace(r):
+			inWord = false
+		case isCJKRune(r):
+			count++
+			inWord = false
+		default:
+			if !inWord {
+				count++
+				inWord = true
+			}
+		}
+	}
+	return count
+}
+
+// Tokenize 将文本切分为 token
+func (t *WhitespaceTokenizer) Tokenize(text string) []string {
+	var tokens []string
+	wordStart := -1
+
+	for i, r := range text {
+		switch {
+		case unicode.IsSpace(r):
+			if wordStart >= 0 {
+				tokens = append(tokens, text[wordStart:i])
+				wordStart = -1
+			}
+		case isCJKRune(r):
+			if wordStart >= 0 {
+				tokens = append(tokens, text[wordStart:i])
+				wordStart = -1
+			}
+			tokens = append(tokens, text[i:i+utf8.RuneLen(r)])
+		default:
+			if wordStart < 0 {
+				wordStart = i
+			}
+		}
+	}
+	if wordStart >= 0 {
+		tokens = append(tokens, text[wordStart:])
+	}
+
+	return tokens
+}
+
+// isCJKRune 判断是否为中日韩字符
+func isCJKRune(r rune) bool {
+	return unicode.Is(unicode.Han, r) ||
+		unicode.Is(unicode.Hiragana, r) ||
+		unicode.Is(unicode.Katakana, r) ||
+		unicode.Is(unicode.Hangul, r)
+}
+
+// bpePreTokenizePattern GPT-2 风格的预分词正则
+var bpePreTokenizePattern = regexp.MustCompile(`'s|'t|'re|'ve|'m|'ll|'d| ?\p{L}+| ?\p{N}+| ?[^\s\p{L}\p{N}]+|\s+`)
+
+// BPETokenizer 基于字节级 BPE 合并规则的分词器
+// 合并规则文件格式与 GPT-2 的 merges.txt 相同：每行两个以空格分隔的符号，
+// 行号越小优先级越高，以 # 开头的行被忽略
+type BPETokenizer struct {
+	name        string
+	ranks       map[[2]string]int
+	byteEncoder [256]string
+	cache       map[string][]string
+	mutex       sync.RWMutex
+}
+
+// NewBPETokenizerFromFile 从本地合并规则文件创建 BPE 分词器
+func NewBPETokenizerFromFile(path string) (*BPETokenizer, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, NewChunkerError(ErrorTypeConfigInvalid, "无法打开 BPE 合并规则文件", err).
+			WithContext("function", "NewBPETokenizerFromFile").
+			WithContext("path", path)
+	}
+	defer file.Close()
+
+	tokenizer, err := NewBPETokenizerFromReader(file)
+	if err != nil {
+		if chunkerErr, ok := err.(*ChunkerError); ok {
+			return nil, chunkerErr.WithContext("path", path)
+		}
+		return nil, err
+	}
+	return tokenizer, nil
+}
+
+// NewBPETokenizerFromReader 从合并规则读取器创建 BPE 分词器
+func NewBPETokenizerFromReader(reader io.Reader) (*BPETokenizer, error) {
+	if reader == nil {
+		return nil, NewChunkerError(ErrorTypeConfigInvalid, "BPE 合并规则读取器不能为空", nil).
+			WithContext("function", "NewBPETokenizerFromReader")
+	}
+
+	ranks := make(map[[2]string]int)
+	scanner := bufio.NewScanner(reader)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	lineNumber := 0
+
+	for scanner.Scan() {
+		lineNumber++
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if strings.TrimSpace(line) == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		parts := strings.Split(line, " ")
+		if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+			return nil, NewChunkerError(ErrorTypeConfigInvalid, "无效的 BPE 合并规则", nil).
+				WithContext("function", "NewBPETokenizerFromReader").
+				WithContext("line_number", lineNumber).
+				WithContext("line", line)
+		}
+
+		pair := [2]string{parts[0], parts[1]}
+		if _, exists := ranks[pair]; !exists {
+			ranks[pair] = len(ranks)
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, NewChunkerError(ErrorTypeConfigInvalid, "读取 BPE 合并规则失败", err).
+			WithContext("function", "NewBPETokenizerFromReader").
+			WithContext("line_number", lineNumber)
+	}
+
+	return &BPETokenizer{
+		name:        "bpe",
+		ranks:       ranks,
+		byteEncoder: bytesToUnicode(),
+		cache:       make(map[string][]string),
+	}, nil
+}
+
+// Name 返回分词器名称
+func (t *BPETokenizer) Name() string {
+	return t.name
+}
+
+// MergeCount 返回合并规则的数量
+func (t *BPETokenizer) MergeCount() int {
+	return len(t.ranks)
+}
+
+// CountTokens 返回文本的 token 数
+func (t *BPETokenizer) CountTokens(text string) int {
+	count := 0
+	for _, word := range bpePreTokenizePattern.FindAllString(text, -1) {
+		count += len(t.encodeWord(word))
+	}
+	return count
+}
+
+// Tokenize 将文本切分为 token，多字节字符可能被拆分到相邻的 token 中
+func (t *BPETokenizer) Tokenize(text string) []string {
+	var tokens []string
+	for _, word := range bpePreTokenizePattern.FindAllString(text, -1) {
+		tokens = append(tokens, t.encodeWord(word)...)
+	}
+	return tokens
+}
+
+// encodeWord 对单个预分词结果应用 BPE 合并，返回原始字节形式的 token
+func (t *BPETokenizer) encodeWord(word string) []string {
+	t.mutex.RLock()
+	cached, ok := t.cache[word]
+	t.mutex.RUnlock()
+	if ok {
+		return cached
+	}
+
+	// 每个字节映射为一个可见符号，并记录其原始字节
+	symbols := make([]string, len(word))
+	raw := make([]string, len(word))
+	for i := 0; i < len(word); i++ {
+		symbols[i] = t.byteEncoder[word[i]]
+		raw[i] = word[i : i+1]
+	}
+
+	for len(symbols) > 1 {
+		bestRank := -1
+		bestIndex := -1
+		for i := 0; i < len(symbols)-1; i++ {
+			if rank, exists := t.ranks[[2]string{symbols[i], symbols[i+1]}]; exists && (bestRank < 0 || rank < bestRank) {
+				bestRank = rank
+				bestIndex = i
+			}
+		}
+		if bestIndex < 0 {
+			break
+		}
+
+		first, second := symbols[bestIndex], symbols[bestIndex+1]
+		var mergedSymbols, mergedRaw []string
+		for i := 0; i < len(symbols); i++ {
+			if i < len(symbols)-1 && symbols[i] == first && symbols[i+1] == second {
+				mergedSymbols = append(mergedSymbols, first+second)
+				mergedRaw = append(mergedRaw, raw[i]+raw[i+1])
+				i++
+				continue
+			}
+			mergedSymbols = append(mergedSymbols, symbols[i])
+			mergedRaw = append(mergedRaw, raw[i])
+		}
+		symbols, raw = mergedSymbols, mergedRaw
+	}
+
+	t.mutex.Lock()
+	if len(t.cache) < 100000 {
+		t.cache[word] = raw
+	}
+	t.mutex.Unlock()
+
+	return raw
+}
+
+// bytesToUnicode 构建 GPT-2 的字节到可见 Unicode 字符的映射
+func bytesToUnicode() [256]string {
+	var encoder [256]string
+	n := 0
+	for b := 0; b < 256; b++ {
+		if (b >= '!' && b <= '~') || (b >= 0xA1 && b <= 0xAC) || (b >= 0xAE && b <= 0xFF) {
+			encoder[b] = string(rune(b))
+			continue
+		}
+		encoder[b] = string(rune(256 + n))
+		n++
+	}
+	return encoder
+}
+
+// getTokenizer 返回配置的分词器，未配置时返回默认分词器
+func (c *MarkdownChunker) getTokenizer() Tokenizer {
+	if c.config != nil && c.config.Tokenizer != nil {
+		return c.config.Tokenizer
+	}
+	return defaultTokenizer
+}
+
+// countTokens 使用配置的分词器计算 token 数
+func (c *MarkdownChunker) countTokens(content string) int {
+	return c.getTokenizer().CountTokens(content)
+}
+
+// measureSize 按配置的计量单位计算内容大小
+func (c *MarkdownChunker) measureSize(content string) int {
+	if c == nil || c.config == nil {
+		return len(content)
+	}
+
+	switch c.config.SizeUnit {
+	case SizeUnitRunes:
+		return utf8.RuneCountInString(content)
+	case SizeUnitTokens:
+		return c.countTokens(content)
+	default:
+		return len(content)
+	}
+}
+
+// truncateToSize 按配置的计量单位截断内容，保证不会截断多字节字符
+func (c *MarkdownChunker) truncateToSize(content string, maxSize int) string {
+	if c == nil || c.config == nil {
+		return truncateUTF8(content, maxSize)
+	}
+
+	switch c.config.SizeUnit {
+	case SizeUnitRunes:
+		if maxSize <= 0 {
+			return ""
+		}
+		count := 0
+		for i := range content {
+			if count == maxSize {
+				return content[:i]
+			}
+			count++
+		}
+		return content
+	case SizeUnitTokens:
+		if c.countTokens(content) <= maxSize {
+			return content
+		}
+		end := tokenPrefixEnd(content, c.getTokenizer().Tokenize(content), maxSize)
+		return truncateUTF8(content, end)
+	default:
+		return truncateUTF8(content, maxSize)
+	}
+}
+
+// tokenPrefixEnd 返回前 n 个 token 在原文中的结束字节偏移
+func tokenPrefixEnd(content string, tokens []string, n int) int {
+	offset := 0
+	for i, token := range tokens {
+		if i == n {
+			break
+		}
+		index := strings.Index(content[offset:], token)
+		if index < 0 {
+			break
+		}
+		offset += index + len(token)
+	}
+	return offset
+}
+
+// measureChunkSize 使用分块器的计量单位计算内容大小，分块器为空时按字节计算
+func measureChunkSize(chunker *MarkdownChunker, content string) int {
+	if chunker == nil {
+		return len(content)
+	}
+	return chunker.measureSize(content)
+}
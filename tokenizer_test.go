@@ -0,0 +1,237 @@
+package markdownchunker
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestWhitespaceTokenizer(t *testing.T) {
+	tokenizer := NewWhitespaceTokenizer()
+
+	tests := []struct {
+		text     string
+		expected []string
+	}{
+		{"", nil},
+		{"hello world", []string{"hello", "world"}},
+		{"  spaced   out  ", []string{"spaced", "out"}},
+		{"中文分词", []string{"中", "文", "分", "词"}},
+		{"Go语言 test", []string{"Go", "语", "言", "test"}},
+	}
+
+	for _, test := range tests {
+		tokens := tokenizer.Tokenize(test.text)
+		if !reflect.DeepEqual(tokens, test.expected) {
+			t.Errorf("Tokenize(%q) = %q, expected %q", test.text, tokens, test.expected)
+		}
+		if count := tokenizer.CountTokens(test.text); count != len(test.expected) {
+			t.Errorf("CountTokens(%q) = %d, expected %d", test.text, count, len(test.expected))
+		}
+	}
+}
+
+func writeTestMerges(t *testing.T) string {
+	t.Helper()
+	merges := strings.Join([]string{
+		"#version: 0.2",
+		"h e",
+		"l l",
+		"he ll",
+		"hell o",
+		"Ġ w",
+		"o r",
+		"l d",
+		"Ġw or",
+		"Ġwor ld",
+	}, "\n")
+
+	path := filepath.Join(t.TempDir(), "merges.txt")
+	if err := os.WriteFile(path, []byte(merges), 0o644); err != nil {
+		t.Fatalf("failed to write merges file: %v", err)
+	}
+	return path
+}
+
+func TestBPETokenizer(t *testing.T) {
+	tokenizer, err := NewBPETokenizerFromFile(writeTestMerges(t))
+	if err != nil {
+		t.Fatalf("NewBPETokenizerFromFile() error = %v", err)
+	}
+
+	if tokenizer.Name() != "bpe" {
+		t.Errorf("Expected name bpe, got %s", tokenizer.Name())
+	}
+	if tokenizer.MergeCount() != 9 {
+		t.Errorf("Expected 9 merges, got %d", tokenizer.MergeCount())
+	}
+
+	tokens := tokenizer.Tokenize("hello world")
+	if !reflect.DeepEqual(tokens, []string{"hello", " world"}) {
+		t.Errorf("Expected [hello, world] tokens, got %q", tokens)
+	}
+	if count := tokenizer.CountTokens("hello world"); count != 2 {
+		t.Errorf("Expected 2 tokens, got %d", count)
+	}
+	if count := tokenizer.CountTokens("xyz"); count != 3 {
+		t.Errorf("Expected unmerged bytes to count individually, got %d", count)
+	}
+
+	// 多字节字符按字节拆分，拼接后应还原原文
+	text := "hello 世界"
+	if joined := strings.Join(tokenizer.Tokenize(text), ""); joined != text {
+		t.Errorf("Expected tokens to reconstruct text, got %q", joined)
+	}
+}
+
+func TestBPETokenizer_InvalidInput(t *testing.T) {
+	if _, err := NewBPETokenizerFromFile(filepath.Join(t.TempDir(), "missing.txt")); err == nil {
+		t.Error("Expected error for missing merges file")
+	}
+
+	if _, err := NewBPETokenizerFromReader(strings.NewReader("a b c\n")); err == nil {
+		t.Error("Expected error for malformed merge rule")
+	}
+}
+
+func TestSizeUnit_Tokens(t *testing.T) {
+	markdown := "# Title\n\n" + strings.Repeat("alpha beta gamma delta. ", 10)
+
+	config := DefaultConfig()
+	config.MaxChunkSize = 12
+	config.SizeUnit = SizeUnitTokens
+	config.OversizeHandling = OversizeModeSplit
+
+	chunker := NewMarkdownChunkerWithConfig(config)
+	chunks, err := chunker.ChunkDocument([]byte(markdown))
+	if err != nil {
+		t.Fatalf("ChunkDocument() error = %v", err)
+	}
+
+	tokenizer := NewWhitespaceTokenizer()
+	if len(chunks) < 4 {
+		t.Fatalf("Expected paragraph to be split by token count, got %d chunks", len(chunks))
+	}
+	for _, chunk := range chunks {
+		count := tokenizer.CountTokens(chunk.Content)
+		if count > config.MaxChunkSize {
+			t.Errorf("Chunk %d has %d tokens, exceeds limit %d", chunk.ID, count, config.MaxChunkSize)
+		}
+		if chunk.TokenCount != count {
+			t.Errorf("Chunk %d TokenCount = %d, expected %d", chunk.ID, chunk.TokenCount, count)
+		}
+	}
+}
+
+func TestSizeUnit_TokensTruncate(t *testing.T) {
+	config := DefaultConfig()
+	config.MaxChunkSize = 3
+	config.SizeUnit = SizeUnitTokens
+
+	chunker := NewMarkdownChunkerWithConfig(config)
+	chunks, err := chunker.ChunkDocument([]byte("one two three four five"))
+	if err != nil {
+		t.Fatalf("ChunkDocument() error = %v", err)
+	}
+
+	if len(chunks) != 1 || chunks[0].Content != "one two three" {
+		t.Errorf("Expected content truncated to 3 tokens, got %+v", chunks)
+	}
+}
+
+func TestSizeUnit_Runes(t *testing.T) {
+	config := DefaultConfig()
+	config.MaxChunkSize = 4
+	config.SizeUnit = SizeUnitRunes
+
+	chunker := NewMarkdownChunkerWithConfig(config)
+	chunks, err := chunker.ChunkDocument([]byte("中文内容测试"))
+	if err != nil {
+		t.Fatalf("ChunkDocument() error = %v", err)
+	}
+
+	if len(chunks) != 1 || chunks[0].Content != "中文内容" {
+		t.Errorf("Expected content truncated to 4 runes, got %+v", chunks)
+	}
+}
+
+func TestSizeUnit_CustomTokenizer(t *testing.T) {
+	tokenizer, err := NewBPETokenizerFromFile(writeTestMerges(t))
+	if err != nil {
+		t.Fatalf("NewBPETokenizerFromFile() error = %v", err)
+	}
+
+	config := DefaultConfig()
+	config.Tokenizer = tokenizer
+
+	chunker := NewMarkdownChunkerWithConfig(config)
+	chunks, err := chunker.ChunkDocument([]byte("hello world"))
+	if err != nil {
+		t.Fatalf("ChunkDocument() error = %v", err)
+	}
+
+	if len(chunks) != 1 || chunks[0].TokenCount != 2 {
+		t.Errorf("Expected 1 chunk with 2 BPE tokens, got %+v", chunks)
+	}
+}
+
+func TestSizeUnit_StrategySizeLimits(t *testing.T) {
+	markdown := "# 标题\n\n短段落。\n\n这是一个比较长的中文段落，用来测试按token计算的最小块大小。"
+
+	config := DefaultConfig()
+	config.SizeUnit = SizeUnitTokens
+	config.ChunkingStrategy = ElementLevelConfigWithSize(10, 0)
+
+	chunker := NewMarkdownChunkerWithConfig(config)
+	chunks, err := chunker.ChunkDocument([]byte(markdown))
+	if err != nil {
+		t.Fatalf("ChunkDocument() error = %v", err)
+	}
+
+	if len(chunks) != 1 || chunks[0].Type != "paragraph" || !strings.Contains(chunks[0].Content, "比较长") {
+		t.Errorf("Expected only the long paragraph to pass the token minimum, got %+v", chunks)
+	}
+}
+
+func TestHierarchicalStrategy_MaxChunkSizeKeepsSections(t *testing.T) {
+	markdown := `# Guide
+
+Intro paragraph.
+
+## Part One
+
+` + strings.Repeat("one ", 30) + `
+
+## Part Two
+
+` + strings.Repeat("two ", 30)
+
+	config := DefaultConfig()
+	config.SizeUnit = SizeUnitTokens
+	config.ChunkingStrategy = HierarchicalConfigWithSize(0, 0, 40)
+
+	chunker := NewMarkdownChunkerWithConfig(config)
+	chunks, err := chunker.ChunkDocument([]byte(markdown))
+	if err != nil {
+		t.Fatalf("ChunkDocument() error = %v", err)
+	}
+
+	// 只有自适应模式会下钻超大章节，默认层级策略保持整个章节
+	if len(chunks) != 1 {
+		t.Fatalf("Expected the section to stay a single chunk, got %d", len(chunks))
+	}
+	if !strings.Contains(chunks[0].Content, "Intro") || !strings.Contains(chunks[0].Content, "## Part Two") {
+		t.Errorf("Expected the whole section in one chunk, got %q", chunks[0].Content)
+	}
+}
+
+func TestValidateConfig_SizeUnit(t *testing.T) {
+	config := DefaultConfig()
+	config.SizeUnit = SizeUnit(42)
+
+	if err := ValidateConfig(config); err == nil {
+		t.Error("Expected error for invalid size unit")
+	}
+}
@@ -0,0 +1,174 @@
+package markdownchunker
+
+import "encoding/json"
+
+// DocumentTree 文档的完整层级树
+// 标题按层级嵌套，正文挂在最近的标题下；跳跃的标题层级和第一个标题之前的内容由虚拟节点承载
+type DocumentTree struct {
+	Roots []*HierarchicalChunk `json:"roots"` // 顶层节点
+}
+
+// IsVirtual 判断节点是否为虚拟节点
+func (h *HierarchicalChunk) IsVirtual() bool {
+	return h != nil && (h.Virtual || h.Chunk.Metadata["virtual"] == "true")
+}
+
+// Walk 以先序遍历节点及其子孙节点，depth 从 0 开始
+// fn 返回 false 时不再进入该节点的子节点
+func (h *HierarchicalChunk) Walk(fn func(node *HierarchicalChunk, depth int) bool) {
+	h.walk(fn, 0)
+}
+
+// walk 递归遍历节点
+func (h *HierarchicalChunk) walk(fn func(node *HierarchicalChunk, depth int) bool, depth int) {
+	if h == nil || !fn(h, depth) {
+		return
+	}
+	for _, child := range h.Children {
+		child.walk(fn, depth+1)
+	}
+}
+
+// Walk 按文档顺序先序遍历整棵树，depth 为节点在树中的深度，顶层节点为 0
+// fn 返回 false 时不再进入该节点的子节点
+func (t *DocumentTree) Walk(fn func(node *HierarchicalChunk, depth int) bool) {
+	if t == nil {
+		return
+	}
+	for _, root := range t.Roots {
+		root.walk(fn, 0)
+	}
+}
+
+// FindAll 返回所有满足条件的节点，按文档顺序排列
+func (t *DocumentTree) FindAll(match func(node *HierarchicalChunk) bool) []*HierarchicalChunk {
+	var nodes []*HierarchicalChunk
+	t.Walk(func(node *HierarchicalChunk, depth int) bool {
+		if match(node) {
+			nodes = append(nodes, node)
+		}
+		return true
+	})
+	return nodes
+}
+
+// Find 返回第一个满足条件的节点，没有时返回 nil
+func (t *DocumentTree) Find(match func(node *HierarchicalChunk) bool) *HierarchicalChunk {
+	var found *HierarchicalChunk
+	t.Walk(func(node *HierarchicalChunk, depth int) bool {
+		if found == nil && match(node) {
+			found = node
+		}
+		return found == nil
+	})
+	return found
+}
+
+// FindByID 按块 ID 查找非虚拟节点
+func (t *DocumentTree) FindByID(id int) *HierarchicalChunk {
+	return t.Find(func(node *HierarchicalChunk) bool {
+		return !node.IsVirtual() && node.Chunk.ID == id
+	})
+}
+
+// FindByAnchor 按标题锚点查找标题节点
+func (t *DocumentTree) FindByAnchor(anchor string) *HierarchicalChunk {
+	return t.Find(func(node *HierarchicalChunk) bool {
+		return node.Chunk.Type == "heading" && node.Chunk.Metadata["anchor"] == anchor
+	})
+}
+
+// NodesAtDepth 返回树中指定深度的所有节点，用于自行选择分块粒度
+func (t *DocumentTree) NodesAtDepth(depth int) []*HierarchicalChunk {
+	var nodes []*HierarchicalChunk
+	t.Walk(func(node *HierarchicalChunk, nodeDepth int) bool {
+		if nodeDepth == depth {
+			nodes = append(nodes, node)
+			return false
+		}
+		return true
+	})
+	return nodes
+}
+
+// UnmarshalJSON 反序列化树并恢复各节点的 Parent 引用
+// Parent 不参与序列化，避免循环引用导致 JSON 编码失败
+func (t *DocumentTree) UnmarshalJSON(data []byte) error {
+	type documentTree DocumentTree
+	var decoded documentTree
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return err
+	}
+
+	*t = DocumentTree(decoded)
+	t.restoreParents()
+	return nil
+}
+
+// restoreParents 根据子节点列表设置 Parent 引用
+func (t *DocumentTree) restoreParents() {
+	var restore func(parent *HierarchicalChunk, children []*HierarchicalChunk)
+	restore = func(parent *HierarchicalChunk, children []*HierarchicalChunk) {
+		for _, child := range children {
+			if child == nil {
+				continue
+			}
+			child.Parent = parent
+			restore(child, child.Children)
+		}
+	}
+	restore(nil, t.Roots)
+}
+
+// ChunkDocumentTree 对文档分块并返回完整的层级树，而不是按目标层级扁平化后的块
+// 节点中的块经过与 ChunkDocument 相同的后处理，调用方可以自行选择合并的层级
+func (c *MarkdownChunker) ChunkDocumentTree(content []byte) (*DocumentTree, error) {
+	logCtx := NewLogContext("ChunkDocumentTree").WithDocumentInfo(len(content), 0)
+	c.logWithContext("info", "开始构建文档层级树", logCtx)
+
+	c.performanceMonitor.Start()
+	defer c.performanceMonitor.Stop()
+	c.performanceMonitor.RecordBytes(int64(len(content)))
+
+	c.errorHandler.ClearErrors()
+
+	tree := &DocumentTree{Roots: []*HierarchicalChunk{}}
+
+	if content == nil {
+		err := NewChunkerError(ErrorTypeInvalidInput, "输入内容不能为空", nil).
+			WithContext("function", "ChunkDocumentTree")
+		c.errorHandler.HandleError(err)
+		return nil, err
+	}
+	if len(content) == 0 {
+		return tree, nil
+	}
+
+	doc, err := c.parseDocument(content)
+	if err != nil {
+		return nil, err
+	}
+	if doc == nil {
+		return tree, nil
+	}
+
+	// 与层级策略一致，先获取元素级基础块
+	baseChunks, err := NewElementLevelStrategy().ChunkDocument(doc, c.source, c)
+	if err != nil {
+		return nil, NewChunkerError(ErrorTypeStrategyExecutionFailed, "获取基础块失败", err).
+			WithContext("function", "ChunkDocumentTree")
+	}
+	chunks, err := c.postProcessChunks(baseChunks, len(c.source))
+	if err != nil {
+		return nil, err
+	}
+
+	tree.Roots = NewHierarchicalStrategy().buildHierarchy(chunks)
+	c.chunks = chunks
+
+	c.logWithContext("info", "完成文档层级树构建", logCtx.
+		WithMetadata("root_nodes", len(tree.Roots)).
+		WithMetadata("chunk_count", len(chunks)))
+
+	return tree, nil
+}
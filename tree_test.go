@@ -0,0 +1,130 @@
+package markdownchunker
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+const treeMarkdown = `Preamble.
+
+# Install
+
+Intro.
+
+### Deep Step
+
+Skipped a level.
+
+## Linux
+
+Run the script.
+
+# Usage
+
+Call the API.`
+
+func TestChunkDocumentTree(t *testing.T) {
+	chunker := NewMarkdownChunker()
+	tree, err := chunker.ChunkDocumentTree([]byte(treeMarkdown))
+	if err != nil {
+		t.Fatalf("ChunkDocumentTree() error = %v", err)
+	}
+
+	var outline []string
+	tree.Walk(func(node *HierarchicalChunk, depth int) bool {
+		label := node.Chunk.Text
+		if node.IsVirtual() {
+			label = "<" + node.Chunk.Type + ">"
+		}
+		outline = append(outline, strings.Repeat("  ", depth)+label)
+		return true
+	})
+
+	expected := []string{
+		"<root>",
+		"  Preamble.",
+		"Install",
+		"  Intro.",
+		"  <heading>",
+		"    Deep Step",
+		"      Skipped a level.",
+		"  Linux",
+		"    Run the script.",
+		"Usage",
+		"  Call the API.",
+	}
+	if strings.Join(outline, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("Unexpected outline:\n%s", strings.Join(outline, "\n"))
+	}
+
+	linux := tree.FindByAnchor("linux")
+	if linux == nil {
+		t.Fatal("Expected to find the Linux heading by anchor")
+	}
+	if linux.Parent == nil || linux.Parent.Chunk.Text != "Install" {
+		t.Errorf("Expected Linux to be nested under Install, got %+v", linux.Parent)
+	}
+	if found := tree.FindByID(linux.Chunk.ID); found != linux {
+		t.Errorf("Expected FindByID to return the Linux node, got %+v", found)
+	}
+
+	headings := tree.FindAll(func(node *HierarchicalChunk) bool {
+		return node.Chunk.Type == "heading" && !node.IsVirtual()
+	})
+	if len(headings) != 4 {
+		t.Errorf("Expected 4 real headings, got %d", len(headings))
+	}
+
+	if sections := tree.NodesAtDepth(1); len(sections) != 5 {
+		t.Errorf("Expected 5 nodes at depth 1, got %d", len(sections))
+	}
+}
+
+func TestDocumentTree_JSONRoundTrip(t *testing.T) {
+	chunker := NewMarkdownChunker()
+	tree, err := chunker.ChunkDocumentTree([]byte(treeMarkdown))
+	if err != nil {
+		t.Fatalf("ChunkDocumentTree() error = %v", err)
+	}
+
+	data, err := json.Marshal(tree)
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v", err)
+	}
+	if !strings.Contains(string(data), `"virtual":true`) {
+		t.Error("Expected virtual nodes to be marked in JSON")
+	}
+
+	var decoded DocumentTree
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+
+	step := decoded.Find(func(node *HierarchicalChunk) bool {
+		return node.Chunk.Text == "Skipped a level."
+	})
+	if step == nil {
+		t.Fatal("Expected to find the nested paragraph after decoding")
+	}
+	if step.Parent == nil || step.Parent.Chunk.Text != "Deep Step" ||
+		step.Parent.Parent == nil || !step.Parent.Parent.IsVirtual() {
+		t.Errorf("Expected Parent references to be restored, got %+v", step.Parent)
+	}
+	if decoded.Roots[0].Parent != nil {
+		t.Error("Expected root nodes to have no parent")
+	}
+}
+
+func TestChunkDocumentTree_EmptyAndNil(t *testing.T) {
+	chunker := NewMarkdownChunker()
+
+	tree, err := chunker.ChunkDocumentTree([]byte{})
+	if err != nil || len(tree.Roots) != 0 {
+		t.Errorf("Expected empty tree, got %+v, %v", tree, err)
+	}
+
+	if _, err := chunker.ChunkDocumentTree(nil); err == nil {
+		t.Error("Expected error for nil content")
+	}
+}